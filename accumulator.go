@@ -0,0 +1,300 @@
+package seclai
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/seclai/seclai-go/generated"
+)
+
+// RunAccumulator folds the events of a streaming run, or the snapshots of a
+// polled one, into the run's current state, and reports what each one
+// changed as a [RunDelta]. Feed it every event from
+// [Client.RunStreamingAgent], [Client.RunStreamingAgentEvents] or a
+// [StreamHandler] with Add, or every [Client.GetAgentRun] result with
+// AddRun:
+//
+//	acc := seclai.NewRunAccumulator()
+//	for evt, err := range client.RunStreamingAgentEvents(ctx, agentID, body) {
+//		if err != nil {
+//			return err
+//		}
+//		d := acc.Add(evt)
+//		fmt.Print(d.Output)
+//		for _, step := range d.CompletedSteps {
+//			fmt.Println("\nstep", step.AgentStepId, step.Status)
+//		}
+//	}
+//
+// Snapshots may be partial: a field an event leaves out keeps its value,
+// and steps and tool calls are merged by ID. A RunAccumulator is not safe
+// for concurrent use.
+type RunAccumulator struct {
+	run    AgentRunResponse
+	output string
+	done   bool
+
+	steps     []AgentRunStepResponse
+	stepIndex map[string]int
+	started   map[string]bool
+	completed map[string]bool
+	toolCalls map[string]bool
+}
+
+// RunDelta is what one event or snapshot changed in a [RunAccumulator].
+type RunDelta struct {
+	// Output is the text appended to the run's output, from stream_token
+	// events or a snapshot whose output extends the text seen so far.
+	Output string
+	// OutputReplaced reports that a snapshot's output did not extend the text
+	// seen so far. Output then holds the whole new output, and text printed
+	// from earlier deltas is stale.
+	OutputReplaced bool
+	// StartedSteps are the steps seen running for the first time. A step
+	// first seen already finished is in both StartedSteps and
+	// CompletedSteps.
+	StartedSteps []AgentRunStepResponse
+	// CompletedSteps are the steps seen finished, completed or failed, for
+	// the first time.
+	CompletedSteps []AgentRunStepResponse
+	// ToolCalls are the tool calls seen finished for the first time: each
+	// call in a step once it has an end time, a result or an error.
+	ToolCalls []RunToolCall
+}
+
+// RunToolCall is a tool call reported in a [RunDelta], with the run and step
+// that made it.
+type RunToolCall struct {
+	RunID       string `json:"run_id"`
+	AgentStepID string `json:"agent_step_id"`
+	AgentRunToolCallResponse
+}
+
+// NewRunAccumulator returns an empty [RunAccumulator].
+func NewRunAccumulator() *RunAccumulator {
+	return &RunAccumulator{
+		stepIndex: map[string]int{},
+		started:   map[string]bool{},
+		completed: map[string]bool{},
+		toolCalls: map[string]bool{},
+	}
+}
+
+// Run returns the current snapshot of the run, with the accumulated output
+// and every step seen so far.
+func (a *RunAccumulator) Run() AgentRunResponse {
+	run := a.run
+	if a.output != "" || run.Output != nil {
+		out := a.output
+		run.Output = &out
+	}
+	if len(a.steps) > 0 {
+		steps := a.steps
+		run.Steps = &steps
+	}
+	// A deep copy, so the snapshot does not change as events are added.
+	return cloneJSON(run)
+}
+
+// Output returns the run's output so far.
+func (a *RunAccumulator) Output() string {
+	return a.output
+}
+
+// Done reports whether the run has reached a terminal status.
+func (a *RunAccumulator) Done() bool {
+	return a.done
+}
+
+// Add folds a stream event into the run. Events without run data, such as
+// stream_end, error and reconnected, change nothing.
+func (a *RunAccumulator) Add(evt AgentRunEvent) RunDelta {
+	var d RunDelta
+	switch p := evt.Payload.(type) {
+	case *InitEvent, *UpdateEvent, *StatusEvent, *DoneEvent:
+		a.addSnapshot(&d, []byte(evt.Data))
+		if evt.Kind() == AgentRunEventDone {
+			a.done = true
+		}
+	case *StepEvent:
+		a.addStep(&d, p.AgentRunStepResponse, json.RawMessage(evt.Data))
+	case *StreamTokenEvent:
+		a.output += p.Token
+		d.Output = p.Token
+	}
+	return d
+}
+
+// AddRun folds a snapshot of the run, as returned by [Client.GetAgentRun],
+// into the run.
+func (a *RunAccumulator) AddRun(run *AgentRunResponse) RunDelta {
+	var d RunDelta
+	if run == nil {
+		return d
+	}
+	data, err := json.Marshal(run)
+	if err != nil {
+		return d
+	}
+	a.addSnapshot(&d, data)
+	return d
+}
+
+// addSnapshot merges a full or partial AgentRunResponse.
+func (a *RunAccumulator) addSnapshot(d *RunDelta, data []byte) {
+	// Decode onto a copy: json.Unmarshal writes through non-nil pointers,
+	// which earlier snapshots and deltas share.
+	next := cloneJSON(a.run)
+	if json.Unmarshal(data, &next) != nil {
+		return
+	}
+	var raw struct {
+		Output *string           `json:"output"`
+		Steps  []json.RawMessage `json:"steps"`
+	}
+	_ = json.Unmarshal(data, &raw)
+
+	next.Steps = nil
+	next.Output = a.run.Output
+	a.run = next
+	if isTerminalRunStatus(next.Status) {
+		a.done = true
+	}
+
+	// An empty output is no output yet, not a reason to drop streamed tokens.
+	if raw.Output != nil && *raw.Output != "" {
+		a.run.Output = raw.Output
+		if out := *raw.Output; strings.HasPrefix(out, a.output) {
+			d.Output += out[len(a.output):]
+		} else {
+			d.Output, d.OutputReplaced = out, true
+		}
+		a.output = *raw.Output
+	}
+	for _, rs := range raw.Steps {
+		var step AgentRunStepResponse
+		if json.Unmarshal(rs, &step) != nil || step.AgentStepId == "" {
+			continue
+		}
+		a.addStep(d, step, rs)
+	}
+}
+
+// addStep merges a step decoded from raw, the step's JSON in a snapshot or a
+// step event, so that fields it leaves out keep their values.
+func (a *RunAccumulator) addStep(d *RunDelta, step AgentRunStepResponse, raw json.RawMessage) {
+	id := step.AgentStepId
+	i := a.stepAt(id)
+	cur := cloneJSON(a.steps[i])
+	prevCalls := cur.ToolCalls
+	cur.ToolCalls = nil
+	_ = json.Unmarshal(raw, &cur)
+	var at []int
+	cur.ToolCalls, at = mergeToolCalls(prevCalls, step.ToolCalls)
+	a.steps[i] = cur
+
+	if step.ToolCalls != nil {
+		for j, call := range *step.ToolCalls {
+			if toolCallFinished(call) {
+				a.reportToolCall(d, RunToolCall{RunID: a.run.RunId, AgentStepID: id, AgentRunToolCallResponse: call}, at[j])
+			}
+		}
+	}
+
+	if !a.started[id] && stepStarted(cur) {
+		a.started[id] = true
+		d.StartedSteps = append(d.StartedSteps, cloneJSON(cur))
+	}
+	if !a.completed[id] && isTerminalRunStatus(cur.Status) {
+		a.completed[id] = true
+		d.CompletedSteps = append(d.CompletedSteps, cloneJSON(cur))
+	}
+}
+
+// stepAt returns the index of a step in a.steps, adding it if it is new.
+func (a *RunAccumulator) stepAt(id string) int {
+	i, ok := a.stepIndex[id]
+	if !ok {
+		i = len(a.steps)
+		a.stepIndex[id] = i
+		a.steps = append(a.steps, AgentRunStepResponse{AgentStepId: id})
+	}
+	return i
+}
+
+// reportToolCall adds a call to the delta unless it was reported before.
+// Calls without an ID are told apart by their step and their position n in
+// the step's merged calls.
+func (a *RunAccumulator) reportToolCall(d *RunDelta, ev RunToolCall, n int) {
+	key := ev.Id
+	if key == "" {
+		key = ev.AgentStepID + "#" + strconv.Itoa(n)
+	}
+	if a.toolCalls[key] {
+		return
+	}
+	a.toolCalls[key] = true
+	d.ToolCalls = append(d.ToolCalls, cloneJSON(ev))
+}
+
+// mergeToolCalls merges calls into cur by ID, replacing a known call and
+// appending a new one, and returns where each of calls ended up. Calls
+// without an ID are matched by their order among the calls without one, so
+// a snapshot repeating them does not add them again.
+func mergeToolCalls(cur, calls *[]AgentRunToolCallResponse) (*[]AgentRunToolCallResponse, []int) {
+	if calls == nil {
+		return cur, nil
+	}
+	var merged []AgentRunToolCallResponse
+	var unnamed []int
+	if cur != nil {
+		merged = append(merged, *cur...)
+		for i, call := range merged {
+			if call.Id == "" {
+				unnamed = append(unnamed, i)
+			}
+		}
+	}
+	at := make([]int, len(*calls))
+	for j, call := range *calls {
+		i := -1
+		if call.Id == "" {
+			if len(unnamed) > 0 {
+				i, unnamed = unnamed[0], unnamed[1:]
+			}
+		} else {
+			i = slices.IndexFunc(merged, func(m AgentRunToolCallResponse) bool { return m.Id == call.Id })
+		}
+		if i < 0 {
+			i = len(merged)
+			merged = append(merged, call)
+		} else {
+			merged[i] = call
+		}
+		at[j] = i
+	}
+	return &merged, at
+}
+
+// stepStarted reports whether a snapshot shows a step running or finished.
+func stepStarted(step AgentRunStepResponse) bool {
+	return step.StartedAt != nil ||
+		(step.Status != "" && step.Status != generated.PendingProcessingCompletedFailedStatusPending)
+}
+
+// toolCallFinished reports whether a snapshot shows a tool call finished.
+func toolCallFinished(call AgentRunToolCallResponse) bool {
+	return call.EndedAt != nil || call.Succeeded != nil || call.Error != nil || call.Output != nil
+}
+
+// cloneJSON deep-copies v by a JSON round trip, which is lossless for the
+// generated run types.
+func cloneJSON[T any](v T) T {
+	var out T
+	if data, err := json.Marshal(v); err == nil {
+		_ = json.Unmarshal(data, &out)
+	}
+	return out
+}
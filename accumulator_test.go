@@ -0,0 +1,169 @@
+package seclai
+
+import (
+	"testing"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func stepIDs(steps []AgentRunStepResponse) []string {
+	ids := make([]string, len(steps))
+	for i, s := range steps {
+		ids[i] = s.AgentStepId
+	}
+	return ids
+}
+
+func TestRunAccumulator_Output(t *testing.T) {
+	acc := NewRunAccumulator()
+	var out string
+	for _, evt := range []AgentRunEvent{
+		newAgentRunEvent("init", `{"run_id":"run_1","status":"pending","output":null}`, ""),
+		newAgentRunEvent("stream_token", `{"token":"Hel"}`, ""),
+		newAgentRunEvent("stream_token", `{"token":"lo"}`, ""),
+		// A partial snapshot keeps the fields it leaves out.
+		newAgentRunEvent("update", `{"status":"processing","output":""}`, ""),
+		newAgentRunEvent("update", `{"status":"processing","output":"Hello wor"}`, ""),
+		newAgentRunEvent("done", `{"run_id":"run_1","status":"completed","output":"Hello world"}`, ""),
+	} {
+		d := acc.Add(evt)
+		if d.OutputReplaced {
+			t.Fatalf("%s: unexpected replacement", evt.Event)
+		}
+		out += d.Output
+	}
+	if out != "Hello world" || acc.Output() != "Hello world" {
+		t.Fatalf("deltas gave %q, Output() = %q", out, acc.Output())
+	}
+	run := acc.Run()
+	if !acc.Done() || run.RunId != "run_1" || run.Status != "completed" || run.Output == nil || *run.Output != "Hello world" {
+		t.Fatalf("unexpected snapshot: %+v", run)
+	}
+
+	d := acc.AddRun(&AgentRunResponse{RunId: "run_1", Status: "completed", Output: ptr("Goodbye")})
+	if !d.OutputReplaced || d.Output != "Goodbye" {
+		t.Fatalf("expected the output to be replaced, got %+v", d)
+	}
+}
+
+func TestRunAccumulator_StreamSteps(t *testing.T) {
+	acc := NewRunAccumulator()
+	acc.Add(newAgentRunEvent("init", runProcessing, ""))
+
+	d := acc.Add(newAgentRunEvent("step", `{"run_id":"run_1","agent_step_id":"s1","status":"processing","step_type":"prompt_call"}`, ""))
+	if len(d.StartedSteps) != 1 || d.StartedSteps[0].StepType != "prompt_call" || len(d.CompletedSteps) != 0 {
+		t.Fatalf("started step: %+v", d)
+	}
+	d = acc.Add(newAgentRunEvent("step", `{"run_id":"run_1","agent_step_id":"s1","tool_calls":[{"id":"tc1","function_name":"search","ended_at":"t"}]}`, ""))
+	if len(d.ToolCalls) != 1 || d.ToolCalls[0].Id != "tc1" || d.ToolCalls[0].AgentStepID != "s1" || len(d.StartedSteps) != 0 {
+		t.Fatalf("tool call: %+v", d)
+	}
+	d = acc.Add(newAgentRunEvent("step", `{"run_id":"run_1","agent_step_id":"s1","status":"completed","output":"found"}`, ""))
+	if len(d.CompletedSteps) != 1 || len(d.StartedSteps) != 0 || len(d.ToolCalls) != 0 {
+		t.Fatalf("completed step: %+v", d)
+	}
+	if s := d.CompletedSteps[0]; s.StepType != "prompt_call" || s.Output == nil || *s.Output != "found" {
+		t.Fatalf("the completed step should merge all three events: %+v", s)
+	}
+
+	// A snapshot repeating what the events said reports nothing new.
+	d = acc.Add(newAgentRunEvent("done", `{"run_id":"run_1","status":"completed","steps":[
+		{"agent_step_id":"s1","status":"completed","tool_calls":[{"id":"tc1","function_name":"search","ended_at":"t"}]}]}`, ""))
+	if len(d.StartedSteps)+len(d.CompletedSteps)+len(d.ToolCalls) != 0 {
+		t.Fatalf("done: %+v", d)
+	}
+	run := acc.Run()
+	if run.Steps == nil || len(*run.Steps) != 1 {
+		t.Fatalf("steps = %+v", run.Steps)
+	}
+	s1 := (*run.Steps)[0]
+	if s1.StepType != "prompt_call" || s1.ToolCalls == nil || len(*s1.ToolCalls) != 1 || (*s1.ToolCalls)[0].EndedAt == nil {
+		t.Fatalf("unexpected step: %+v", s1)
+	}
+}
+
+func TestRunAccumulator_Polling(t *testing.T) {
+	acc := NewRunAccumulator()
+	d := acc.AddRun(&AgentRunResponse{RunId: "run_1", Status: "processing", Steps: &[]AgentRunStepResponse{
+		{AgentStepId: "s1", Status: "processing"},
+		{AgentStepId: "s2", Status: "pending"},
+	}})
+	if got := stepIDs(d.StartedSteps); len(got) != 1 || got[0] != "s1" {
+		t.Fatalf("started = %v", got)
+	}
+
+	d = acc.AddRun(&AgentRunResponse{RunId: "run_1", Status: "completed", Output: ptr("abc"), Steps: &[]AgentRunStepResponse{
+		{AgentStepId: "s1", Status: "completed"},
+		{AgentStepId: "s2", Status: "completed", ToolCalls: &[]AgentRunToolCallResponse{
+			{Id: "tc1", FunctionName: "search", Succeeded: ptr(true)},
+			{Id: "tc2", FunctionName: "fetch"},
+		}},
+	}})
+	if got := stepIDs(d.StartedSteps); len(got) != 1 || got[0] != "s2" {
+		t.Fatalf("started = %v", got)
+	}
+	if got := stepIDs(d.CompletedSteps); len(got) != 2 || got[0] != "s1" || got[1] != "s2" {
+		t.Fatalf("completed = %v", got)
+	}
+	// tc2 has not finished.
+	if len(d.ToolCalls) != 1 || d.ToolCalls[0].Id != "tc1" || d.ToolCalls[0].RunID != "run_1" {
+		t.Fatalf("tool calls = %+v", d.ToolCalls)
+	}
+	if d.Output != "abc" || !acc.Done() {
+		t.Fatalf("output %q, done %v", d.Output, acc.Done())
+	}
+}
+
+func TestRunAccumulator_PartialSnapshotsMergeToolCalls(t *testing.T) {
+	acc := NewRunAccumulator()
+	acc.Add(newAgentRunEvent("update", `{"run_id":"run_1","status":"processing","steps":[
+		{"agent_step_id":"s1","status":"processing","output":"a","tool_calls":[{"id":"t1","function_name":"f"},{"id":"t2","function_name":"g"}]}]}`, ""))
+	before := acc.Run()
+	d := acc.Add(newAgentRunEvent("update", `{"steps":[
+		{"agent_step_id":"s1","status":"completed","output":"b","tool_calls":[{"id":"t3","function_name":"h","ended_at":"t"}]}]}`, ""))
+
+	run := acc.Run()
+	calls := *(*run.Steps)[0].ToolCalls
+	if len(calls) != 3 || calls[0].Id != "t1" || calls[1].Id != "t2" || calls[2].Id != "t3" {
+		t.Fatalf("tool calls = %+v", calls)
+	}
+	if len(d.CompletedSteps) != 1 || len(*d.CompletedSteps[0].ToolCalls) != 3 {
+		t.Fatalf("completed = %+v", d.CompletedSteps)
+	}
+
+	// Earlier results do not change as the accumulator moves on.
+	s1 := (*before.Steps)[0]
+	if got := *s1.ToolCalls; len(got) != 2 || got[0].Id != "t1" || got[1].Id != "t2" {
+		t.Fatalf("an earlier Run() result changed: %+v", got)
+	}
+	if *s1.Output != "a" || s1.Status != "processing" {
+		t.Fatalf("an earlier Run() result changed: %+v", s1)
+	}
+	acc.Add(newAgentRunEvent("update", `{"steps":[{"agent_step_id":"s1","output":"c","tool_calls":[{"id":"t1","function_name":"f","ended_at":"u"}]}]}`, ""))
+	if s := d.CompletedSteps[0]; *s.Output != "b" || (*s.ToolCalls)[0].EndedAt != nil {
+		t.Fatalf("an earlier delta changed: %+v", s)
+	}
+}
+
+func TestRunAccumulator_ToolCallsWithoutIDsAreReportedOnce(t *testing.T) {
+	acc := NewRunAccumulator()
+	d := acc.Add(newAgentRunEvent("update", `{"run_id":"run_1","status":"processing","steps":[
+		{"agent_step_id":"s1","status":"processing","tool_calls":[{"id":"t1","function_name":"f"},{"function_name":"g","ended_at":"t"}]}]}`, ""))
+	if len(d.ToolCalls) != 1 || d.ToolCalls[0].FunctionName != "g" {
+		t.Fatalf("tool calls = %+v", d.ToolCalls)
+	}
+	// The call is first in this list but second in the step's merged calls.
+	d = acc.Add(newAgentRunEvent("step", `{"run_id":"run_1","agent_step_id":"s1","tool_calls":[{"function_name":"g","ended_at":"t"}]}`, ""))
+	if len(d.ToolCalls) != 0 {
+		t.Fatalf("the call was reported again: %+v", d.ToolCalls)
+	}
+	d = acc.Add(newAgentRunEvent("done", `{"run_id":"run_1","status":"completed","steps":[
+		{"agent_step_id":"s1","status":"completed","tool_calls":[{"id":"t1","function_name":"f","ended_at":"t"},{"function_name":"g","ended_at":"t"}]}]}`, ""))
+	if len(d.ToolCalls) != 1 || d.ToolCalls[0].Id != "t1" {
+		t.Fatalf("tool calls = %+v", d.ToolCalls)
+	}
+	run := acc.Run()
+	if calls := *(*run.Steps)[0].ToolCalls; len(calls) != 2 || calls[0].Id != "t1" || calls[1].FunctionName != "g" {
+		t.Fatalf("merged tool calls = %+v", calls)
+	}
+}
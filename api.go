@@ -0,0 +1,310 @@
+package seclai
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"net/http"
+)
+
+// API is the full method set of [Client] as an interface, so code that calls
+// the SDK can take a substitute in its tests. Most code needs only a few
+// methods; accept the narrowest of the grouped interfaces below that covers
+// them. The seclaimock package has a function-field mock of each.
+//
+// Methods may be added to these interfaces in minor releases, as they are to
+// Client. Embed the interface, or a seclaimock type, in a hand-written fake
+// rather than implementing every method.
+type API interface {
+	AccountAPI
+	AgentsAPI
+	RunsAPI
+	EvaluationsAPI
+	KnowledgeBasesAPI
+	MemoryBanksAPI
+	SourcesAPI
+	ContentAPI
+	SolutionsAPI
+	GovernanceAPI
+	AlertsAPI
+	ModelsAPI
+	EmailAPI
+	SearchAPI
+	AIAssistantAPI
+}
+
+// AccountAPI covers the caller's identity and the account's pinned API version.
+type AccountAPI interface {
+	GetMe(ctx context.Context) (*MeResponse, error)
+	Identity(ctx context.Context) (*Identity, error)
+	GetAPIVersion(ctx context.Context) (*ApiVersionResponse, error)
+	UpdateAPIVersion(ctx context.Context, version *string) (*ApiVersionResponse, error)
+}
+
+// AgentsAPI covers agents, their definitions and the agent AI assistant.
+type AgentsAPI interface {
+	ListAgents(ctx context.Context, opts ListOptions) (*AgentListResponse, error)
+	AllAgents(ctx context.Context, opts ListOptions) iter.Seq2[AgentSummaryResponse, error]
+	CreateAgent(ctx context.Context, body CreateAgentRequest) (*AgentSummaryResponse, error)
+	GetAgent(ctx context.Context, agentID string) (*AgentSummaryResponse, error)
+	UpdateAgent(ctx context.Context, agentID string, body UpdateAgentRequest) (*AgentSummaryResponse, error)
+	DeleteAgent(ctx context.Context, agentID string) error
+	DisableAgent(ctx context.Context, agentID string) (*AgentSummaryResponse, error)
+	EnableAgent(ctx context.Context, agentID string) (*AgentSummaryResponse, error)
+	GetAgentCallers(ctx context.Context, agentID string) ([]AgentCallerApiResponse, error)
+	ExportAgent(ctx context.Context, agentID string, download bool) (*AgentExportResponse, error)
+	PreviewImportAgent(ctx context.Context, body AgentImportPreviewRequest) (*AgentImportPreviewResponse, error)
+	GetAgentDefinition(ctx context.Context, agentID string) (*AgentDefinitionResponse, error)
+	UpdateAgentDefinition(ctx context.Context, agentID string, body UpdateAgentDefinitionRequest) (*AgentDefinitionResponse, error)
+	GenerateAgentSteps(ctx context.Context, agentID string, body GenerateAgentStepsRequest) (*GenerateAgentStepsResponse, error)
+	GenerateStepConfig(ctx context.Context, agentID string, body GenerateStepConfigRequest) (*GenerateStepConfigResponse, error)
+	GetAgentAiConversationHistory(ctx context.Context, agentID string) (*AiConversationHistoryResponse, error)
+	GetAgentAiConversationHistoryWithOptions(ctx context.Context, agentID string, opts AiConversationHistoryOptions) (*AiConversationHistoryResponse, error)
+	MarkAgentAiSuggestion(ctx context.Context, agentID, conversationID string, body MarkAiSuggestionRequest) error
+}
+
+// RunsAPI covers starting, streaming, polling and inspecting agent runs, and
+// the input uploads and attachments that go with them.
+type RunsAPI interface {
+	RunAgent(ctx context.Context, agentID string, body AgentRunRequest) (*AgentRunResponse, error)
+	RunAgentAndPoll(ctx context.Context, agentID string, body AgentRunRequest, opts *RunAgentAndPollOptions) (*AgentRunResponse, error)
+	RunStreamingAgent(ctx context.Context, agentID string, body AgentRunStreamRequest) (<-chan AgentRunEvent, <-chan error)
+	RunStreamingAgentAndWait(ctx context.Context, agentID string, body AgentRunStreamRequest) (*AgentRunResponse, error)
+	RunStreamingAgentWithHandler(ctx context.Context, agentID string, body AgentRunStreamRequest, h StreamHandler) error
+	RunStreamingAgentEvents(ctx context.Context, agentID string, body AgentRunStreamRequest) iter.Seq2[AgentRunEvent, error]
+	ListAgentRuns(ctx context.Context, agentID string, opts ListAgentRunsOptions) (*AgentRunListResponse, error)
+	AllAgentRuns(ctx context.Context, agentID string, opts ListAgentRunsOptions) iter.Seq2[AgentRunResponse, error]
+	SearchAgentRuns(ctx context.Context, body AgentTraceSearchRequest) (*AgentTraceSearchResponse, error)
+	GetAgentRun(ctx context.Context, runID string, opts *GetAgentRunOptions) (*AgentRunResponse, error)
+	DeleteAgentRun(ctx context.Context, runID string) error
+	CancelAgentRun(ctx context.Context, runID string) (*AgentRunResponse, error)
+	UploadAgentInput(ctx context.Context, agentID string, req UploadFileRequest) (*UploadAgentInputApiResponse, error)
+	GetAgentInputUploadStatus(ctx context.Context, agentID, uploadID string) (*UploadAgentInputApiResponse, error)
+	GetAgentAttachmentReferences(ctx context.Context, agentID string) (*AgentAttachmentRefsApiResponse, error)
+	DownloadAgentRunAttachment(ctx context.Context, runID, attachmentID, downloadName string) (*http.Response, error)
+}
+
+// EvaluationsAPI covers evaluation criteria, results and runs.
+type EvaluationsAPI interface {
+	ListEvaluationCriteria(ctx context.Context, agentID string, opts ListOptions) ([]EvaluationCriteriaResponse, error)
+	ListEvaluationCriteriaPage(ctx context.Context, agentID string, opts ListOptions) (*EvaluationCriteriaListResponse, error)
+	AllEvaluationCriteria(ctx context.Context, agentID string, opts ListOptions) iter.Seq2[EvaluationCriteriaResponse, error]
+	CreateEvaluationCriteria(ctx context.Context, agentID string, body CreateEvaluationCriteriaRequest) (*EvaluationCriteriaResponse, error)
+	GetEvaluationCriteria(ctx context.Context, criteriaID string) (*EvaluationCriteriaResponse, error)
+	UpdateEvaluationCriteria(ctx context.Context, criteriaID string, body UpdateEvaluationCriteriaRequest) (*EvaluationCriteriaResponse, error)
+	DeleteEvaluationCriteria(ctx context.Context, criteriaID string) error
+	GetEvaluationCriteriaSummary(ctx context.Context, criteriaID string) (*EvaluationResultSummaryResponse, error)
+	ListEvaluationResults(ctx context.Context, criteriaID string, opts ListOptions) (*EvaluationResultListResponse, error)
+	AllEvaluationResults(ctx context.Context, criteriaID string, opts ListOptions) iter.Seq2[EvaluationResultResponse, error]
+	CreateEvaluationResult(ctx context.Context, criteriaID string, body CreateEvaluationResultRequest) (*EvaluationResultResponse, error)
+	ListCompatibleRuns(ctx context.Context, criteriaID string, opts ListOptions) (*CompatibleRunListResponse, error)
+	AllCompatibleRuns(ctx context.Context, criteriaID string, opts ListOptions) iter.Seq2[CompatibleRunResponse, error]
+	TestDraftEvaluation(ctx context.Context, agentID string, body TestDraftEvaluationRequest) (*TestDraftEvaluationResponse, error)
+	ListAgentEvaluationResults(ctx context.Context, agentID string, opts ListOptions) (*EvaluationResultWithCriteriaListResponse, error)
+	AllAgentEvaluationResults(ctx context.Context, agentID string, opts ListOptions) iter.Seq2[EvaluationResultWithCriteriaResponse, error]
+	ListRunEvaluationResults(ctx context.Context, agentID, runID string, opts ListOptions) (*EvaluationResultWithCriteriaListResponse, error)
+	AllRunEvaluationResults(ctx context.Context, agentID, runID string, opts ListOptions) iter.Seq2[EvaluationResultWithCriteriaResponse, error]
+	ListEvaluationRuns(ctx context.Context, agentID string, opts ListOptions) (*EvaluationRunSummaryListResponse, error)
+	AllEvaluationRuns(ctx context.Context, agentID string, opts ListOptions) iter.Seq2[EvaluationRunSummaryResponse, error]
+	GetNonManualEvaluationSummary(ctx context.Context, agentID string) (*NonManualEvaluationSummaryResponse, error)
+}
+
+// KnowledgeBasesAPI covers knowledge bases.
+type KnowledgeBasesAPI interface {
+	ListKnowledgeBases(ctx context.Context, opts SortableListOptions) (*KnowledgeBaseListResponse, error)
+	AllKnowledgeBases(ctx context.Context, opts SortableListOptions) iter.Seq2[KnowledgeBaseResponse, error]
+	CreateKnowledgeBase(ctx context.Context, body CreateKnowledgeBaseBody) (*KnowledgeBaseResponse, error)
+	GetKnowledgeBase(ctx context.Context, knowledgeBaseID string) (*KnowledgeBaseResponse, error)
+	UpdateKnowledgeBase(ctx context.Context, knowledgeBaseID string, body UpdateKnowledgeBaseBody) (*KnowledgeBaseResponse, error)
+	DeleteKnowledgeBase(ctx context.Context, knowledgeBaseID string) error
+}
+
+// MemoryBanksAPI covers memory banks, their compaction and the memory bank AI
+// assistant.
+type MemoryBanksAPI interface {
+	ListMemoryBanks(ctx context.Context, opts SortableListOptions) (*MemoryBankListResponse, error)
+	AllMemoryBanks(ctx context.Context, opts SortableListOptions) iter.Seq2[MemoryBankResponse, error]
+	CreateMemoryBank(ctx context.Context, body CreateMemoryBankBody) (*MemoryBankResponse, error)
+	GetMemoryBank(ctx context.Context, memoryBankID string) (*MemoryBankResponse, error)
+	UpdateMemoryBank(ctx context.Context, memoryBankID string, body UpdateMemoryBankBody) (*MemoryBankResponse, error)
+	DeleteMemoryBank(ctx context.Context, memoryBankID string) error
+	GetAgentsUsingMemoryBank(ctx context.Context, memoryBankID string) (json.RawMessage, error)
+	GetMemoryBankStats(ctx context.Context, memoryBankID string) (json.RawMessage, error)
+	CompactMemoryBank(ctx context.Context, memoryBankID string) error
+	DeleteMemoryBankSource(ctx context.Context, memoryBankID string) error
+	TestMemoryBankCompaction(ctx context.Context, memoryBankID string, body TestCompactionRequest) (*CompactionTestResponse, error)
+	TestCompactionPromptStandalone(ctx context.Context, body StandaloneTestCompactionRequest) (*CompactionTestResponse, error)
+	ListMemoryBankTemplates(ctx context.Context) (json.RawMessage, error)
+	GenerateMemoryBankConfig(ctx context.Context, body MemoryBankAiAssistantRequest) (*MemoryBankAiAssistantResponse, error)
+	GetMemoryBankAiLastConversation(ctx context.Context) (*MemoryBankLastConversationResponse, error)
+	AcceptMemoryBankAiSuggestion(ctx context.Context, conversationID string, body MemoryBankAcceptRequest) (json.RawMessage, error)
+}
+
+// SourcesAPI covers sources, uploads into them, exports and embedding
+// migrations.
+type SourcesAPI interface {
+	ListSources(ctx context.Context, opts ListSourcesOptions) (*SourceListResponse, error)
+	AllSources(ctx context.Context, opts ListSourcesOptions) iter.Seq2[SourceResponse, error]
+	CreateSource(ctx context.Context, body CreateSourceBody) (*SourceResponse, error)
+	GetSource(ctx context.Context, sourceID string) (*SourceResponse, error)
+	UpdateSource(ctx context.Context, sourceID string, body UpdateSourceBody) (*SourceResponse, error)
+	DeleteSource(ctx context.Context, sourceID string) error
+	UploadFileToSource(ctx context.Context, sourceConnectionID string, req UploadFileRequest) (*FileUploadResponse, error)
+	UploadInlineTextToSource(ctx context.Context, sourceConnectionID string, body InlineTextUploadRequest) (*FileUploadResponse, error)
+	ListSourceExports(ctx context.Context, sourceID string, opts ListOptions) (*ExportListResponse, error)
+	AllSourceExports(ctx context.Context, sourceID string, opts ListOptions) iter.Seq2[ExportResponse, error]
+	CreateSourceExport(ctx context.Context, sourceID string, body CreateExportRequest) (*ExportResponse, error)
+	GetSourceExport(ctx context.Context, sourceID, exportID string) (*ExportResponse, error)
+	CancelSourceExport(ctx context.Context, sourceID, exportID string) (*ExportResponse, error)
+	DeleteSourceExport(ctx context.Context, sourceID, exportID string) error
+	DownloadSourceExport(ctx context.Context, sourceID, exportID string) (*http.Response, error)
+	EstimateSourceExport(ctx context.Context, sourceID string, body EstimateExportRequest) (*EstimateExportResponse, error)
+	GetSourceEmbeddingMigration(ctx context.Context, sourceID string) (*SourceEmbeddingMigrationResponse, error)
+	StartSourceEmbeddingMigration(ctx context.Context, sourceID string, body StartSourceEmbeddingMigrationRequest) (*SourceEmbeddingMigrationResponse, error)
+	CancelSourceEmbeddingMigration(ctx context.Context, sourceID string) (*SourceEmbeddingMigrationResponse, error)
+}
+
+// ContentAPI covers individual content items and their embeddings.
+type ContentAPI interface {
+	GetContentDetail(ctx context.Context, contentVersionID string, start, end int) (*ContentDetailResponse, error)
+	ReplaceContentWithInlineText(ctx context.Context, contentVersionID string, body InlineTextReplaceRequest) (*ContentFileUploadResponse, error)
+	UploadFileToContent(ctx context.Context, contentVersionID string, req UploadFileRequest) (*ContentFileUploadResponse, error)
+	DeleteContent(ctx context.Context, contentVersionID string) error
+	ListContentEmbeddings(ctx context.Context, contentVersionID string, opts ListOptions) (*ContentEmbeddingsListResponse, error)
+	AllContentEmbeddings(ctx context.Context, contentVersionID string, opts ListOptions) iter.Seq2[ContentEmbeddingResponse, error]
+}
+
+// SolutionsAPI covers solutions, the resources linked to them and the solution
+// AI assistant.
+type SolutionsAPI interface {
+	ListSolutions(ctx context.Context, opts SortableListOptions) (*SolutionListResponse, error)
+	AllSolutions(ctx context.Context, opts SortableListOptions) iter.Seq2[SolutionSummaryResponse, error]
+	CreateSolution(ctx context.Context, body CreateSolutionRequest) (*SolutionResponse, error)
+	GetSolution(ctx context.Context, solutionID string) (*SolutionResponse, error)
+	UpdateSolution(ctx context.Context, solutionID string, body UpdateSolutionRequest) (*SolutionResponse, error)
+	DeleteSolution(ctx context.Context, solutionID string) error
+	LinkAgentsToSolution(ctx context.Context, solutionID string, body LinkResourcesRequest) (*SolutionResponse, error)
+	UnlinkAgentsFromSolution(ctx context.Context, solutionID string, body UnlinkResourcesRequest) (*SolutionResponse, error)
+	LinkKnowledgeBasesToSolution(ctx context.Context, solutionID string, body LinkResourcesRequest) (*SolutionResponse, error)
+	UnlinkKnowledgeBasesFromSolution(ctx context.Context, solutionID string, body UnlinkResourcesRequest) (*SolutionResponse, error)
+	LinkSourceConnectionsToSolution(ctx context.Context, solutionID string, body LinkResourcesRequest) (*SolutionResponse, error)
+	UnlinkSourceConnectionsFromSolution(ctx context.Context, solutionID string, body UnlinkResourcesRequest) (*SolutionResponse, error)
+	ListSolutionConversations(ctx context.Context, solutionID string) ([]SolutionConversationResponse, error)
+	AddSolutionConversationTurn(ctx context.Context, solutionID string, body AddConversationTurnRequest) (*SolutionConversationResponse, error)
+	MarkSolutionConversationTurn(ctx context.Context, solutionID, conversationID string, body MarkConversationTurnRequest) error
+	GenerateSolutionAiPlan(ctx context.Context, solutionID string, body AiAssistantGenerateRequest) (*AiAssistantGenerateResponse, error)
+	GenerateSolutionAiKnowledgeBase(ctx context.Context, solutionID string, body AiAssistantGenerateRequest) (*AiAssistantGenerateResponse, error)
+	GenerateSolutionAiSource(ctx context.Context, solutionID string, body AiAssistantGenerateRequest) (*AiAssistantGenerateResponse, error)
+	AcceptSolutionAiPlan(ctx context.Context, solutionID, conversationID string, body AiAssistantAcceptRequest) (*AiAssistantAcceptResponse, error)
+	DeclineSolutionAiPlan(ctx context.Context, solutionID, conversationID string) error
+}
+
+// GovernanceAPI covers the governance AI assistant.
+type GovernanceAPI interface {
+	GenerateGovernanceAiPlan(ctx context.Context, body GovernanceAiAssistantRequest) (*GovernanceAiAssistantResponse, error)
+	ListGovernanceAiConversations(ctx context.Context) ([]GovernanceConversationResponse, error)
+	AcceptGovernanceAiPlan(ctx context.Context, conversationID string) (*GovernanceAiAcceptResponse, error)
+	DeclineGovernanceAiPlan(ctx context.Context, conversationID string) error
+}
+
+// AlertsAPI covers alerts, alert configs and organization alert preferences.
+type AlertsAPI interface {
+	ListAlerts(ctx context.Context, opts ListAlertsOptions) (json.RawMessage, error)
+	AllAlerts(ctx context.Context, opts ListAlertsOptions) iter.Seq2[AlertResponse, error]
+	GetAlert(ctx context.Context, alertID string) (json.RawMessage, error)
+	ChangeAlertStatus(ctx context.Context, alertID string, body ChangeStatusRequest) (json.RawMessage, error)
+	AddAlertComment(ctx context.Context, alertID string, body AddCommentRequest) (json.RawMessage, error)
+	SubscribeToAlert(ctx context.Context, alertID string) (json.RawMessage, error)
+	UnsubscribeFromAlert(ctx context.Context, alertID string) (json.RawMessage, error)
+	ListAlertConfigs(ctx context.Context, opts ListOptions) (json.RawMessage, error)
+	AllAlertConfigs(ctx context.Context, opts ListOptions) iter.Seq2[AlertConfigResponse, error]
+	CreateAlertConfig(ctx context.Context, body CreateAlertConfigRequest) (json.RawMessage, error)
+	GetAlertConfig(ctx context.Context, configID string) (json.RawMessage, error)
+	UpdateAlertConfig(ctx context.Context, configID string, body UpdateAlertConfigRequest) (json.RawMessage, error)
+	DeleteAlertConfig(ctx context.Context, configID string) error
+	ListOrganizationAlertPreferences(ctx context.Context) (*OrganizationAlertPreferenceListResponse, error)
+	UpdateOrganizationAlertPreference(ctx context.Context, organizationID, alertType string, body UpdateOrganizationAlertPreferenceRequest) (json.RawMessage, error)
+}
+
+// ModelsAPI covers models, model alerts, recommendations and experiments.
+type ModelsAPI interface {
+	ListModels(ctx context.Context, opts ListModelsOptions) ([]ProviderGroupResponse, error)
+	GetModel(ctx context.Context, modelID string) (*PromptModelResponse, error)
+	GetGenerationTiers(ctx context.Context) (json.RawMessage, error)
+	GetModelRecommendations(ctx context.Context, modelID string) (json.RawMessage, error)
+	ListModelAlerts(ctx context.Context, opts ListOptions) (json.RawMessage, error)
+	AllModelAlerts(ctx context.Context, opts ListOptions) iter.Seq2[ModelAlertResponse, error]
+	MarkModelAlertRead(ctx context.Context, alertID string) error
+	MarkAllModelAlertsRead(ctx context.Context) error
+	GetUnreadModelAlertCount(ctx context.Context) (json.RawMessage, error)
+	ListExperiments(ctx context.Context, opts ListExperimentsOptions) (json.RawMessage, error)
+	AllExperiments(ctx context.Context, opts ListExperimentsOptions) iter.Seq2[ExperimentSummaryResponse, error]
+	CreateExperiment(ctx context.Context, body PlaygroundCreateRequest) (json.RawMessage, error)
+	GetExperiment(ctx context.Context, experimentID string) (json.RawMessage, error)
+	CancelExperiment(ctx context.Context, experimentID string) (json.RawMessage, error)
+	DeleteExperiment(ctx context.Context, experimentID string) error
+}
+
+// EmailAPI covers agent email triggers, inbound email governance and email
+// domains.
+type EmailAPI interface {
+	SetEmailTriggerConfig(ctx context.Context, agentID string, triggerID string, body RoutersApiAgentsSetEmailTriggerConfigRequest) (*EmailTriggerConfigResponse, error)
+	ListAgentEmailOptOuts(ctx context.Context, opts AgentEmailOptOutOptions) (*AgentEmailOptOutListResponse, error)
+	AllAgentEmailOptOuts(ctx context.Context, opts AgentEmailOptOutOptions) iter.Seq2[AgentEmailOptOutResponse, error]
+	RemoveAgentEmailOptOut(ctx context.Context, optoutID string) error
+	ListBlockedEmailSenders(ctx context.Context, opts BlockedEmailSenderOptions) (*BlockedEmailSenderListResponse, error)
+	AllBlockedEmailSenders(ctx context.Context, opts BlockedEmailSenderOptions) iter.Seq2[BlockedEmailSenderResponse, error]
+	BlockEmailSender(ctx context.Context, body BlockEmailSenderRequest) (*BlockedEmailSenderResponse, error)
+	UnblockEmailSender(ctx context.Context, blockedID string) error
+	SetAutoBlockMode(ctx context.Context, body SetAutoBlockModeRequest) (*BlockedEmailSenderListResponse, error)
+	ListInboundEmailRejections(ctx context.Context, opts InboundEmailRejectionOptions) ([]InboundEmailRejectionResponse, error)
+	GetInboundEmailStatus(ctx context.Context) (*InboundEmailStatusResponse, error)
+	CancelQueuedEmailRuns(ctx context.Context) (*CancelQueuedRunsResponse, error)
+	ResumeInboundEmail(ctx context.Context) (*ResumeInboundResponse, error)
+	ListEmailDomains(ctx context.Context) (*EmailDomainsListResponse, error)
+	AddEmailDomain(ctx context.Context, body AddEmailDomainRequest) (*EmailDomainResponse, error)
+	RemoveEmailDomain(ctx context.Context, domainID string) (*RemoveEmailDomainResponse, error)
+	VerifyEmailDomain(ctx context.Context, domainID string) (*EmailDomainResponse, error)
+	SetPrimaryEmailDomain(ctx context.Context, domainID string) (*EmailDomainResponse, error)
+	UseSharedEmailDomain(ctx context.Context) error
+	SendEmailDomainTestEmail(ctx context.Context, domainID string) (*SendTestEmailResponse, error)
+	GetDmarcSummary(ctx context.Context, domainID string, opts DmarcOptions) (*DmarcSummaryResponse, error)
+}
+
+// SearchAPI covers search and documentation search.
+type SearchAPI interface {
+	Search(ctx context.Context, opts SearchOptions) (json.RawMessage, error)
+	SearchDocs(ctx context.Context, opts DocsSearchOptions) (json.RawMessage, error)
+}
+
+// AIAssistantAPI covers the top-level AI assistant.
+type AIAssistantAPI interface {
+	AiAssistantKnowledgeBase(ctx context.Context, body AiAssistantGenerateRequest) (*AiAssistantGenerateResponse, error)
+	AiAssistantSource(ctx context.Context, body AiAssistantGenerateRequest) (*AiAssistantGenerateResponse, error)
+	AiAssistantSolution(ctx context.Context, body AiAssistantGenerateRequest) (*AiAssistantGenerateResponse, error)
+	AiAssistantMemoryBank(ctx context.Context, body MemoryBankAiAssistantRequest) (*MemoryBankAiAssistantResponse, error)
+	GetAiAssistantMemoryBankHistory(ctx context.Context) (*MemoryBankLastConversationResponse, error)
+	AcceptAiAssistantPlan(ctx context.Context, conversationID string, body AiAssistantAcceptRequest) (*AiAssistantAcceptResponse, error)
+	DeclineAiAssistantPlan(ctx context.Context, conversationID string) error
+	AcceptAiMemoryBankSuggestion(ctx context.Context, conversationID string, body MemoryBankAcceptRequest) (json.RawMessage, error)
+	SubmitAiFeedback(ctx context.Context, body AiAssistantFeedbackRequest) (*AiAssistantFeedbackResponse, error)
+}
+
+var (
+	_ API               = (*Client)(nil)
+	_ AccountAPI        = (*Client)(nil)
+	_ AgentsAPI         = (*Client)(nil)
+	_ RunsAPI           = (*Client)(nil)
+	_ EvaluationsAPI    = (*Client)(nil)
+	_ KnowledgeBasesAPI = (*Client)(nil)
+	_ MemoryBanksAPI    = (*Client)(nil)
+	_ SourcesAPI        = (*Client)(nil)
+	_ ContentAPI        = (*Client)(nil)
+	_ SolutionsAPI      = (*Client)(nil)
+	_ GovernanceAPI     = (*Client)(nil)
+	_ AlertsAPI         = (*Client)(nil)
+	_ ModelsAPI         = (*Client)(nil)
+	_ EmailAPI          = (*Client)(nil)
+	_ SearchAPI         = (*Client)(nil)
+	_ AIAssistantAPI    = (*Client)(nil)
+)
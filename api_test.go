@@ -0,0 +1,21 @@
+package seclai
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAPI_CoversEveryClientMethod(t *testing.T) {
+	// Kept in step with cmd/mockgen, which refuses to generate mocks while a
+	// method is missing.
+	unmocked := map[string]bool{"Do": true, "Generated": true, "Typed": true, "Settings": true, "Close": true}
+
+	api := reflect.TypeOf((*API)(nil)).Elem()
+	client := reflect.TypeOf(&Client{})
+	for i := 0; i < client.NumMethod(); i++ {
+		name := client.Method(i).Name
+		if _, ok := api.MethodByName(name); !ok && !unmocked[name] {
+			t.Errorf("Client.%s is in none of the interfaces in api.go", name)
+		}
+	}
+}
@@ -7,7 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
@@ -39,7 +39,8 @@ type SsoCacheEntry struct {
 	RefreshToken string `json:"refreshToken,omitempty"`
 	// IDToken is the OIDC ID token (optional).
 	IDToken string `json:"idToken,omitempty"`
-	// ExpiresAt is the ISO-8601 expiry timestamp for the access token.
+	// ExpiresAt is the ISO-8601 expiry timestamp for the access token. When
+	// it is missing or corrupt the access token's exp claim is used.
 	ExpiresAt string `json:"expiresAt"`
 	// ClientID is the Cognito app client ID.
 	ClientID string `json:"clientId"`
@@ -57,6 +58,7 @@ const (
 	authModeBearerStatic
 	authModeBearerProvider
 	authModeSSO
+	authModeCredentialProcess
 )
 
 // authState holds resolved auth for the client lifetime.
@@ -70,8 +72,27 @@ type authState struct {
 	ssoProfile    *SsoProfile
 	configDir     string
 	autoRefresh   bool
+	store         TokenStore
 	httpClient    *http.Client
+	logger        *slog.Logger
 	refreshMu     sync.Mutex
+
+	credentialProcess string
+	credentialMu      sync.Mutex
+	credential        *processCredential
+
+	// provider is the AccessTokenProvider's token while the background
+	// refresher renews it.
+	providerMu sync.Mutex
+	provider   *providerToken
+}
+
+// tokenStore returns the configured store, or the file store in configDir.
+func (s *authState) tokenStore() TokenStore {
+	if s.store != nil {
+		return s.store
+	}
+	return &FileTokenStore{configDir: s.configDir}
 }
 
 const (
@@ -161,50 +182,99 @@ func ParseIni(r io.Reader) map[string]map[string]string {
 // Always returns a valid profile — missing config values fall back to
 // environment variable overrides (SECLAI_SSO_DOMAIN, SECLAI_SSO_CLIENT_ID,
 // SECLAI_SSO_REGION), then built-in production defaults.
+//
+// A profile missing from the config file is reported to [slog.Default].
 func LoadSsoProfile(configDir, profileName string) (*SsoProfile, error) {
+	return loadSsoProfile(configDir, profileName, slog.Default())
+}
+
+// loadSsoProfile is [LoadSsoProfile] reporting to logger.
+func loadSsoProfile(configDir, profileName string, logger *slog.Logger) (*SsoProfile, error) {
+	section, err := loadProfileSection(configDir, profileName, logger)
+	if err != nil {
+		return nil, err
+	}
+	return ssoProfileFromSection(section), nil
+}
+
+// loadProfileSection reads a profile's keys from the config file, inheriting
+// from [default]. A missing config file yields an empty section.
+func loadProfileSection(configDir, profileName string, logger *slog.Logger) (map[string]string, error) {
+	p, err := loadConfigProfile(configDir, profileName, logger)
+	if err != nil {
+		return nil, err
+	}
+	return p.values, nil
+}
+
+// configProfile is a profile section of the config file after inheritance,
+// remembering which keys came from [default].
+type configProfile struct {
+	name string
+	dir  string
+	// path is the config file, empty when there is none.
+	path   string
+	values map[string]string
+	// inherited holds the keys taken from [default].
+	inherited map[string]bool
+	// missing is set when the config file exists but has no section for
+	// name.
+	missing bool
+}
+
+// loadConfigProfile reads a profile from the config file, inheriting from
+// [default]. A missing config file yields an empty profile.
+func loadConfigProfile(configDir, profileName string, logger *slog.Logger) (*configProfile, error) {
 	configPath := filepath.Join(configDir, ssoConfigFile)
 	f, err := os.Open(configPath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			// No config file — return profile with defaults
-			return &SsoProfile{
-				SsoDomain:   envOrDefault("SECLAI_SSO_DOMAIN", "", DefaultSsoDomain),
-				SsoClientID: envOrDefault("SECLAI_SSO_CLIENT_ID", "", DefaultSsoClientID),
-				SsoRegion:   envOrDefault("SECLAI_SSO_REGION", "", DefaultSsoRegion),
-			}, nil
+			// No config file — the profile is all defaults
+			return &configProfile{name: profileName, dir: configDir, values: map[string]string{}, inherited: map[string]bool{}}, nil
 		}
 		return nil, err
 	}
 	defer f.Close()
 
-	sections := ParseIni(f)
+	p := profileFromSections(ParseIni(f), profileName, logger)
+	p.dir, p.path = configDir, configPath
+	return p, nil
+}
+
+// profileFromSections resolves a profile from parsed config sections,
+// inheriting from [default].
+func profileFromSections(sections map[string]map[string]string, profileName string, logger *slog.Logger) *configProfile {
+	p := &configProfile{name: profileName, values: map[string]string{}, inherited: map[string]bool{}}
 
 	defaultSection := sections["default"]
 	if defaultSection == nil {
 		defaultSection = map[string]string{}
 	}
 
-	var section map[string]string
 	if profileName == "default" {
-		section = defaultSection
-	} else {
-		section = sections[profileName]
-		if section == nil {
-			log.Printf("seclai: SSO profile %q not found in config; using defaults", profileName)
-			section = defaultSection
-		} else {
-			// Inherit from default
-			merged := make(map[string]string)
-			for k, v := range defaultSection {
-				merged[k] = v
-			}
-			for k, v := range section {
-				merged[k] = v
-			}
-			section = merged
-		}
+		p.values = defaultSection
+		return p
+	}
+	section := sections[profileName]
+	if section == nil {
+		logger.Warn("seclai: SSO profile not found in config; using defaults", slog.String("profile", profileName))
+		section = map[string]string{}
+		p.missing = true
+	}
+	// Inherit from default
+	for k, v := range defaultSection {
+		p.values[k] = v
+		p.inherited[k] = true
+	}
+	for k, v := range section {
+		p.values[k] = v
+		delete(p.inherited, k)
 	}
+	return p
+}
 
+// ssoProfileFromSection resolves the SSO settings of a profile section.
+func ssoProfileFromSection(section map[string]string) *SsoProfile {
 	accountID := section["sso_account_id"]
 
 	// Apply env var overrides, then built-in defaults
@@ -217,7 +287,7 @@ func LoadSsoProfile(configDir, profileName string) (*SsoProfile, error) {
 		SsoRegion:    region,
 		SsoClientID:  clientID,
 		SsoDomain:    domain,
-	}, nil
+	}
 }
 
 // envOrDefault returns the env var value if set, else configVal if non-empty, else fallback.
@@ -256,8 +326,20 @@ func ReadSsoCache(configDir string, profile *SsoProfile) (*SsoCacheEntry, error)
 	return &entry, nil
 }
 
-// WriteSsoCache atomically writes a cache entry.
+// WriteSsoCache atomically writes a cache entry. It holds the cache's
+// cross-process lock while writing, so it never lands in the middle of
+// another process's refresh.
 func WriteSsoCache(configDir string, profile *SsoProfile, entry *SsoCacheEntry) error {
+	unlock, err := lockSsoCache(context.Background(), configDir, profile)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return writeSsoCache(configDir, profile, entry)
+}
+
+// writeSsoCache is [WriteSsoCache] for a caller already holding the lock.
+func writeSsoCache(configDir string, profile *SsoProfile, entry *SsoCacheEntry) error {
 	cacheDir := filepath.Join(configDir, ssoCacheDir)
 	if err := os.MkdirAll(cacheDir, 0700); err != nil {
 		return err
@@ -268,21 +350,37 @@ func WriteSsoCache(configDir string, profile *SsoProfile, entry *SsoCacheEntry)
 	if err != nil {
 		return err
 	}
+	return writeFileAtomic(cachePath, data)
+}
 
-	tmpFile := cachePath + ".tmp"
-	if err := os.WriteFile(tmpFile, data, 0600); err != nil {
+// writeFileAtomic writes data to path with mode 0600 via a uniquely named
+// temp file in the same directory, synced and then renamed over path, so a
+// reader or a failed write never sees a partial file and concurrent writers
+// do not share a temp file.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
 		return err
 	}
-	// Remove destination first for Windows compatibility (os.Rename fails if dest exists).
-	os.Remove(cachePath)
-	if err := os.Rename(tmpFile, cachePath); err != nil {
-		os.Remove(tmpFile) // clean up orphaned temp file
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
 		return err
 	}
 	return nil
 }
 
-// DeleteSsoCache removes a cached token file.
+// DeleteSsoCache removes a cached token file. The tokens stay valid at
+// Cognito; [Logout] revokes them first.
 func DeleteSsoCache(configDir string, profile *SsoProfile) error {
 	cachePath := ssoCachePath(configDir, profile)
 	err := os.Remove(cachePath)
@@ -295,16 +393,28 @@ func DeleteSsoCache(configDir string, profile *SsoProfile) error {
 // ── Token validation ────────────────────────────────────────────────────────
 
 // IsTokenValid checks if a cached token is still valid (with 30s buffer).
+// When ExpiresAt is missing or corrupt, the access token's exp claim is used
+// instead.
 func IsTokenValid(entry *SsoCacheEntry) bool {
-	t, err := time.Parse(time.RFC3339, entry.ExpiresAt)
+	t, ok := entry.expiry()
+	if !ok {
+		return false
+	}
+	return time.Now().Add(expiryBuffer).Before(t)
+}
+
+// expiry parses ExpiresAt, falling back to the access token's exp claim.
+// ok is false when neither gives an expiry.
+func (e *SsoCacheEntry) expiry() (t time.Time, ok bool) {
+	t, err := time.Parse(time.RFC3339, e.ExpiresAt)
 	if err != nil {
 		// Try RFC3339Nano
-		t, err = time.Parse(time.RFC3339Nano, entry.ExpiresAt)
+		t, err = time.Parse(time.RFC3339Nano, e.ExpiresAt)
 		if err != nil {
-			return false
+			return jwtExpiry(e.AccessToken)
 		}
 	}
-	return time.Now().Add(expiryBuffer).Before(t)
+	return t, true
 }
 
 // ── Token refresh ───────────────────────────────────────────────────────────
@@ -370,14 +480,14 @@ func RefreshToken(ctx context.Context, profile *SsoProfile, refreshToken string,
 
 // ── Credential chain ────────────────────────────────────────────────────────
 
-// resolveCredentialChain resolves the credential chain from Options
-// and returns an authState for the client lifetime. First match wins:
-// explicit APIKey → AccessToken → AccessTokenProvider → SECLAI_API_KEY env → SSO profile.
-func resolveCredentialChain(opts Options) (*authState, error) {
-	header := strings.TrimSpace(opts.APIKeyHeader)
-	if header == "" {
-		header = "x-api-key"
-	}
+// resolveCredentialChain resolves the credential chain from Options and the
+// client's profile, and returns an authState for the client lifetime and the
+// setting that supplied it. First match wins: explicit APIKey → AccessToken →
+// AccessTokenProvider → SECLAI_API_KEY env → the profile's api_key,
+// api_key_file or credential_process → SSO profile.
+func resolveCredentialChain(opts Options, prof *configProfile) (*authState, Setting, error) {
+	logger := loggerOrDiscard(opts.Logger)
+	header, _ := resolveAPIKeyHeader(opts, prof)
 
 	// Mutual exclusion
 	hasAPIKey := strings.TrimSpace(opts.APIKey) != ""
@@ -395,7 +505,7 @@ func resolveCredentialChain(opts Options) (*authState, error) {
 		authCount++
 	}
 	if authCount > 1 {
-		return nil, &ConfigurationError{Message: "provide only one of APIKey, AccessToken, or AccessTokenProvider"}
+		return nil, Setting{}, &ConfigurationError{Message: "provide only one of APIKey, AccessToken, or AccessTokenProvider"}
 	}
 
 	// 1. Explicit API key
@@ -404,8 +514,9 @@ func resolveCredentialChain(opts Options) (*authState, error) {
 			mode:         authModeAPIKey,
 			apiKey:       strings.TrimSpace(opts.APIKey),
 			apiKeyHeader: header,
+			logger:       logger,
 			accountID:    opts.AccountID,
-		}, nil
+		}, optionSetting(settingCredentials, "api_key", "APIKey"), nil
 	}
 
 	// 2. Static access token
@@ -414,8 +525,9 @@ func resolveCredentialChain(opts Options) (*authState, error) {
 			mode:         authModeBearerStatic,
 			accessToken:  strings.TrimSpace(opts.AccessToken),
 			apiKeyHeader: header,
+			logger:       logger,
 			accountID:    opts.AccountID,
-		}, nil
+		}, optionSetting(settingCredentials, "access_token", "AccessToken"), nil
 	}
 
 	// 3. Provider
@@ -424,8 +536,9 @@ func resolveCredentialChain(opts Options) (*authState, error) {
 			mode:          authModeBearerProvider,
 			tokenProvider: opts.AccessTokenProvider,
 			apiKeyHeader:  header,
+			logger:        logger,
 			accountID:     opts.AccountID,
-		}, nil
+		}, optionSetting(settingCredentials, "access_token_provider", "AccessTokenProvider"), nil
 	}
 
 	// 4. SECLAI_API_KEY env var
@@ -434,44 +547,64 @@ func resolveCredentialChain(opts Options) (*authState, error) {
 			mode:         authModeAPIKey,
 			apiKey:       envKey,
 			apiKeyHeader: header,
+			logger:       logger,
 			accountID:    opts.AccountID,
-		}, nil
+		}, Setting{Name: settingCredentials, Value: "api_key", Source: SourceEnv, Detail: "SECLAI_API_KEY"}, nil
 	}
 
-	// 5. SSO profile
-	configDir := resolveConfigDir(opts.ConfigDir)
-	if configDir != "" {
-		profileName := opts.Profile
-		if profileName == "" {
-			profileName = os.Getenv("SECLAI_PROFILE")
-		}
-		if profileName == "" {
-			profileName = "default"
-		}
-		ssoProfile, err := LoadSsoProfile(configDir, profileName)
-		if err == nil && ssoProfile != nil {
-			acctID := opts.AccountID
-			if acctID == "" {
-				acctID = ssoProfile.SsoAccountID
-			}
-			autoRefresh := true
-			if opts.AutoRefresh != nil {
-				autoRefresh = *opts.AutoRefresh
-			}
-			return &authState{
-				mode:         authModeSSO,
-				apiKeyHeader: header,
-				accountID:    acctID,
-				ssoProfile:   ssoProfile,
-				configDir:    configDir,
-				autoRefresh:  autoRefresh,
-				httpClient:   opts.HTTPClient,
-			}, nil
-		}
+	// 5. The profile
+	if prof.dir == "" {
+		return nil, Setting{}, &ConfigurationError{Message: "missing credentials: provide Options.APIKey, Options.AccessToken, set SECLAI_API_KEY, or run `seclai auth login`"}
 	}
-
-	// 6. Nothing
-	return nil, &ConfigurationError{Message: "missing credentials: provide Options.APIKey, Options.AccessToken, set SECLAI_API_KEY, or run `seclai auth login`"}
+	acctID := opts.AccountID
+	if acctID == "" {
+		acctID = prof.values["sso_account_id"]
+	}
+	key, err := prof.credentialKey()
+	if err != nil {
+		return nil, Setting{}, err
+	}
+	switch key {
+	case "api_key", "api_key_file":
+		// 5a. A static API key
+		apiKey, err := prof.apiKey(key)
+		if err != nil {
+			return nil, Setting{}, err
+		}
+		return &authState{
+			mode:         authModeAPIKey,
+			apiKey:       apiKey,
+			apiKeyHeader: header,
+			logger:       logger,
+			accountID:    acctID,
+		}, prof.setting(settingCredentials, key, "api_key"), nil
+	case "credential_process":
+		// 5b. The profile's credential helper
+		return &authState{
+			mode:              authModeCredentialProcess,
+			credentialProcess: prof.values["credential_process"],
+			apiKeyHeader:      header,
+			logger:            logger,
+			accountID:         acctID,
+		}, prof.setting(settingCredentials, key, "credential_process"), nil
+	}
+
+	// 6. SSO
+	autoRefresh := true
+	if opts.AutoRefresh != nil {
+		autoRefresh = *opts.AutoRefresh
+	}
+	return &authState{
+		mode:         authModeSSO,
+		apiKeyHeader: header,
+		logger:       logger,
+		accountID:    acctID,
+		ssoProfile:   ssoProfileFromSection(prof.values),
+		configDir:    prof.dir,
+		autoRefresh:  autoRefresh,
+		store:        opts.TokenStore,
+		httpClient:   opts.HTTPClient,
+	}, prof.ssoSetting(), nil
 }
 
 // resolveAuthHeaders returns the auth headers for a given request.
@@ -484,6 +617,10 @@ func resolveAuthHeaders(ctx context.Context, state *authState) (map[string]strin
 	case authModeBearerStatic:
 		headers["Authorization"] = "Bearer " + state.accessToken
 	case authModeBearerProvider:
+		if token, ok := state.validProviderToken(); ok {
+			headers["Authorization"] = "Bearer " + token
+			break
+		}
 		token, err := state.tokenProvider(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("access token provider error: %w", err)
@@ -495,6 +632,16 @@ func resolveAuthHeaders(ctx context.Context, state *authState) (map[string]strin
 			return nil, err
 		}
 		headers["Authorization"] = "Bearer " + token
+	case authModeCredentialProcess:
+		cred, err := resolveProcessCredential(ctx, state)
+		if err != nil {
+			return nil, err
+		}
+		if cred.APIKey != "" {
+			headers[state.apiKeyHeader] = cred.APIKey
+		} else {
+			headers["Authorization"] = "Bearer " + cred.AccessToken
+		}
 	}
 
 	if state.accountID != "" {
@@ -505,40 +652,74 @@ func resolveAuthHeaders(ctx context.Context, state *authState) (map[string]strin
 }
 
 // resolveSsoToken resolves a valid SSO token, refreshing from cache if needed.
-// Uses a mutex to prevent concurrent refresh attempts.
+// refreshMu keeps this client's goroutines to one refresh at a time, and the
+// cache file lock does the same across processes sharing the cache: with
+// refresh-token rotation, a second concurrent refresh would spend a refresh
+// token the first has already replaced.
 func resolveSsoToken(ctx context.Context, state *authState) (string, error) {
-	cached, err := ReadSsoCache(state.configDir, state.ssoProfile)
+	store := state.tokenStore()
+	cached, err := store.Get(ctx, state.ssoProfile)
 	if err != nil {
 		return "", err
 	}
 	if cached == nil {
-		return "", &ConfigurationError{Message: "No cached SSO token found. Run `seclai auth login` to authenticate via SSO."}
+		return "", &ConfigurationError{Message: "No cached SSO token found. Run `seclai auth login`, or call seclai.LoginWithDeviceCode, to authenticate via SSO."}
 	}
 	if IsTokenValid(cached) {
 		return cached.AccessToken, nil
 	}
 	if cached.RefreshToken != "" && state.autoRefresh {
-		state.refreshMu.Lock()
-		defer state.refreshMu.Unlock()
-		// Re-check after acquiring lock — another goroutine may have refreshed
-		cached, err = ReadSsoCache(state.configDir, state.ssoProfile)
+		refreshed, err := refreshSsoToken(ctx, state, cached)
 		if err != nil {
 			return "", err
 		}
-		if cached != nil && IsTokenValid(cached) {
-			return cached.AccessToken, nil
-		}
-		if cached != nil && cached.RefreshToken != "" {
-			hc := state.httpClient
-			refreshed, err := RefreshToken(ctx, state.ssoProfile, cached.RefreshToken, hc)
-			if err != nil {
-				return "", fmt.Errorf("token refresh failed: %w", err)
-			}
-			if err := WriteSsoCache(state.configDir, state.ssoProfile, refreshed); err != nil {
-				return "", err
-			}
+		if refreshed != nil {
 			return refreshed.AccessToken, nil
 		}
 	}
-	return "", &ConfigurationError{Message: "SSO token is missing or has expired. Run `seclai auth login` to authenticate."}
+	return "", &ConfigurationError{Message: "SSO token is missing or has expired. Run `seclai auth login`, or call seclai.LoginWithDeviceCode, to authenticate."}
+}
+
+// refreshSsoToken replaces stale, the entry last read from the store. It
+// holds the locks from re-reading the store to storing the new entry, and
+// returns another goroutine's or process's replacement instead when one
+// arrived meanwhile. It returns nil when there is no refresh token.
+func refreshSsoToken(ctx context.Context, state *authState, stale *SsoCacheEntry) (*SsoCacheEntry, error) {
+	store := state.tokenStore()
+	state.refreshMu.Lock()
+	defer state.refreshMu.Unlock()
+	if locker, ok := store.(TokenLocker); ok {
+		unlock, err := locker.Lock(ctx, state.ssoProfile)
+		if err != nil {
+			return nil, fmt.Errorf("SSO token refresh: %w", err)
+		}
+		defer unlock()
+	}
+	// Re-check after acquiring the locks — another goroutine or process
+	// may have refreshed
+	cached, err := store.Get(ctx, state.ssoProfile)
+	if err != nil {
+		return nil, err
+	}
+	if cached != nil && cached.AccessToken != stale.AccessToken && IsTokenValid(cached) {
+		return cached, nil
+	}
+	if cached == nil || cached.RefreshToken == "" {
+		return nil, nil
+	}
+	logger := loggerOrDiscard(state.logger)
+	logger.InfoContext(ctx, "seclai: refreshing SSO token", slog.String("domain", state.ssoProfile.SsoDomain))
+	refreshed, err := RefreshToken(ctx, state.ssoProfile, cached.RefreshToken, state.httpClient)
+	if err != nil {
+		// Cognito error bodies can echo the request's parameters.
+		redact := newRedactor(state)
+		redact.secrets = append(redact.secrets, cached.RefreshToken)
+		logger.WarnContext(ctx, "seclai: SSO token refresh failed", slog.String("error", redact.string(err.Error())))
+		return nil, fmt.Errorf("token refresh failed: %w", err)
+	}
+	if err := store.Put(ctx, state.ssoProfile, refreshed); err != nil {
+		return nil, err
+	}
+	logger.InfoContext(ctx, "seclai: SSO token refreshed", slog.String("expires_at", refreshed.ExpiresAt))
+	return refreshed, nil
 }
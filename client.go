@@ -1,25 +1,25 @@
 package seclai
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"mime"
 	"mime/multipart"
 	"net/http"
 	"net/textproto"
 	"net/url"
-	"os"
 	"path"
 	"path/filepath"
-	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/seclai/seclai-go/generated"
+	"github.com/seclai/seclai-go/sse"
 )
 
 // DefaultBaseURL is the default API base URL.
@@ -41,28 +41,49 @@ type Options struct {
 	// AccessTokenProvider returns a bearer token per request (mutually exclusive with APIKey).
 	AccessTokenProvider AccessTokenProvider
 
-	// Profile selects an SSO profile from the config file.
-	// Defaults to the SECLAI_PROFILE environment variable, then "default".
+	// Profile selects a profile from the config file, which can supply
+	// credentials, SSO settings and the base_url, api_version,
+	// api_key_header, timeout and default_headers settings. Defaults to the
+	// SECLAI_PROFILE environment variable, then "default".
 	Profile string
 
 	// ConfigDir overrides the config directory path.
 	// Defaults to the SECLAI_CONFIG_DIR environment variable, then ~/.seclai.
 	ConfigDir string
 
+	// TokenStore holds the SSO profile's tokens. Defaults to the plaintext
+	// file cache in ConfigDir; see [NewMemoryTokenStore] and
+	// [NewEncryptedFileTokenStore] to keep refresh tokens out of plaintext
+	// files.
+	TokenStore TokenStore
+
 	// AutoRefresh controls whether expired SSO tokens are automatically refreshed.
 	// Defaults to true. Set to a pointer to false to disable.
 	AutoRefresh *bool
 
+	// BackgroundRefresh renews SSO and provider tokens ahead of expiry from
+	// a background goroutine, which [Client.Close] stops. Nil, the default,
+	// refreshes only when a request finds the token expired.
+	BackgroundRefresh *RefreshPolicy
+
+	// StreamReconnect makes [Client.RunStreamingAgent] follow a run whose
+	// stream drops before the done event, by polling the run. Nil, the
+	// default, reports the dropped stream's error.
+	StreamReconnect *StreamReconnectPolicy
+
 	// AccountID is sent as the X-Account-Id header for multi‑org targeting.
 	AccountID string
 
-	// BaseURL is the API base URL. Defaults to SECLAI_API_URL if set, else DefaultBaseURL.
+	// BaseURL is the API base URL. Defaults to SECLAI_API_URL if set, then
+	// the profile's base_url, else DefaultBaseURL.
 	BaseURL string
 
-	// APIKeyHeader is the HTTP header name used for the API key. Defaults to "x-api-key".
+	// APIKeyHeader is the HTTP header name used for the API key. Defaults to
+	// the profile's api_key_header, then "x-api-key".
 	APIKeyHeader string
 
-	// DefaultHeaders are HTTP headers applied to every request.
+	// DefaultHeaders are HTTP headers applied to every request. Each one
+	// replaces the profile's default_headers entry of the same name.
 	DefaultHeaders map[string]string
 
 	// AllowUnknownAPIVersion permits an APIVersion this release was not built
@@ -87,10 +108,38 @@ type Options struct {
 	// From 2026-07-27 the API rejects undeclared query parameters with a 422
 	// rather than ignoring them, and list endpoints return the canonical
 	// {data, pagination} envelope.
+	//
+	// Defaults to the profile's api_version.
 	APIVersion string
 
-	// HTTPClient is used for requests. Defaults to a client with a 30s timeout.
+	// HTTPClient is used for requests. Defaults to a client with the
+	// profile's timeout, else 30s.
 	HTTPClient *http.Client
+
+	// Retry controls retries of failed requests. Nil disables retries; use
+	// [DefaultRetryPolicy] for the recommended settings. A retried 429 waits
+	// out its Retry-After header.
+	Retry *RetryPolicy
+
+	// RateLimiter paces every request this client sends, retries included.
+	// Share one limiter between clients to pace them together. Nil sends
+	// requests as fast as they are made; see [NewTokenBucket].
+	RateLimiter RateLimiter
+
+	// Middleware wraps every request this client sends, in order: the first
+	// entry sees the request first and the response last.
+	Middleware []Middleware
+
+	// Logger receives the client's logs: requests and responses at debug
+	// level, retries and SSO token refreshes at info, and stream lifecycle
+	// events at debug. Bodies are logged only at [LevelBody]. Credentials —
+	// API keys, bearer and refresh tokens, and the x-api-key and
+	// Authorization headers — are always redacted. Nil disables logging.
+	Logger *slog.Logger
+
+	// LogBodyLimit caps how many bytes of each body are logged at
+	// [LevelBody]. Defaults to 4 KiB.
+	LogBodyLimit int
 }
 
 // Client is the Seclai Go SDK client.
@@ -98,93 +147,99 @@ type Client struct {
 	auth           *authState
 	baseURL        *url.URL
 	defaultHeaders map[string]string
-	httpClient     *http.Client
+	transport      http.RoundTripper
+	retry          *RetryPolicy
+	limiter        RateLimiter
+	logger         *slog.Logger
+	logBodyLimit   int
+	redact         redactor
+	settings       []Setting
+	refresher      *refresher
+	closeOnce      sync.Once
+	reconnect      *StreamReconnectPolicy
 
 	generated *generated.ClientWithResponses
 }
 
 // NewClient constructs a new Client.
 //
+// Each setting comes from Options, then the environment, then the profile,
+// then the built-in default; [Client.Settings] reports which.
+//
 // Returns ConfigurationError if credentials are missing or if the base URL is invalid.
 func NewClient(opts Options) (*Client, error) {
-	state, err := resolveCredentialChain(opts)
+	prof, profileSetting := resolveProfile(opts, loggerOrDiscard(opts.Logger))
+	state, credSetting, err := resolveCredentialChain(opts, prof)
 	if err != nil {
 		return nil, err
 	}
+	_, headerSetting := resolveAPIKeyHeader(opts, prof)
 
-	base := strings.TrimSpace(opts.BaseURL)
-	if base == "" {
-		base = strings.TrimSpace(os.Getenv("SECLAI_API_URL"))
-	}
-	if base == "" {
-		base = DefaultBaseURL
-	}
+	base, baseSetting := resolveBaseURL(opts, prof)
 	parsed, err := url.Parse(base)
 	if err != nil {
 		return nil, &ConfigurationError{Message: fmt.Sprintf("invalid base URL: %v", err)}
 	}
 
-	hc := opts.HTTPClient
-	if hc == nil {
-		hc = &http.Client{Timeout: 30 * time.Second}
+	hc, timeoutSetting, err := resolveHTTPClient(opts, prof)
+	if err != nil {
+		return nil, err
 	}
 	state.httpClient = hc
 
-	defHeaders := make(map[string]string, len(opts.DefaultHeaders)+1)
-	// Set first so an explicit DefaultHeaders entry still wins. Omitted unless
-	// the caller opts in: with no header the account's pinned baseline applies.
-	if opts.APIVersion != "" {
-		defHeaders["Seclai-Version"] = opts.APIVersion
-	}
-	// DefaultHeaders may carry several spellings of one header. Sort the keys so
-	// the survivor does not depend on Go's randomised map order, and drop any
-	// differently-cased duplicate so only one value reaches the wire.
-	headerKeys := make([]string, 0, len(opts.DefaultHeaders))
-	for k := range opts.DefaultHeaders {
-		headerKeys = append(headerKeys, k)
-	}
-	sort.Strings(headerKeys)
-	for _, k := range headerKeys {
-		for existing := range defHeaders {
-			if strings.EqualFold(existing, k) && existing != k {
-				delete(defHeaders, existing)
-			}
-		}
-		defHeaders[k] = opts.DefaultHeaders[k]
+	// Omitted unless the caller or profile opts in: with no Seclai-Version
+	// header the account's pinned baseline applies.
+	headers, err := resolveHeaders(opts, prof)
+	if err != nil {
+		return nil, err
 	}
+	defHeaders := headers.values
 
 	// Validate what the merge produced, not what the options said. Inspecting the
 	// options would have to predict which spelling survives — and with a map that
 	// is not even deterministic, so the guard could approve one value and the
 	// client send another.
-	effectiveVersion, versionSource := "", "Options.APIVersion"
-	for k, v := range defHeaders {
-		if strings.EqualFold(k, "Seclai-Version") {
-			effectiveVersion = v
-			if v != opts.APIVersion {
-				versionSource = fmt.Sprintf("Options.DefaultHeaders[%q]", k)
-			}
-			break
-		}
-	}
-	if effectiveVersion != "" && !opts.AllowUnknownAPIVersion && !isKnownAPIVersion(effectiveVersion) {
+	redact := newRedactor(state)
+	versionSetting, headerSettings := headers.settings(redact)
+	if versionSetting != nil && !opts.AllowUnknownAPIVersion && !isKnownAPIVersion(versionSetting.Value) {
 		return nil, &ConfigurationError{Message: fmt.Sprintf(
 			"unknown API version %q (via %s): this release was built against %s. A "+
 				"newer API version can change response shapes, which this client would "+
 				"decode incorrectly rather than reject. Upgrade the module, or set "+
 				"Options.AllowUnknownAPIVersion to proceed anyway.",
-			effectiveVersion, versionSource, strings.Join(KnownAPIVersions, ", "))}
+			versionSetting.Value, versionSetting.Detail, strings.Join(KnownAPIVersions, ", "))}
+	}
+
+	settings := []Setting{profileSetting, baseSetting}
+	if versionSetting != nil {
+		settings = append(settings, *versionSetting)
 	}
+	settings = append(settings, headerSetting, credSetting, timeoutSetting)
+	settings = append(settings, headerSettings...)
+	logSettings(state.logger, settings)
 
 	client := &Client{
 		auth:           state,
 		baseURL:        parsed,
 		defaultHeaders: defHeaders,
-		httpClient:     hc,
+		retry:          opts.Retry,
+		limiter:        opts.RateLimiter,
+		logger:         state.logger,
+		logBodyLimit:   opts.LogBodyLimit,
+		redact:         redact,
+		settings:       settings,
+	}
+	if client.logBodyLimit <= 0 {
+		client.logBodyLimit = defaultLogBodyLimit
+	}
+	if opts.StreamReconnect != nil {
+		p := opts.StreamReconnect.normalized()
+		client.reconnect = &p
 	}
+	client.transport = chainMiddleware(client.logTransport(roundTripperFunc(hc.Do)), opts.Middleware)
 
 	gen, err := generated.NewClientWithResponses(parsed.String(),
-		generated.WithHTTPClient(hc),
+		generated.WithHTTPClient(doerFunc(client.send)),
 		generated.WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
 			for k, v := range defHeaders {
 				req.Header.Set(k, v)
@@ -197,6 +252,9 @@ func NewClient(opts Options) (*Client, error) {
 	}
 	client.generated = gen
 
+	if opts.BackgroundRefresh != nil {
+		client.refresher = startRefresher(state, *opts.BackgroundRefresh)
+	}
 	return client, nil
 }
 
@@ -210,6 +268,27 @@ func (c *Client) Generated() *generated.ClientWithResponses {
 	return c.generated
 }
 
+// doerFunc adapts a function to the generated client's HttpRequestDoer.
+type doerFunc func(req *http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) { return f(req) }
+
+// newRequest builds a request to the API with the default headers and auth
+// applied. Callers add the content headers for their own body.
+func (c *Client) newRequest(ctx context.Context, method string, reqURL *url.URL, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, reqURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range c.defaultHeaders {
+		req.Header.Set(k, v)
+	}
+	if err := c.applyAuth(ctx, req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
 // applyAuth resolves auth headers and applies them to a request.
 func (c *Client) applyAuth(ctx context.Context, req *http.Request) error {
 	hdrs, err := resolveAuthHeaders(ctx, c.auth)
@@ -242,17 +321,10 @@ func (c *Client) Do(ctx context.Context, method, apiPath string, query map[strin
 		reqBody = bytes.NewReader(b)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, reqURL.String(), reqBody)
+	req, err := c.newRequest(ctx, method, reqURL, reqBody)
 	if err != nil {
 		return err
 	}
-
-	for k, v := range c.defaultHeaders {
-		req.Header.Set(k, v)
-	}
-	if err := c.applyAuth(ctx, req); err != nil {
-		return err
-	}
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
@@ -264,25 +336,16 @@ func (c *Client) Do(ctx context.Context, method, apiPath string, query map[strin
 		req.Header.Set(k, v)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	raw, _ := io.ReadAll(resp.Body)
-	text := strings.TrimSpace(string(raw))
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		statusErr := APIStatusError{StatusCode: resp.StatusCode, Method: method, URL: reqURL.String(), ResponseText: text}
-		if resp.StatusCode == 422 {
-			var ve HTTPValidationError
-			if len(raw) > 0 && json.Unmarshal(raw, &ve) == nil && ve.Detail != nil {
-				return &APIValidationError{APIStatusError: statusErr, ValidationError: &ve}
-			}
-			return &APIValidationError{APIStatusError: statusErr}
-		}
-		return &statusErr
+		return newAPIError(method, reqURL.String(), resp, raw)
 	}
 
 	if out == nil {
@@ -307,6 +370,58 @@ func (c *Client) GetMe(ctx context.Context) (*MeResponse, error) {
 	return &out, nil
 }
 
+// Identity is who a client is authenticated as: the API's view from
+// [Client.GetMe], and the claims of the client's token.
+type Identity struct {
+	// Me is the authenticated user's account and organizations.
+	Me *MeResponse
+	// Claims are the claims of the client's SSO or bearer token, decoded
+	// without verification. Nil for an API key, or a bearer token that is
+	// not a JWT.
+	Claims *TokenClaims
+}
+
+// Identity returns who the client is authenticated as, combining [Client.GetMe]
+// with the claims of its token, such as the email address and expiry of an
+// SSO login.
+func (c *Client) Identity(ctx context.Context) (*Identity, error) {
+	me, err := c.GetMe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := c.tokenClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Identity{Me: me, Claims: claims}, nil
+}
+
+// tokenClaims decodes the claims of the token the client sends. An SSO
+// entry's ID token contributes too.
+func (c *Client) tokenClaims(ctx context.Context) (*TokenClaims, error) {
+	if c.auth.mode == authModeSSO {
+		entry, err := c.auth.tokenStore().Get(ctx, c.auth.ssoProfile)
+		if err != nil || entry == nil {
+			return nil, err
+		}
+		return entry.Claims()
+	}
+	hdrs, err := resolveAuthHeaders(ctx, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	token, ok := strings.CutPrefix(hdrs["Authorization"], "Bearer ")
+	if !ok {
+		return nil, nil
+	}
+	claims, err := (&SsoCacheEntry{AccessToken: token}).Claims()
+	if err != nil {
+		// An opaque bearer token has no claims to report.
+		return nil, nil
+	}
+	return claims, nil
+}
+
 // ── API Version ─────────────────────────────────────────────────────────────
 
 // GetAPIVersion reports the API version this request resolved to, and the
@@ -647,25 +762,18 @@ func (c *Client) DownloadAgentRunAttachment(ctx context.Context, runID, attachme
 		query = map[string]string{"download_name": downloadName}
 	}
 	reqURL := c.buildURL(fmt.Sprintf("/v2/agent-runs/%s/attachments/%s", url.PathEscape(runID), url.PathEscape(attachmentID)), query)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL.String(), nil)
+	req, err := c.newRequest(ctx, http.MethodGet, reqURL, nil)
 	if err != nil {
 		return nil, err
 	}
-	for k, v := range c.defaultHeaders {
-		req.Header.Set(k, v)
-	}
-	if err := c.applyAuth(ctx, req); err != nil {
-		return nil, err
-	}
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return nil, err
 	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		raw, _ := io.ReadAll(resp.Body)
 		resp.Body.Close()
-		text := strings.TrimSpace(string(raw))
-		return nil, &APIStatusError{StatusCode: resp.StatusCode, Method: http.MethodGet, URL: reqURL.String(), ResponseText: text}
+		return nil, newDownloadError(http.MethodGet, reqURL.String(), resp, raw)
 	}
 	return resp, nil
 }
@@ -934,120 +1042,76 @@ func (c *Client) RunStreamingAgentAndWait(ctx context.Context, agentID string, b
 		defer cancel()
 	}
 
-	reqURL := c.buildURL(fmt.Sprintf("/agents/%s/runs/stream", url.PathEscape(agentID)), nil)
-	b, err := json.Marshal(body)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL.String(), bytes.NewReader(b))
-	if err != nil {
-		return nil, err
-	}
-	for k, v := range c.defaultHeaders {
-		req.Header.Set(k, v)
-	}
-	if err := c.applyAuth(ctx, req); err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "text/event-stream")
-
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.openAgentRunStream(ctx, agentID, body)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		raw, _ := io.ReadAll(resp.Body)
-		text := strings.TrimSpace(string(raw))
-		statusErr := APIStatusError{StatusCode: resp.StatusCode, Method: http.MethodPost, URL: reqURL.String(), ResponseText: text}
-		if resp.StatusCode == 422 {
-			var ve HTTPValidationError
-			if len(raw) > 0 && json.Unmarshal(raw, &ve) == nil && ve.Detail != nil {
-				return nil, &APIValidationError{APIStatusError: statusErr, ValidationError: &ve}
-			}
-			return nil, &APIValidationError{APIStatusError: statusErr}
-		}
-		return nil, &statusErr
-	}
+	var received int
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "seclai: stream opened", slog.String("agent_id", agentID))
+	defer func() {
+		c.logger.LogAttrs(ctx, slog.LevelDebug, "seclai: stream closed",
+			slog.String("agent_id", agentID), slog.Int("events", received))
+	}()
 
-	reader := bufio.NewReader(resp.Body)
-	var currentEvent string
-	var dataLines []string
+	dec := sse.NewDecoder(resp.Body)
+	// Servers may end the stream without the done event's blank line.
+	dec.SetDispatchAtEOF(true)
 	var lastSeen *AgentRunResponse
-
-	dispatch := func() (*AgentRunResponse, bool) {
-		if currentEvent == "" && len(dataLines) == 0 {
-			return nil, false
-		}
-		data := strings.Join(dataLines, "\n")
-		data = strings.TrimSuffix(data, "\n")
-		defer func() {
-			currentEvent = ""
-			dataLines = nil
-		}()
-
-		if data == "" {
-			return nil, false
-		}
-
-		if currentEvent == "init" || currentEvent == "done" {
-			var parsed AgentRunResponse
-			if err := json.Unmarshal([]byte(data), &parsed); err == nil {
-				lastSeen = &parsed
-				if currentEvent == "done" {
-					return &parsed, true
-				}
+	for {
+		ev, err := dec.Next()
+		if err == io.EOF {
+			if lastSeen != nil {
+				return lastSeen, nil
 			}
+			return nil, &StreamingError{Message: "stream ended before receiving done event"}
 		}
-		return nil, false
-	}
-
-	for {
-		line, err := reader.ReadString('\n')
 		if err != nil {
-			if err == io.EOF {
-				if done, ok := dispatch(); ok {
-					return done, nil
-				}
-				if lastSeen != nil {
-					return lastSeen, nil
-				}
-				return nil, &StreamingError{Message: "stream ended before receiving done event"}
-			}
 			return nil, err
 		}
-
-		line = strings.TrimRight(line, "\r\n")
-		if line == "" {
-			if done, ok := dispatch(); ok {
-				return done, nil
-			}
-			continue
-		}
-		if strings.HasPrefix(line, ":") {
+		received++
+		c.logStreamEvent(ctx, agentID, ev.Type, ev.Data)
+		kind := AgentRunEventKind(ev.Type)
+		if ev.Data == "" || (kind != AgentRunEventInit && kind != AgentRunEventDone) {
 			continue
 		}
-
-		field := line
-		value := ""
-		if i := strings.IndexByte(line, ':'); i >= 0 {
-			field = line[:i]
-			value = line[i+1:]
-			if strings.HasPrefix(value, " ") {
-				value = value[1:]
+		var parsed AgentRunResponse
+		if json.Unmarshal([]byte(ev.Data), &parsed) == nil {
+			lastSeen = &parsed
+			if kind == AgentRunEventDone {
+				return &parsed, nil
 			}
 		}
+	}
+}
 
-		switch field {
-		case "event":
-			currentEvent = value
-		case "data":
-			dataLines = append(dataLines, value)
-		}
+// openAgentRunStream starts a streaming run and returns the response once it
+// has succeeded. The caller closes its body.
+func (c *Client) openAgentRunStream(ctx context.Context, agentID string, body AgentRunStreamRequest) (*http.Response, error) {
+	reqURL := c.buildURL(fmt.Sprintf("/agents/%s/runs/stream", url.PathEscape(agentID)), nil)
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
 	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, reqURL, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := c.send(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		raw, _ := io.ReadAll(resp.Body)
+		return nil, newAPIError(http.MethodPost, reqURL.String(), resp, raw)
+	}
+	return resp, nil
 }
 
 // AgentRunEvent is a single event from an SSE agent run stream.
@@ -1058,6 +1122,17 @@ type AgentRunEvent struct {
 	Data string
 	// Run is the parsed AgentRunResponse if the data could be decoded.
 	Run *AgentRunResponse
+	// ID is the stream's last event ID when the event arrived, empty when
+	// the server sends none. Synthetic events carry the last ID seen.
+	ID string
+	// Payload is the data decoded for the event's kind, such as
+	// *[StepEvent], or an *[UnknownEvent] passing it through.
+	Payload AgentRunEventPayload
+}
+
+// Kind returns the event's kind.
+func (e AgentRunEvent) Kind() AgentRunEventKind {
+	return AgentRunEventKind(e.Event)
 }
 
 // RunStreamingAgent runs an agent in priority mode and returns a channel that
@@ -1073,6 +1148,15 @@ type AgentRunEvent struct {
 //	if err := <-errCh; err != nil {
 //	    // handle error
 //	}
+//
+// A consumer that stops reading leaves the producing goroutine blocked
+// until ctx is cancelled; [Client.RunStreamingAgentEvents] and
+// [Client.RunStreamingAgentWithHandler] read the stream on the caller's
+// goroutine instead.
+//
+// With [Options.StreamReconnect] set, a stream that drops before the done
+// event is followed by polling the run, behind a synthetic "reconnected"
+// event; see [StreamReconnectPolicy].
 func (c *Client) RunStreamingAgent(ctx context.Context, agentID string, body AgentRunStreamRequest) (<-chan AgentRunEvent, <-chan error) {
 	events := make(chan AgentRunEvent, 16)
 	errCh := make(chan error, 1)
@@ -1084,129 +1168,16 @@ func (c *Client) RunStreamingAgent(ctx context.Context, agentID string, body Age
 		if ctx == nil {
 			ctx = context.Background()
 		}
-
-		reqURL := c.buildURL(fmt.Sprintf("/agents/%s/runs/stream", url.PathEscape(agentID)), nil)
-		b, err := json.Marshal(body)
-		if err != nil {
-			errCh <- err
-			return
-		}
-
-		req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL.String(), bytes.NewReader(b))
-		if err != nil {
-			errCh <- err
-			return
-		}
-		for k, v := range c.defaultHeaders {
-			req.Header.Set(k, v)
-		}
-		if err := c.applyAuth(ctx, req); err != nil {
-			errCh <- err
-			return
-		}
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Accept", "text/event-stream")
-
-		resp, err := c.httpClient.Do(req)
-		if err != nil {
-			errCh <- err
-			return
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-			raw, _ := io.ReadAll(resp.Body)
-			text := strings.TrimSpace(string(raw))
-			statusErr := APIStatusError{StatusCode: resp.StatusCode, Method: http.MethodPost, URL: reqURL.String(), ResponseText: text}
-			if resp.StatusCode == 422 {
-				var ve HTTPValidationError
-				if len(raw) > 0 && json.Unmarshal(raw, &ve) == nil && ve.Detail != nil {
-					errCh <- &APIValidationError{APIStatusError: statusErr, ValidationError: &ve}
-					return
-				}
-				errCh <- &APIValidationError{APIStatusError: statusErr}
-				return
-			}
-			errCh <- &statusErr
-			return
-		}
-
-		// If the server returns JSON instead of SSE, emit a single done event.
-		ct := resp.Header.Get("Content-Type")
-		if strings.HasPrefix(ct, "application/json") {
-			raw, _ := io.ReadAll(resp.Body)
-			var parsed AgentRunResponse
-			evt := AgentRunEvent{Event: "done", Data: string(raw)}
-			if json.Unmarshal(raw, &parsed) == nil {
-				evt.Run = &parsed
-			}
-			events <- evt
-			return
-		}
-
-		reader := bufio.NewReader(resp.Body)
-		var currentEvent string
-		var dataLines []string
-
-		dispatch := func() {
-			if currentEvent == "" && len(dataLines) == 0 {
-				return
-			}
-			data := strings.Join(dataLines, "\n")
-			data = strings.TrimSuffix(data, "\n")
-			evt := AgentRunEvent{Event: currentEvent, Data: data}
-			currentEvent = ""
-			dataLines = nil
-
-			if data == "" {
-				return
-			}
-
-			var parsed AgentRunResponse
-			if json.Unmarshal([]byte(data), &parsed) == nil {
-				evt.Run = &parsed
-			}
+		err := c.streamAgentRun(ctx, agentID, body, func(evt AgentRunEvent) error {
 			select {
 			case events <- evt:
+				return nil
 			case <-ctx.Done():
+				return ctx.Err()
 			}
-		}
-
-		for {
-			line, err := reader.ReadString('\n')
-			if err != nil {
-				dispatch()
-				if err != io.EOF {
-					errCh <- err
-				}
-				return
-			}
-
-			line = strings.TrimRight(line, "\r\n")
-			if line == "" {
-				dispatch()
-				continue
-			}
-			if strings.HasPrefix(line, ":") {
-				continue
-			}
-
-			field := line
-			value := ""
-			if i := strings.IndexByte(line, ':'); i >= 0 {
-				field = line[:i]
-				value = line[i+1:]
-				if strings.HasPrefix(value, " ") {
-					value = value[1:]
-				}
-			}
-
-			switch field {
-			case "event":
-				currentEvent = value
-			case "data":
-				dataLines = append(dataLines, value)
-			}
+		})
+		if err != nil {
+			errCh <- err
 		}
 	}()
 
@@ -1251,8 +1222,7 @@ func (c *Client) RunAgentAndPoll(ctx context.Context, agentID string, body Agent
 	defer ticker.Stop()
 
 	for {
-		switch run.Status {
-		case "completed", "failed":
+		if isTerminalRunStatus(run.Status) {
 			return run, nil
 		}
 
@@ -1340,37 +1310,22 @@ func (c *Client) doUpload(ctx context.Context, apiPath string, req UploadFileReq
 	}
 	_ = w.Close()
 
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL.String(), &buf)
+	httpReq, err := c.newRequest(ctx, http.MethodPost, reqURL, &buf)
 	if err != nil {
 		return nil, err
 	}
-	for k, v := range c.defaultHeaders {
-		httpReq.Header.Set(k, v)
-	}
-	if err := c.applyAuth(ctx, httpReq); err != nil {
-		return nil, err
-	}
 	httpReq.Header.Set("Content-Type", w.FormDataContentType())
 	httpReq.Header.Set("Accept", "application/json")
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.send(httpReq)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	raw, _ := io.ReadAll(resp.Body)
-	text := strings.TrimSpace(string(raw))
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		statusErr := APIStatusError{StatusCode: resp.StatusCode, Method: http.MethodPost, URL: reqURL.String(), ResponseText: text}
-		if resp.StatusCode == 422 {
-			var ve HTTPValidationError
-			if len(raw) > 0 && json.Unmarshal(raw, &ve) == nil && ve.Detail != nil {
-				return nil, &APIValidationError{APIStatusError: statusErr, ValidationError: &ve}
-			}
-			return nil, &APIValidationError{APIStatusError: statusErr}
-		}
-		return nil, &statusErr
+		return nil, newAPIError(http.MethodPost, reqURL.String(), resp, raw)
 	}
 	return raw, nil
 }
@@ -1874,25 +1829,18 @@ func (c *Client) DownloadSourceExport(ctx context.Context, sourceID, exportID st
 		ctx = context.Background()
 	}
 	reqURL := c.buildURL(fmt.Sprintf("/sources/%s/exports/%s/download", url.PathEscape(sourceID), url.PathEscape(exportID)), nil)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL.String(), nil)
+	req, err := c.newRequest(ctx, http.MethodGet, reqURL, nil)
 	if err != nil {
 		return nil, err
 	}
-	for k, v := range c.defaultHeaders {
-		req.Header.Set(k, v)
-	}
-	if err := c.applyAuth(ctx, req); err != nil {
-		return nil, err
-	}
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return nil, err
 	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		raw, _ := io.ReadAll(resp.Body)
 		resp.Body.Close()
-		text := strings.TrimSpace(string(raw))
-		return nil, &APIStatusError{StatusCode: resp.StatusCode, Method: http.MethodGet, URL: reqURL.String(), ResponseText: text}
+		return nil, newDownloadError(http.MethodGet, reqURL.String(), resp, raw)
 	}
 	return resp, nil
 }
@@ -1277,6 +1277,75 @@ func TestClient_RunStreamingAgent_ServerError(t *testing.T) {
 	}
 }
 
+func TestClient_RunStreamingAgent_CRLFAndUnterminatedEvent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "\uFEFF: keepalive\r\n\r\nid: 1\r\nevent: init\r\ndata: {\"run_id\":\"run_1\",\"status\":\"processing\"}\r\n\r\n")
+		// The stream breaks off mid-event: the partial event is discarded.
+		_, _ = io.WriteString(w, "event: done\r\ndata: {\"run_id\":\"run_1\"")
+	}))
+	t.Cleanup(srv.Close)
+
+	c, _ := NewClient(Options{APIKey: "k", BaseURL: srv.URL})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ch, errCh := c.RunStreamingAgent(ctx, "agent_1", AgentRunStreamRequest{})
+	var events []AgentRunEvent
+	for evt := range ch {
+		events = append(events, evt)
+	}
+	if err := <-errCh; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 || events[0].Event != "init" || events[0].Run == nil || events[0].Run.RunId != "run_1" {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+
+	// RunStreamingAgentAndWait falls back to the last run it saw.
+	run, err := c.RunStreamingAgentAndWait(ctx, "agent_1", AgentRunStreamRequest{})
+	if err != nil {
+		t.Fatalf("RunStreamingAgentAndWait: %v", err)
+	}
+	if run.RunId != "run_1" || run.Status != "processing" {
+		t.Fatalf("unexpected run: %+v", run)
+	}
+}
+
+func TestClient_RunStreamingAgent_DoneWithoutClosingBlankLine(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		// The done event's lines are complete but the blank line is missing.
+		_, _ = io.WriteString(w, "event: init\ndata: {\"run_id\":\"run_1\",\"status\":\"processing\"}\n\n"+
+			"event: done\ndata: {\"run_id\":\"run_1\",\"status\":\"completed\",\"output\":\"ok\"}\n")
+	}))
+	t.Cleanup(srv.Close)
+
+	c, _ := NewClient(Options{APIKey: "k", BaseURL: srv.URL})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ch, errCh := c.RunStreamingAgent(ctx, "agent_1", AgentRunStreamRequest{})
+	var events []AgentRunEvent
+	for evt := range ch {
+		events = append(events, evt)
+	}
+	if err := <-errCh; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 2 || events[1].Event != "done" || events[1].Run == nil || events[1].Run.Status != "completed" {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+
+	run, err := c.RunStreamingAgentAndWait(ctx, "agent_1", AgentRunStreamRequest{})
+	if err != nil {
+		t.Fatalf("RunStreamingAgentAndWait: %v", err)
+	}
+	if run.Status != "completed" || run.Output == nil || *run.Output != "ok" {
+		t.Fatalf("unexpected run: %+v", run)
+	}
+}
+
 // ── RunAgentAndPoll tests ───────────────────────────────────────────────────
 
 func TestClient_RunAgentAndPoll_CompletesImmediately(t *testing.T) {
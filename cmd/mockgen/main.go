@@ -0,0 +1,289 @@
+// Command mockgen writes the seclaimock package: a function-field mock of
+// every interface in the seclai package's api.go. It fails if an exported
+// *Client method belongs to none of those interfaces, so a method cannot be
+// added to the client and left out of them.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// unmocked are the exported *Client methods deliberately left out of the
+// interfaces: they hand out other concrete types, report the client's own
+// configuration or manage its lifecycle rather than calling the API.
+var unmocked = map[string]bool{
+	"Close":     true,
+	"Do":        true,
+	"Generated": true,
+	"Settings":  true,
+	"Typed":     true,
+}
+
+const seclaiImport = "github.com/seclai/seclai-go"
+
+type iface struct {
+	name    string
+	embeds  []string
+	methods []*ast.Field
+}
+
+func main() {
+	var dir string
+	var outPath string
+	flag.StringVar(&dir, "dir", ".", "Directory of the seclai package")
+	flag.StringVar(&outPath, "out", "", "Output Go file path")
+	flag.Parse()
+
+	if outPath == "" {
+		fmt.Fprintln(os.Stderr, "Usage: mockgen -dir <seclai package dir> -out <mock_gen.go>")
+		os.Exit(2)
+	}
+
+	fset := token.NewFileSet()
+	apiFile, err := parser.ParseFile(fset, filepath.Join(dir, "api.go"), nil, 0)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mockgen: parse: %v\n", err)
+		os.Exit(1)
+	}
+	ifaces := interfaces(apiFile)
+
+	methods, err := clientMethods(fset, dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mockgen: parse: %v\n", err)
+		os.Exit(1)
+	}
+	covered := map[string]bool{}
+	for _, it := range ifaces {
+		for _, m := range it.methods {
+			covered[m.Names[0].Name] = true
+		}
+	}
+	var missing []string
+	for _, m := range methods {
+		if !covered[m] && !unmocked[m] {
+			missing = append(missing, m)
+		}
+	}
+	if len(missing) > 0 {
+		fmt.Fprintf(os.Stderr, "mockgen: *Client methods in no interface in api.go: %s\n", strings.Join(missing, ", "))
+		os.Exit(1)
+	}
+
+	imports := map[string]string{}
+	for _, spec := range apiFile.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		imports[filepath.Base(path)] = path
+	}
+	used := map[string]bool{}
+
+	var body bytes.Buffer
+	for _, it := range ifaces {
+		if len(it.embeds) > 0 {
+			writeComposite(&body, it)
+			continue
+		}
+		writeMock(&body, fset, it, used)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by cmd/mockgen from api.go. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package seclaimock\n\nimport (\n")
+	var paths []string
+	for pkg := range used {
+		paths = append(paths, imports[pkg])
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		fmt.Fprintf(&buf, "\t%q\n", p)
+	}
+	fmt.Fprintf(&buf, "\n\tseclai %q\n)\n", seclaiImport)
+	buf.Write(body.Bytes())
+
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mockgen: format: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(outPath, out, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "mockgen: write: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// interfaces returns the exported interfaces declared in f, in source order.
+func interfaces(f *ast.File) []iface {
+	var out []iface
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			it, ok := ts.Type.(*ast.InterfaceType)
+			if !ok || !ts.Name.IsExported() {
+				continue
+			}
+			i := iface{name: ts.Name.Name}
+			for _, field := range it.Methods.List {
+				if len(field.Names) == 0 {
+					i.embeds = append(i.embeds, field.Type.(*ast.Ident).Name)
+					continue
+				}
+				i.methods = append(i.methods, field)
+			}
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// clientMethods returns the exported methods declared on *Client in dir.
+func clientMethods(fset *token.FileSet, dir string) ([]string, error) {
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			for _, decl := range f.Decls {
+				fd, ok := decl.(*ast.FuncDecl)
+				if !ok || fd.Recv == nil || !fd.Name.IsExported() {
+					continue
+				}
+				star, ok := fd.Recv.List[0].Type.(*ast.StarExpr)
+				if !ok {
+					continue
+				}
+				if id, ok := star.X.(*ast.Ident); ok && id.Name == "Client" {
+					out = append(out, fd.Name.Name)
+				}
+			}
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+func writeComposite(buf *bytes.Buffer, it iface) {
+	name := it.name
+	if name == "API" {
+		name = "Client"
+	}
+	fmt.Fprintf(buf, "\n// %s is a mock of [seclai.%s], made of the mock of each interface it\n// embeds.\n", name, it.name)
+	fmt.Fprintf(buf, "type %s struct {\n", name)
+	for _, e := range it.embeds {
+		fmt.Fprintf(buf, "\t%s\n", e)
+	}
+	fmt.Fprintf(buf, "}\n\nvar _ seclai.%s = (*%s)(nil)\n", it.name, name)
+}
+
+func writeMock(buf *bytes.Buffer, fset *token.FileSet, it iface, used map[string]bool) {
+	fmt.Fprintf(buf, "\n// %s is a mock of [seclai.%s].\n", it.name, it.name)
+	fmt.Fprintf(buf, "type %s struct {\n", it.name)
+	for _, m := range it.methods {
+		fmt.Fprintf(buf, "\t%sFunc %s\n", m.Names[0].Name, expr(fset, qualify(m.Type, used)))
+	}
+	fmt.Fprintf(buf, "}\n\nvar _ seclai.%s = (*%s)(nil)\n", it.name, it.name)
+
+	for _, m := range it.methods {
+		name := m.Names[0].Name
+		ft := qualify(m.Type, used).(*ast.FuncType)
+		var args []string
+		for i, p := range ft.Params.List {
+			if len(p.Names) == 0 {
+				p.Names = []*ast.Ident{ast.NewIdent(fmt.Sprintf("p%d", i))}
+			}
+			for _, n := range p.Names {
+				if _, ok := p.Type.(*ast.Ellipsis); ok {
+					args = append(args, n.Name+"...")
+				} else {
+					args = append(args, n.Name)
+				}
+			}
+		}
+		call := fmt.Sprintf("m.%sFunc(%s)", name, strings.Join(args, ", "))
+		if ft.Results != nil && len(ft.Results.List) > 0 {
+			call = "return " + call
+		}
+		fmt.Fprintf(buf, "\nfunc (m *%s) %s%s {\n", it.name, name, strings.TrimPrefix(expr(fset, ft), "func"))
+		fmt.Fprintf(buf, "\tif m.%sFunc == nil {\n\t\tpanic(\"seclaimock: %s.%s called with a nil %sFunc\")\n\t}\n", name, it.name, name, name)
+		fmt.Fprintf(buf, "\t%s\n}\n", call)
+	}
+}
+
+// qualify returns a copy of a type expression with the seclai package's own
+// identifiers qualified, recording the other packages it refers to in used.
+func qualify(e ast.Expr, used map[string]bool) ast.Expr {
+	switch e := e.(type) {
+	case *ast.Ident:
+		if e.IsExported() {
+			return &ast.SelectorExpr{X: ast.NewIdent("seclai"), Sel: ast.NewIdent(e.Name)}
+		}
+		return ast.NewIdent(e.Name)
+	case *ast.SelectorExpr:
+		used[e.X.(*ast.Ident).Name] = true
+		return &ast.SelectorExpr{X: ast.NewIdent(e.X.(*ast.Ident).Name), Sel: ast.NewIdent(e.Sel.Name)}
+	case *ast.StarExpr:
+		return &ast.StarExpr{X: qualify(e.X, used)}
+	case *ast.ArrayType:
+		return &ast.ArrayType{Len: e.Len, Elt: qualify(e.Elt, used)}
+	case *ast.MapType:
+		return &ast.MapType{Key: qualify(e.Key, used), Value: qualify(e.Value, used)}
+	case *ast.ChanType:
+		return &ast.ChanType{Dir: e.Dir, Value: qualify(e.Value, used)}
+	case *ast.Ellipsis:
+		return &ast.Ellipsis{Elt: qualify(e.Elt, used)}
+	case *ast.IndexExpr:
+		return &ast.IndexExpr{X: qualify(e.X, used), Index: qualify(e.Index, used)}
+	case *ast.IndexListExpr:
+		idx := make([]ast.Expr, len(e.Indices))
+		for i, x := range e.Indices {
+			idx[i] = qualify(x, used)
+		}
+		return &ast.IndexListExpr{X: qualify(e.X, used), Indices: idx}
+	case *ast.FuncType:
+		return &ast.FuncType{Params: qualifyFields(e.Params, used), Results: qualifyFields(e.Results, used)}
+	case *ast.InterfaceType, *ast.StructType:
+		return e
+	}
+	panic(fmt.Sprintf("mockgen: unsupported type expression %T", e))
+}
+
+func qualifyFields(fl *ast.FieldList, used map[string]bool) *ast.FieldList {
+	if fl == nil {
+		return nil
+	}
+	out := &ast.FieldList{}
+	for _, f := range fl.List {
+		var names []*ast.Ident
+		for _, n := range f.Names {
+			names = append(names, ast.NewIdent(n.Name))
+		}
+		out.List = append(out.List, &ast.Field{Names: names, Type: qualify(f.Type, used)})
+	}
+	return out
+}
+
+func expr(fset *token.FileSet, e ast.Expr) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, e); err != nil {
+		panic(err)
+	}
+	return buf.String()
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"sort"
+)
+
+func main() {
+	var inPath string
+	var outPath string
+	var pkg string
+	flag.StringVar(&inPath, "in", "", "Input OpenAPI JSON path")
+	flag.StringVar(&outPath, "out", "", "Output Go file path")
+	flag.StringVar(&pkg, "package", "routes", "Package name of the output file")
+	flag.Parse()
+
+	if inPath == "" || outPath == "" {
+		fmt.Fprintln(os.Stderr, "Usage: routegen -in <openapi.json> -out <routes_gen.go> [-package name]")
+		os.Exit(2)
+	}
+
+	raw, err := os.ReadFile(inPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "routegen: read: %v\n", err)
+		os.Exit(1)
+	}
+
+	var doc struct {
+		Paths map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		fmt.Fprintf(os.Stderr, "routegen: parse json: %v\n", err)
+		os.Exit(1)
+	}
+
+	paths := make([]string, 0, len(doc.Paths))
+	for p := range doc.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by cmd/routegen from openapi/seclai.openapi.json. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	fmt.Fprintf(&buf, "// templates lists every path template in the OpenAPI spec.\n")
+	fmt.Fprintf(&buf, "var templates = []string{\n")
+	for _, p := range paths {
+		fmt.Fprintf(&buf, "\t%q,\n", p)
+	}
+	fmt.Fprintf(&buf, "}\n")
+
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "routegen: format: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(outPath, out, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "routegen: write: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,338 @@
+package seclai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ConfigFile is the config file (~/.seclai/config) for editing. It keeps the
+// file's comments, blank lines and ordering, so a setup script can add a
+// profile or change one key without disturbing what a person wrote.
+//
+// Changes are made in memory; [ConfigFile.Save] writes them.
+type ConfigFile struct {
+	// TokenStore is where Status looks up cached SSO tokens. Set it to the
+	// store clients use, as in [Options.TokenStore]; nil uses the plaintext
+	// cache in the config directory.
+	TokenStore TokenStore
+
+	dir      string
+	preamble []string
+	sections []*configSection
+}
+
+// configSection is one [section] of the file as written. The comment lines
+// directly above its header belong to it, so they go with it when it is
+// deleted.
+type configSection struct {
+	name   string
+	lead   []string
+	header string
+	lines  []string
+}
+
+// LoadConfigFile reads the config file in configDir for editing. Empty uses
+// SECLAI_CONFIG_DIR, then ~/.seclai. A missing file yields an empty
+// ConfigFile that Save creates.
+func LoadConfigFile(configDir string) (*ConfigFile, error) {
+	dir := resolveConfigDir(configDir)
+	if dir == "" {
+		return nil, &ConfigurationError{Message: "cannot locate the config directory: set SECLAI_CONFIG_DIR"}
+	}
+	cf := &ConfigFile{dir: dir}
+	f, err := os.Open(cf.Path())
+	if os.IsNotExist(err) {
+		return cf, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if err := cf.parse(f); err != nil {
+		return nil, fmt.Errorf("read %s: %w", cf.Path(), err)
+	}
+	return cf, nil
+}
+
+// Path returns the path of the config file.
+func (cf *ConfigFile) Path() string {
+	return filepath.Join(cf.dir, ssoConfigFile)
+}
+
+func (cf *ConfigFile) parse(r io.Reader) error {
+	var cur *configSection
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			sec := &configSection{name: sectionName(trimmed), header: line}
+			// Claim the comments directly above the header.
+			prev := &cf.preamble
+			if cur != nil {
+				prev = &cur.lines
+			}
+			n := len(*prev)
+			for n > 0 && isComment((*prev)[n-1]) {
+				n--
+			}
+			sec.lead = append([]string(nil), (*prev)[n:]...)
+			*prev = (*prev)[:n]
+			cf.sections = append(cf.sections, sec)
+			cur = sec
+			continue
+		}
+		if cur == nil {
+			cf.preamble = append(cf.preamble, line)
+		} else {
+			cur.lines = append(cur.lines, line)
+		}
+	}
+	return scanner.Err()
+}
+
+// sectionName maps a header to its profile name as [ParseIni] does:
+// [default] is "default" and [profile X] is "X".
+func sectionName(header string) string {
+	raw := strings.TrimSpace(header[1 : len(header)-1])
+	if rest, ok := strings.CutPrefix(raw, "profile "); ok {
+		return strings.TrimSpace(rest)
+	}
+	return raw
+}
+
+func isComment(line string) bool {
+	t := strings.TrimSpace(line)
+	return strings.HasPrefix(t, "#") || strings.HasPrefix(t, ";")
+}
+
+// lineKey returns the key a line sets, or "" for a blank line or comment.
+func lineKey(line string) string {
+	t := strings.TrimSpace(line)
+	if t == "" || isComment(t) {
+		return ""
+	}
+	if idx := strings.Index(t, "="); idx > 0 {
+		return strings.TrimSpace(t[:idx])
+	}
+	return ""
+}
+
+// Profiles returns the names of the profiles in the file, in file order.
+// [default] is "default".
+func (cf *ConfigFile) Profiles() []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, sec := range cf.sections {
+		if !seen[sec.name] {
+			seen[sec.name] = true
+			out = append(out, sec.name)
+		}
+	}
+	return out
+}
+
+// Get returns the value a profile's own section sets for key, without
+// inheriting from [default].
+func (cf *ConfigFile) Get(profile, key string) (string, bool) {
+	v, ok := cf.sectionsMap()[profile][key]
+	return v, ok
+}
+
+// sectionsMap returns the file's sections the way [ParseIni] reads them.
+func (cf *ConfigFile) sectionsMap() map[string]map[string]string {
+	var buf bytes.Buffer
+	cf.write(&buf)
+	return ParseIni(&buf)
+}
+
+// Set sets key in a profile's section, adding the section when the file has
+// none. An existing key is changed in place, keeping its position; a new one
+// goes after the section's last key.
+func (cf *ConfigFile) Set(profile, key, value string) error {
+	if err := validateProfileName(profile); err != nil {
+		return err
+	}
+	if key == "" || strings.ContainsAny(key, "=\r\n") || strings.ContainsAny(key[:1], "[#;") || strings.TrimSpace(key) != key {
+		return &ConfigurationError{Message: fmt.Sprintf("invalid config key %q", key)}
+	}
+	if strings.ContainsAny(value, "\r\n") {
+		return &ConfigurationError{Message: fmt.Sprintf("config value for %s must be a single line", key)}
+	}
+	line := key + " = " + value
+
+	// Change the occurrence ParseIni would read: the last one.
+	var last *configSection
+	for i := len(cf.sections) - 1; i >= 0; i-- {
+		sec := cf.sections[i]
+		if sec.name != profile {
+			continue
+		}
+		if last == nil {
+			last = sec
+		}
+		for j := len(sec.lines) - 1; j >= 0; j-- {
+			if lineKey(sec.lines[j]) == key {
+				sec.lines[j] = line
+				return nil
+			}
+		}
+	}
+
+	if last == nil {
+		last = cf.addSection(profile)
+	}
+	at := 0
+	for j, l := range last.lines {
+		if lineKey(l) != "" {
+			at = j + 1
+		}
+	}
+	last.lines = append(last.lines[:at], append([]string{line}, last.lines[at:]...)...)
+	return nil
+}
+
+func (cf *ConfigFile) addSection(profile string) *configSection {
+	header := "[profile " + profile + "]"
+	if profile == "default" {
+		header = "[default]"
+	}
+	// Separate it from the previous section by a blank line.
+	prev := &cf.preamble
+	if n := len(cf.sections); n > 0 {
+		prev = &cf.sections[n-1].lines
+	}
+	if n := len(*prev); n > 0 && strings.TrimSpace((*prev)[n-1]) != "" {
+		*prev = append(*prev, "")
+	}
+	sec := &configSection{name: profile, header: header}
+	cf.sections = append(cf.sections, sec)
+	return sec
+}
+
+func validateProfileName(profile string) error {
+	if profile == "" || strings.ContainsAny(profile, "[]\r\n") || strings.TrimSpace(profile) != profile {
+		return &ConfigurationError{Message: fmt.Sprintf("invalid profile name %q", profile)}
+	}
+	return nil
+}
+
+// DeleteProfile removes every section for profile, with the comments
+// directly above it. It reports whether there was one.
+func (cf *ConfigFile) DeleteProfile(profile string) bool {
+	kept := cf.sections[:0]
+	for _, sec := range cf.sections {
+		if sec.name != profile {
+			kept = append(kept, sec)
+		}
+	}
+	deleted := len(kept) != len(cf.sections)
+	cf.sections = kept
+	return deleted
+}
+
+// Bytes returns the file as Save would write it.
+func (cf *ConfigFile) Bytes() []byte {
+	var buf bytes.Buffer
+	cf.write(&buf)
+	return buf.Bytes()
+}
+
+func (cf *ConfigFile) write(buf *bytes.Buffer) {
+	writeLines := func(lines []string) {
+		for _, l := range lines {
+			buf.WriteString(l)
+			buf.WriteByte('\n')
+		}
+	}
+	writeLines(cf.preamble)
+	for _, sec := range cf.sections {
+		writeLines(sec.lead)
+		writeLines([]string{sec.header})
+		writeLines(sec.lines)
+	}
+}
+
+// Save writes the file atomically with mode 0600, creating the config
+// directory if needed.
+func (cf *ConfigFile) Save() error {
+	if err := os.MkdirAll(cf.dir, 0700); err != nil {
+		return err
+	}
+	return writeFileAtomic(cf.Path(), cf.Bytes())
+}
+
+// ProfileStatus describes a profile and its cached SSO token, as reported by
+// [ConfigFile.Status].
+type ProfileStatus struct {
+	// Profile is the profile name.
+	Profile string
+	// Exists reports whether the file has a section for the profile. A
+	// profile without one uses [default].
+	Exists bool
+	// Credential is how a client using the profile authenticates: "api_key",
+	// "credential_process" or "sso".
+	Credential string
+	// SsoProfile is the profile's resolved SSO settings.
+	SsoProfile *SsoProfile
+	// Cached reports whether the token cache holds tokens for the profile's
+	// SSO domain and client ID.
+	Cached bool
+	// Valid reports whether the cached access token is unexpired, with the
+	// same 30-second buffer a client applies.
+	Valid bool
+	// ExpiresAt is when the cached access token expires, zero when unknown.
+	ExpiresAt time.Time
+	// Refreshable reports whether the cache holds a refresh token, so an
+	// expired access token can be renewed without logging in again.
+	Refreshable bool
+}
+
+// Status reports how a profile authenticates and the state of its cached
+// SSO token, from the file as edited so far and cf.TokenStore.
+func (cf *ConfigFile) Status(profile string) (*ProfileStatus, error) {
+	sections := cf.sectionsMap()
+	_, exists := sections[profile]
+	prof := profileFromSections(sections, profile, loggerOrDiscard(nil))
+	prof.dir, prof.path = cf.dir, cf.Path()
+	st := &ProfileStatus{
+		Profile:    profile,
+		Exists:     exists,
+		SsoProfile: ssoProfileFromSection(prof.values),
+	}
+	key, err := prof.credentialKey()
+	if err != nil {
+		return nil, err
+	}
+	switch key {
+	case "api_key", "api_key_file":
+		st.Credential = "api_key"
+	case "credential_process":
+		st.Credential = key
+	default:
+		st.Credential = "sso"
+	}
+
+	store := cf.TokenStore
+	if store == nil {
+		store = NewFileTokenStore(cf.dir)
+	}
+	entry, err := store.Get(context.Background(), st.SsoProfile)
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil {
+		st.Cached = true
+		st.Valid = IsTokenValid(entry)
+		st.ExpiresAt, _ = entry.expiry()
+		st.Refreshable = entry.RefreshToken != ""
+	}
+	return st, nil
+}
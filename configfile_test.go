@@ -0,0 +1,265 @@
+package seclai
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+const sampleConfig = `# Seclai CLI configuration
+; managed by hand
+
+[default]
+sso_region = us-west-2
+# the shared key
+api_key = sk-default
+
+# Staging points at the staging pool.
+[profile staging]
+sso_domain = auth.staging.example.com
+sso_client_id = staging-client
+
+[profile prod]
+credential_process = /usr/local/bin/creds prod
+`
+
+func loadSample(t *testing.T) (*ConfigFile, string) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config"), []byte(sampleConfig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cf, err := LoadConfigFile(dir)
+	if err != nil {
+		t.Fatalf("LoadConfigFile: %v", err)
+	}
+	return cf, dir
+}
+
+func TestConfigFile_RoundTripsUnchanged(t *testing.T) {
+	cf, _ := loadSample(t)
+	if got := string(cf.Bytes()); got != sampleConfig {
+		t.Fatalf("round trip changed the file:\n%s", got)
+	}
+	if got, want := cf.Profiles(), []string{"default", "staging", "prod"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Profiles() = %v, want %v", got, want)
+	}
+	if v, ok := cf.Get("staging", "sso_domain"); !ok || v != "auth.staging.example.com" {
+		t.Fatalf("Get = %q, %v", v, ok)
+	}
+	if _, ok := cf.Get("staging", "api_key"); ok {
+		t.Fatal("Get should not inherit from [default]")
+	}
+}
+
+func TestConfigFile_SetAndDelete(t *testing.T) {
+	cf, dir := loadSample(t)
+	for _, kv := range [][3]string{
+		{"default", "sso_region", "eu-west-1"},
+		{"staging", "base_url", "https://staging.example.com"},
+		{"dev", "api_key_file", "~/.secrets/dev"},
+	} {
+		if err := cf.Set(kv[0], kv[1], kv[2]); err != nil {
+			t.Fatalf("Set(%q, %q): %v", kv[0], kv[1], err)
+		}
+	}
+	if !cf.DeleteProfile("prod") {
+		t.Fatal("expected prod to be deleted")
+	}
+	if cf.DeleteProfile("prod") {
+		t.Fatal("deleting a missing profile should report false")
+	}
+	if err := cf.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	raw, err := os.ReadFile(filepath.Join(dir, "config"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# Seclai CLI configuration
+; managed by hand
+
+[default]
+sso_region = eu-west-1
+# the shared key
+api_key = sk-default
+
+# Staging points at the staging pool.
+[profile staging]
+sso_domain = auth.staging.example.com
+sso_client_id = staging-client
+base_url = https://staging.example.com
+
+[profile dev]
+api_key_file = ~/.secrets/dev
+`
+	if string(raw) != want {
+		t.Fatalf("saved file:\n%s\nwant:\n%s", raw, want)
+	}
+	if runtime.GOOS != "windows" {
+		fi, err := os.Stat(filepath.Join(dir, "config"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fi.Mode().Perm() != 0o600 {
+			t.Fatalf("expected mode 0600, got %v", fi.Mode().Perm())
+		}
+	}
+	if tmp, _ := filepath.Glob(filepath.Join(dir, "config.*")); len(tmp) != 0 {
+		t.Fatalf("temp file left behind: %v", tmp)
+	}
+}
+
+func TestWriteFileAtomic_ConcurrentWriters(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config")
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- writeFileAtomic(path, bytes.Repeat([]byte{'a' + byte(i)}, 64<<10))
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("writeFileAtomic: %v", err)
+		}
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(raw) != 64<<10 || len(bytes.Trim(raw, string(raw[:1]))) != 0 {
+		t.Fatalf("expected one writer's whole file, got %d bytes mixing writers", len(raw))
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Fatalf("temp files left behind: %v", entries)
+	}
+}
+
+func TestConfigFile_SetRejectsInvalidInput(t *testing.T) {
+	cf, _ := loadSample(t)
+	for _, args := range [][3]string{
+		{"", "k", "v"},
+		{"bad]name", "k", "v"},
+		{"p", "", "v"},
+		{"p", "a=b", "v"},
+		{"p", "#k", "v"},
+		{"p", "k", "two\nlines"},
+	} {
+		if err := cf.Set(args[0], args[1], args[2]); err == nil {
+			t.Errorf("Set(%q, %q, %q) should fail", args[0], args[1], args[2])
+		}
+	}
+	if string(cf.Bytes()) != sampleConfig {
+		t.Fatal("a rejected Set changed the file")
+	}
+}
+
+func TestConfigFile_NewFile(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested")
+	cf, err := LoadConfigFile(dir)
+	if err != nil {
+		t.Fatalf("LoadConfigFile: %v", err)
+	}
+	if len(cf.Profiles()) != 0 {
+		t.Fatalf("expected no profiles, got %v", cf.Profiles())
+	}
+	if err := cf.Set("default", "sso_region", "us-west-2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cf.Set("ci", "api_key", "sk-ci"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cf.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	raw, err := os.ReadFile(filepath.Join(dir, "config"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "[default]\nsso_region = us-west-2\n\n[profile ci]\napi_key = sk-ci\n"; string(raw) != want {
+		t.Fatalf("saved file:\n%q\nwant:\n%q", raw, want)
+	}
+}
+
+func TestConfigFile_Status(t *testing.T) {
+	cf, dir := loadSample(t)
+	t.Setenv("SECLAI_SSO_DOMAIN", "")
+	t.Setenv("SECLAI_SSO_CLIENT_ID", "")
+
+	staging := &SsoProfile{SsoDomain: "auth.staging.example.com", SsoClientID: "staging-client"}
+	expires := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	if err := WriteSsoCache(dir, staging, &SsoCacheEntry{
+		AccessToken:  "at",
+		RefreshToken: "rt",
+		ExpiresAt:    expires.Format(time.RFC3339),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	st, err := cf.Status("staging")
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	// [profile staging] sets its own SSO settings, so it does not inherit
+	// [default]'s api_key.
+	if !st.Exists || st.Credential != "sso" || !st.Cached || !st.Valid || !st.Refreshable || !st.ExpiresAt.Equal(expires) {
+		t.Fatalf("unexpected status: %+v", st)
+	}
+	if st.SsoProfile.SsoDomain != "auth.staging.example.com" || st.SsoProfile.SsoRegion != "us-west-2" {
+		t.Fatalf("unexpected SSO profile: %+v", st.SsoProfile)
+	}
+
+	st, err = cf.Status("prod")
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if st.Credential != "credential_process" || st.Cached {
+		t.Fatalf("unexpected status: %+v", st)
+	}
+
+	st, err = cf.Status("missing")
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if st.Exists || st.Cached || st.Valid {
+		t.Fatalf("unexpected status: %+v", st)
+	}
+}
+
+func TestConfigFile_StatusUsesTheTokenStore(t *testing.T) {
+	cf, dir := loadSample(t)
+	t.Setenv("SECLAI_SSO_DOMAIN", "")
+	t.Setenv("SECLAI_SSO_CLIENT_ID", "")
+
+	staging := &SsoProfile{SsoDomain: "auth.staging.example.com", SsoClientID: "staging-client"}
+	// The plaintext cache has an expired token, the configured store a valid one.
+	if err := WriteSsoCache(dir, staging, &SsoCacheEntry{AccessToken: "old", ExpiresAt: time.Now().Add(-time.Hour).Format(time.RFC3339)}); err != nil {
+		t.Fatal(err)
+	}
+	store := NewMemoryTokenStore()
+	if err := store.Put(context.Background(), staging, &SsoCacheEntry{AccessToken: "at", ExpiresAt: time.Now().Add(time.Hour).Format(time.RFC3339)}); err != nil {
+		t.Fatal(err)
+	}
+	cf.TokenStore = store
+
+	st, err := cf.Status("staging")
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if !st.Cached || !st.Valid || st.Refreshable {
+		t.Fatalf("expected the memory store's entry, got %+v", st)
+	}
+}
@@ -0,0 +1,157 @@
+package seclai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// credentialProcessTimeout bounds one run of a profile's credential_process.
+const credentialProcessTimeout = time.Minute
+
+// processCredential is the JSON a credential_process prints on stdout:
+//
+//	{"api_key": "sk-...", "expiration": "2026-01-02T15:04:05Z"}
+//	{"access_token": "eyJ...", "expiration": "2026-01-02T15:04:05Z"}
+//
+// Exactly one of api_key and access_token is set. Without an expiration the
+// credential is used for the client's lifetime.
+type processCredential struct {
+	APIKey      string `json:"api_key"`
+	AccessToken string `json:"access_token"`
+	Expiration  string `json:"expiration"`
+
+	expiresAt time.Time
+}
+
+// valid reports whether the credential can still be used, with the same
+// buffer before expiry as SSO tokens.
+func (p *processCredential) valid() bool {
+	return p.expiresAt.IsZero() || time.Now().Add(expiryBuffer).Before(p.expiresAt)
+}
+
+// resolveProcessCredential returns the cached credential, running the
+// profile's credential_process again once it has expired. Concurrent
+// requests share one run.
+func resolveProcessCredential(ctx context.Context, state *authState) (*processCredential, error) {
+	state.credentialMu.Lock()
+	defer state.credentialMu.Unlock()
+	if state.credential != nil && state.credential.valid() {
+		return state.credential, nil
+	}
+	cred, err := runCredentialProcess(ctx, state.credentialProcess, loggerOrDiscard(state.logger))
+	if err != nil {
+		return nil, err
+	}
+	state.credential = cred
+	return cred, nil
+}
+
+// runCredentialProcess runs command and parses its output.
+func runCredentialProcess(ctx context.Context, command string, logger *slog.Logger) (*processCredential, error) {
+	args, err := splitCommandLine(command)
+	if err != nil {
+		return nil, &ConfigurationError{Message: fmt.Sprintf("credential_process: %v", err)}
+	}
+	if len(args) == 0 {
+		return nil, &ConfigurationError{Message: "credential_process is empty"}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, credentialProcessTimeout)
+	defer cancel()
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	logger.DebugContext(ctx, "seclai: running credential_process", slog.String("command", args[0]))
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("credential_process %s failed: %w: %s", args[0], err, msg)
+		}
+		return nil, fmt.Errorf("credential_process %s failed: %w", args[0], err)
+	}
+
+	var cred processCredential
+	if err := json.Unmarshal(stdout.Bytes(), &cred); err != nil {
+		return nil, fmt.Errorf("credential_process %s printed invalid JSON: %w", args[0], err)
+	}
+	if (cred.APIKey == "") == (cred.AccessToken == "") {
+		return nil, fmt.Errorf("credential_process %s must print exactly one of api_key and access_token", args[0])
+	}
+	if cred.Expiration != "" {
+		t, err := time.Parse(time.RFC3339, cred.Expiration)
+		if err != nil {
+			return nil, fmt.Errorf("credential_process %s printed an invalid expiration %q: %w", args[0], cred.Expiration, err)
+		}
+		cred.expiresAt = t
+	}
+	if !cred.valid() {
+		return nil, fmt.Errorf("credential_process %s returned a credential that has already expired (%s)", args[0], cred.Expiration)
+	}
+	return &cred, nil
+}
+
+// backslashEscapes is whether a backslash escapes the next character in a
+// credential_process. Not on Windows, where it separates path elements.
+var backslashEscapes = runtime.GOOS != "windows"
+
+// splitCommandLine splits a credential_process value into arguments the
+// way a POSIX shell would, without running one: whitespace separates
+// arguments, single quotes keep text literally, and double quotes and
+// backslashes escape as in sh.
+func splitCommandLine(s string) ([]string, error) {
+	var args []string
+	var cur strings.Builder
+	inArg := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		case c == '\'':
+			inArg = true
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote in %q", s)
+			}
+			cur.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+		case c == '"':
+			inArg = true
+			for i++; ; i++ {
+				if i >= len(s) {
+					return nil, fmt.Errorf("unterminated double quote in %q", s)
+				}
+				if s[i] == '"' {
+					break
+				}
+				if s[i] == '\\' && backslashEscapes && i+1 < len(s) && strings.IndexByte("\"\\$`", s[i+1]) >= 0 {
+					i++
+				}
+				cur.WriteByte(s[i])
+			}
+		case c == '\\' && backslashEscapes && i+1 < len(s):
+			inArg = true
+			i++
+			cur.WriteByte(s[i])
+		default:
+			inArg = true
+			cur.WriteByte(c)
+		}
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
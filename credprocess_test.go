@@ -0,0 +1,132 @@
+package seclai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestCredentialProcessHelper is not a test: it is the credential_process
+// the tests below run, re-invoking the test binary. It appends a line to
+// the file named by its argument for each run and prints SECLAI_HELPER_OUTPUT.
+func TestCredentialProcessHelper(t *testing.T) {
+	if os.Getenv("SECLAI_HELPER_OUTPUT") == "" {
+		t.Skip("only run as a credential_process")
+	}
+	log := os.Args[len(os.Args)-1]
+	f, _ := os.OpenFile(log, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	fmt.Fprintln(f, "run")
+	f.Close()
+	if os.Getenv("SECLAI_HELPER_FAIL") != "" {
+		fmt.Fprintln(os.Stderr, "vault is sealed")
+		os.Exit(3)
+	}
+	fmt.Print(os.Getenv("SECLAI_HELPER_OUTPUT"))
+	os.Exit(0)
+}
+
+// credentialProcessClient configures a profile whose credential_process is
+// the helper above, and returns a client for it, the helper's run log and
+// the Authorization and x-api-key headers the API saw last.
+func credentialProcessClient(t *testing.T, output string) (*Client, string, *http.Header) {
+	t.Helper()
+	dir := t.TempDir()
+	runs := filepath.Join(dir, "runs.log")
+	config := fmt.Sprintf("[default]\ncredential_process = '%s' -test.run=^TestCredentialProcessHelper$ %s\n", os.Args[0], runs)
+	if err := os.WriteFile(filepath.Join(dir, "config"), []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SECLAI_API_KEY", "")
+	t.Setenv("SECLAI_HELPER_OUTPUT", output)
+	t.Setenv("GORACE", "atexit_sleep_ms=0") // a -race helper otherwise lingers 1s on exit
+
+	seen := &http.Header{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*seen = r.Header.Clone()
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(srv.Close)
+	c, err := NewClient(Options{ConfigDir: dir, BaseURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, runs, seen
+}
+
+func countRuns(t *testing.T, path string) int {
+	t.Helper()
+	raw, _ := os.ReadFile(path)
+	return strings.Count(string(raw), "run\n")
+}
+
+func TestCredentialProcess_APIKeyCachedUntilExpiry(t *testing.T) {
+	exp := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	c, runs, seen := credentialProcessClient(t, `{"api_key":"sk-helper","expiration":"`+exp+`"}`)
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if err := c.Do(ctx, http.MethodGet, "/me", nil, nil, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := seen.Get("x-api-key"); got != "sk-helper" {
+		t.Fatalf("x-api-key = %q", got)
+	}
+	if n := countRuns(t, runs); n != 1 {
+		t.Fatalf("the helper should run once while its credential is valid, ran %d times", n)
+	}
+
+	// Expire the cached credential: the next request runs the helper again.
+	c.auth.credential.expiresAt = time.Now()
+	if err := c.Do(ctx, http.MethodGet, "/me", nil, nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if n := countRuns(t, runs); n != 2 {
+		t.Fatalf("an expired credential should re-run the helper, ran %d times", n)
+	}
+}
+
+func TestCredentialProcess_AccessToken(t *testing.T) {
+	c, _, seen := credentialProcessClient(t, `{"access_token":"tok"}`)
+	if err := c.Do(context.Background(), http.MethodGet, "/me", nil, nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := seen.Get("Authorization"); got != "Bearer tok" {
+		t.Fatalf("Authorization = %q", got)
+	}
+}
+
+func TestCredentialProcess_Failures(t *testing.T) {
+	for name, tc := range map[string]struct{ output, fail, want string }{
+		"exit status":     {output: "{}", fail: "1", want: "vault is sealed"},
+		"not json":        {output: "sk-raw", want: "invalid JSON"},
+		"no credential":   {output: `{"expiration":"2099-01-01T00:00:00Z"}`, want: "exactly one"},
+		"already expired": {output: `{"api_key":"k","expiration":"2000-01-01T00:00:00Z"}`, want: "already expired"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, _, _ := credentialProcessClient(t, tc.output)
+			t.Setenv("SECLAI_HELPER_FAIL", tc.fail)
+			err := c.Do(context.Background(), http.MethodGet, "/me", nil, nil, nil, nil)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected an error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestSplitCommandLine(t *testing.T) {
+	got, err := splitCommandLine(`/usr/bin/vault read -field=key 'secret/seclai prod' "a \"b\"" c\ d`)
+	want := []string{"/usr/bin/vault", "read", "-field=key", "secret/seclai prod", `a "b"`, "c d"}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Fatalf("splitCommandLine = %q, %v; want %q", got, err, want)
+	}
+	if _, err := splitCommandLine(`helper 'unterminated`); err == nil {
+		t.Fatal("an unterminated quote should be an error")
+	}
+}
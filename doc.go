@@ -24,7 +24,8 @@
 //  2. Explicit AccessToken option (static string)
 //  3. Explicit AccessTokenProvider option (function called per request)
 //  4. SECLAI_API_KEY environment variable
-//  5. SSO profile from ~/.seclai/config + cached tokens in ~/.seclai/sso/cache/
+//  5. The profile's api_key, api_key_file or credential_process in ~/.seclai/config
+//  6. SSO profile from ~/.seclai/config + cached tokens in ~/.seclai/sso/cache/
 //
 // API key authentication:
 //
@@ -69,6 +70,12 @@
 //	// List agents
 //	agents, err := client.ListAgents(ctx, seclai.ListOptions{Page: 1, Limit: 20})
 //
+//	// Iterate over every agent, page by page
+//	for agent, err := range client.AllAgents(ctx, seclai.ListOptions{Limit: 100}) {
+//	    if err != nil { ... }
+//	    fmt.Println(agent.Name)
+//	}
+//
 //	// Run an agent and poll for completion
 //	result, err := client.RunAgentAndPoll(ctx, agentID, seclai.AgentRunRequest{}, nil)
 //
@@ -86,6 +93,8 @@
 //   - [ConfigurationError]: invalid or missing client configuration
 //   - [APIStatusError]: non-2xx HTTP responses
 //   - [APIValidationError]: HTTP 422 validation errors (embeds APIStatusError)
+//   - [InsufficientCreditsError]: HTTP 402 responses with the decoded credit detail (embeds APIStatusError)
+//   - [RateLimitError]: HTTP 429 responses with the parsed Retry-After (embeds APIStatusError)
 //   - [StreamingError]: SSE stream failures (includes RunID when available)
 //
 // # Low-Level Access
@@ -1,6 +1,13 @@
 package seclai
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // ConfigurationError indicates invalid or missing client configuration.
 type ConfigurationError struct {
@@ -53,6 +60,72 @@ func (e *APIValidationError) Error() string {
 	return (&e.APIStatusError).Error()
 }
 
+// RateLimitError is returned for HTTP 429 responses.
+//
+// It unwraps to its [APIStatusError], so code matching on that type keeps
+// seeing 429s.
+type RateLimitError struct {
+	APIStatusError
+	// RetryAfter is the wait the server asked for in its Retry-After header,
+	// or zero when it named none.
+	RetryAfter time.Duration
+	// Limit is the request quota for the current window, if the server reported it.
+	Limit *int
+	// Remaining is the number of requests left in the current window, if reported.
+	Remaining *int
+	// Reset is how long until the current window resets, if reported.
+	Reset *time.Duration
+	// Header holds every rate-limit header on the response (Retry-After,
+	// RateLimit-* and X-RateLimit-*), unparsed.
+	Header http.Header
+}
+
+func (e *RateLimitError) Error() string {
+	if e == nil {
+		return "seclai: rate limit error"
+	}
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s (retry after %s)", (&e.APIStatusError).Error(), e.RetryAfter)
+	}
+	return (&e.APIStatusError).Error()
+}
+
+func (e *RateLimitError) Unwrap() error {
+	if e == nil {
+		return nil
+	}
+	return &e.APIStatusError
+}
+
+// InsufficientCreditsError is returned for HTTP 402 responses, when the
+// account has exhausted its credits.
+//
+// It unwraps to its [APIStatusError], so code matching on that type keeps
+// seeing 402s.
+type InsufficientCreditsError struct {
+	APIStatusError
+	// Detail is the decoded error code, message and account id. It is nil
+	// when the body was not the documented envelope.
+	Detail *InsufficientCreditsDetail
+}
+
+func (e *InsufficientCreditsError) Error() string {
+	if e == nil {
+		return "seclai: insufficient credits"
+	}
+	if e.Detail != nil && e.Detail.Message != "" {
+		return fmt.Sprintf("seclai: insufficient credits (%d) %s %s: %s", e.StatusCode, e.Method, e.URL, e.Detail.Message)
+	}
+	return (&e.APIStatusError).Error()
+}
+
+func (e *InsufficientCreditsError) Unwrap() error {
+	if e == nil {
+		return nil
+	}
+	return &e.APIStatusError
+}
+
 // StreamingError indicates a failure during an SSE streaming operation.
 type StreamingError struct {
 	// Message describes what went wrong.
@@ -70,3 +143,130 @@ func (e *StreamingError) Error() string {
 	}
 	return fmt.Sprintf("seclai: streaming error: %s", e.Message)
 }
+
+// OAuthError is an error response from an SSO endpoint, such as a denied or
+// expired device login.
+type OAuthError struct {
+	// StatusCode is the HTTP status code.
+	StatusCode int
+	// Code is the OAuth error code (e.g. "access_denied", "expired_token"),
+	// empty when the body was not an OAuth error.
+	Code string
+	// Description is the error_description, or the raw body when Code is
+	// empty.
+	Description string
+}
+
+func (e *OAuthError) Error() string {
+	if e == nil {
+		return "seclai: oauth error"
+	}
+	msg := e.Code
+	if msg == "" {
+		msg = fmt.Sprintf("HTTP %d", e.StatusCode)
+	}
+	if e.Description != "" {
+		msg += ": " + e.Description
+	}
+	return "seclai: oauth error: " + msg
+}
+
+// newAPIError builds the typed error for a non-2xx response whose body has
+// been read into raw.
+func newAPIError(method, reqURL string, resp *http.Response, raw []byte) error {
+	statusErr := APIStatusError{StatusCode: resp.StatusCode, Method: method, URL: reqURL, ResponseText: strings.TrimSpace(string(raw))}
+	switch resp.StatusCode {
+	case http.StatusUnprocessableEntity:
+		var ve HTTPValidationError
+		if len(raw) > 0 && json.Unmarshal(raw, &ve) == nil && ve.Detail != nil {
+			return &APIValidationError{APIStatusError: statusErr, ValidationError: &ve}
+		}
+		return &APIValidationError{APIStatusError: statusErr}
+	case http.StatusPaymentRequired:
+		var env InsufficientCreditsResponse
+		if len(raw) > 0 && json.Unmarshal(raw, &env) == nil && env.Detail != (InsufficientCreditsDetail{}) {
+			return &InsufficientCreditsError{APIStatusError: statusErr, Detail: &env.Detail}
+		}
+		return &InsufficientCreditsError{APIStatusError: statusErr}
+	case http.StatusTooManyRequests:
+		return newRateLimitError(statusErr, resp.Header)
+	}
+	return &statusErr
+}
+
+// newDownloadError is newAPIError for the download endpoints, which have
+// never decoded a 422 and keep returning it as a plain [APIStatusError].
+func newDownloadError(method, reqURL string, resp *http.Response, raw []byte) error {
+	if resp.StatusCode == http.StatusUnprocessableEntity {
+		return &APIStatusError{StatusCode: resp.StatusCode, Method: method, URL: reqURL, ResponseText: strings.TrimSpace(string(raw))}
+	}
+	return newAPIError(method, reqURL, resp, raw)
+}
+
+// newRateLimitError parses the rate-limit headers of a 429 response.
+//
+// Both the IETF draft names (RateLimit-Limit) and the older X-RateLimit-*
+// names are read. Reset is taken as seconds until the window resets, except
+// that a value too large to be a delta is read as a Unix timestamp.
+func newRateLimitError(statusErr APIStatusError, h http.Header) *RateLimitError {
+	e := &RateLimitError{APIStatusError: statusErr, Header: http.Header{}}
+	for k, v := range h {
+		lk := strings.ToLower(k)
+		if lk == "retry-after" || strings.HasPrefix(lk, "ratelimit") || strings.HasPrefix(lk, "x-ratelimit") {
+			e.Header[k] = v
+		}
+	}
+	e.RetryAfter, _ = parseRetryAfter(h.Get("Retry-After"), time.Now())
+	e.Limit = headerInt(h, "RateLimit-Limit", "X-RateLimit-Limit")
+	e.Remaining = headerInt(h, "RateLimit-Remaining", "X-RateLimit-Remaining")
+	if n := headerInt(h, "RateLimit-Reset", "X-RateLimit-Reset"); n != nil {
+		reset := time.Duration(*n) * time.Second
+		// Anything past a year is a timestamp, not a delta.
+		if *n > 365*24*60*60 {
+			reset = time.Until(time.Unix(int64(*n), 0))
+			if reset < 0 {
+				reset = 0
+			}
+		}
+		e.Reset = &reset
+	}
+	return e
+}
+
+// parseRetryAfter reads a Retry-After value, either delay-seconds or an
+// HTTP-date, relative to now. ok is false when the value is absent or invalid.
+func parseRetryAfter(v string, now time.Time) (d time.Duration, ok bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	if d = t.Sub(now); d < 0 {
+		d = 0
+	}
+	return d, true
+}
+
+// headerInt returns the first of names that holds an integer. Values of the
+// structured-field form "100;w=60" are read up to the first semicolon.
+func headerInt(h http.Header, names ...string) *int {
+	for _, name := range names {
+		v := h.Get(name)
+		if i := strings.IndexByte(v, ';'); i >= 0 {
+			v = v[:i]
+		}
+		if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+			return &n
+		}
+	}
+	return nil
+}
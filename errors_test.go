@@ -0,0 +1,109 @@
+package seclai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const insufficientCreditsBody = `{"detail":{"error":"insufficient_credits","message":"Account has no credits left","account_id":"acct-1"}}`
+
+func TestInsufficientCreditsError_DecodesDetail(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(402)
+		_, _ = io.WriteString(w, insufficientCreditsBody)
+	}))
+	t.Cleanup(srv.Close)
+
+	c, _ := NewClient(Options{APIKey: "k", BaseURL: srv.URL})
+	_, err := c.RunAgent(context.Background(), "a_1", AgentRunRequest{})
+
+	var ice *InsufficientCreditsError
+	if !errors.As(err, &ice) {
+		t.Fatalf("expected InsufficientCreditsError, got %T %v", err, err)
+	}
+	if ice.Detail == nil {
+		t.Fatal("expected decoded detail")
+	}
+	if ice.Detail.Error != "insufficient_credits" || ice.Detail.AccountId != "acct-1" {
+		t.Fatalf("unexpected detail: %+v", *ice.Detail)
+	}
+	if !strings.Contains(err.Error(), "Account has no credits left") {
+		t.Fatalf("expected the message in Error(), got %q", err.Error())
+	}
+	var apiErr *APIStatusError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 402 {
+		t.Fatalf("expected InsufficientCreditsError to unwrap to a 402 APIStatusError, got %v", apiErr)
+	}
+}
+
+func TestInsufficientCreditsError_UndocumentedBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(402)
+		_, _ = io.WriteString(w, `payment required`)
+	}))
+	t.Cleanup(srv.Close)
+
+	c, _ := NewClient(Options{APIKey: "k", BaseURL: srv.URL})
+	err := c.Do(context.Background(), http.MethodGet, "/agents", nil, nil, nil, nil)
+	var ice *InsufficientCreditsError
+	if !errors.As(err, &ice) {
+		t.Fatalf("expected InsufficientCreditsError, got %T", err)
+	}
+	if ice.Detail != nil || ice.ResponseText != "payment required" {
+		t.Fatalf("expected no detail and the raw text, got %+v", ice)
+	}
+}
+
+func TestInsufficientCreditsError_ReturnedFromEveryPath(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(402)
+		_, _ = io.WriteString(w, insufficientCreditsBody)
+	}))
+	t.Cleanup(srv.Close)
+	c, _ := NewClient(Options{APIKey: "k", BaseURL: srv.URL})
+	ctx := context.Background()
+
+	_, uploadErr := c.UploadAgentInput(ctx, "a_1", UploadFileRequest{File: []byte("x"), FileName: "a.txt"})
+	_, waitErr := c.RunStreamingAgentAndWait(ctx, "a_1", AgentRunStreamRequest{})
+	_, errCh := c.RunStreamingAgent(ctx, "a_1", AgentRunStreamRequest{})
+	streamErr := <-errCh
+	_, exportErr := c.DownloadSourceExport(ctx, "s_1", "e_1")
+	_, attachErr := c.DownloadAgentRunAttachment(ctx, "r_1", "att", "")
+
+	for name, err := range map[string]error{
+		"upload": uploadErr, "stream-and-wait": waitErr, "stream": streamErr,
+		"export download": exportErr, "attachment download": attachErr,
+	} {
+		var ice *InsufficientCreditsError
+		if !errors.As(err, &ice) || ice.Detail == nil || ice.Detail.AccountId != "acct-1" {
+			t.Errorf("%s: expected InsufficientCreditsError with detail, got %T %v", name, err, err)
+		}
+	}
+}
+
+func TestDownloads_ValidationErrorStaysAPIStatusError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(422)
+		_, _ = io.WriteString(w, `{"detail":[{"loc":["path","export_id"],"msg":"bad id","type":"value_error"}]}`)
+	}))
+	t.Cleanup(srv.Close)
+	c, _ := NewClient(Options{APIKey: "k", BaseURL: srv.URL})
+	ctx := context.Background()
+
+	_, exportErr := c.DownloadSourceExport(ctx, "s_1", "e_1")
+	_, attachErr := c.DownloadAgentRunAttachment(ctx, "r_1", "att", "")
+	for name, err := range map[string]error{"DownloadSourceExport": exportErr, "DownloadAgentRunAttachment": attachErr} {
+		apiErr, ok := err.(*APIStatusError)
+		if !ok || apiErr.StatusCode != 422 || !strings.Contains(apiErr.ResponseText, "bad id") {
+			t.Errorf("%s: expected a 422 APIStatusError, got %T %v", name, err, err)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package seclai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+const (
+	// ssoLockTimeout bounds the wait for another process's SSO cache refresh.
+	// It covers a refresh's 30s HTTP timeout with room to spare.
+	ssoLockTimeout = 45 * time.Second
+	// staleLockAge is when an exclusive-create lock file is presumed left
+	// behind by a process that died holding it: past a refresh's 30s HTTP
+	// timeout, but inside ssoLockTimeout so that a waiter outlasts it. flock
+	// needs no such guess: the kernel releases it with the process.
+	staleLockAge     = 40 * time.Second
+	lockPollInterval = 25 * time.Millisecond
+)
+
+// lockSsoCache takes the cross-process lock that serializes reading,
+// refreshing and writing a profile's SSO cache file. The returned function
+// releases it.
+func lockSsoCache(ctx context.Context, configDir string, profile *SsoProfile) (func(), error) {
+	if err := os.MkdirAll(filepath.Join(configDir, ssoCacheDir), 0700); err != nil {
+		return nil, err
+	}
+	return acquireFileLock(ctx, ssoCachePath(configDir, profile)+".lock", ssoLockTimeout)
+}
+
+// acquireFileLock polls tryLock until it gets path's lock, timeout passes or
+// ctx is done.
+func acquireFileLock(ctx context.Context, path string, timeout time.Duration) (func(), error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		release, ok, err := tryLock(path)
+		if err != nil {
+			return nil, fmt.Errorf("lock %s: %w", path, err)
+		}
+		if ok {
+			return release, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out after %s waiting for lock %s held by another process", timeout, path)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+// tryExclusiveLock takes path's lock by creating it exclusively, for
+// platforms without flock. A lock file older than staleLockAge is broken and
+// the lock retried once. The file holds an owner token, so that releasing a
+// lock another process has since broken and retaken leaves theirs alone.
+func tryExclusiveLock(path string) (func(), bool, error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err == nil {
+			owner := strconv.Itoa(os.Getpid()) + " " + randomToken(16) + "\n"
+			_, werr := f.WriteString(owner)
+			if cerr := f.Close(); werr == nil {
+				werr = cerr
+			}
+			if werr != nil {
+				os.Remove(path)
+				return nil, false, werr
+			}
+			return func() {
+				if data, err := os.ReadFile(path); err == nil && string(data) == owner {
+					os.Remove(path)
+				}
+			}, true, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, false, err
+		}
+		info, err := os.Stat(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue // released between the create and the stat
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		if time.Since(info.ModTime()) < staleLockAge {
+			return nil, false, nil
+		}
+		breakStaleLock(path, info)
+	}
+	return nil, false, nil
+}
+
+// breakStaleLock removes path if it is still the file described by info.
+// Another waiter may have broken the same lock and taken a fresh one since
+// the stat, so the file is first renamed aside, where no one else can touch
+// it, and checked; a file that turns out not to be the stale one is linked
+// back into place.
+func breakStaleLock(path string, info os.FileInfo) {
+	aside := path + ".stale-" + randomToken(8)
+	if os.Rename(path, aside) != nil {
+		return // already broken or released
+	}
+	if moved, err := os.Stat(aside); err != nil || !os.SameFile(info, moved) || !moved.ModTime().Equal(info.ModTime()) {
+		_ = os.Link(aside, path)
+	}
+	os.Remove(aside)
+}
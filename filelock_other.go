@@ -0,0 +1,9 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package seclai
+
+// tryLock falls back to an exclusively created lock file, with stale-lock
+// recovery, where flock is unavailable.
+func tryLock(path string) (func(), bool, error) {
+	return tryExclusiveLock(path)
+}
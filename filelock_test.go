@@ -0,0 +1,146 @@
+package seclai
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFileLock_ExcludesAndTimesOut(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "x.lock")
+	release, err := acquireFileLock(context.Background(), path, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := acquireFileLock(context.Background(), path, 50*time.Millisecond); err == nil {
+		t.Fatal("a held lock should time out a second holder")
+	}
+	release()
+	release, err = acquireFileLock(context.Background(), path, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("a released lock should be free: %v", err)
+	}
+	release()
+}
+
+func TestExclusiveLock_RecoversStaleLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "x.lock")
+	release, ok, err := tryExclusiveLock(path)
+	if err != nil || !ok {
+		t.Fatalf("tryExclusiveLock = %v, %v", ok, err)
+	}
+	if _, ok, _ := tryExclusiveLock(path); ok {
+		t.Fatal("a fresh lock file should be respected")
+	}
+
+	// Simulate a holder that died without releasing.
+	old := time.Now().Add(-staleLockAge - time.Minute)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	release2, ok, err := tryExclusiveLock(path)
+	if err != nil || !ok {
+		t.Fatalf("a stale lock should be recovered, got %v, %v", ok, err)
+	}
+	release2()
+	release() // the dead holder's release finds nothing to remove
+}
+
+func TestExclusiveLock_BreakingKeepsAFreshLock(t *testing.T) {
+	if staleLockAge >= ssoLockTimeout {
+		t.Fatalf("staleLockAge %s must be shorter than ssoLockTimeout %s, or waiters time out first", staleLockAge, ssoLockTimeout)
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "x.lock")
+	if err := os.WriteFile(path, []byte("dead\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-staleLockAge - time.Minute)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	stale, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Another waiter breaks the stale lock and takes it first.
+	release, ok, err := tryExclusiveLock(path)
+	if err != nil || !ok {
+		t.Fatalf("tryExclusiveLock = %v, %v", ok, err)
+	}
+	// This waiter acts on its stat of the stale file, which is out of date.
+	breakStaleLock(path, stale)
+	if _, ok, _ := tryExclusiveLock(path); ok {
+		t.Fatal("breaking a stale lock must not remove the fresh one that replaced it")
+	}
+	release()
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Fatalf("expected no lock files left behind, found %v", entries)
+	}
+}
+
+func TestExclusiveLock_ReleaseLeavesAnotherOwnersLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "x.lock")
+	release, ok, err := tryExclusiveLock(path)
+	if err != nil || !ok {
+		t.Fatalf("tryExclusiveLock = %v, %v", ok, err)
+	}
+	// The lock was broken as stale and retaken while this holder was stuck.
+	if err := os.WriteFile(path, []byte("other\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	release()
+	if data, err := os.ReadFile(path); err != nil || string(data) != "other\n" {
+		t.Fatalf("release removed another owner's lock: %q, %v", data, err)
+	}
+}
+
+// TestResolveSsoToken_OneRefreshAcrossClients stands in for several processes
+// sharing a cache: each authState has its own refreshMu, so only the file
+// lock keeps them from all spending the same rotating refresh token.
+func TestResolveSsoToken_OneRefreshAcrossClients(t *testing.T) {
+	var refreshes atomic.Int32
+	profile, hc := newSsoServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.Form.Get("refresh_token") != "rt-1" {
+			w.WriteHeader(400)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		refreshes.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"at-2","refresh_token":"rt-2","expires_in":3600}`))
+	})
+	dir := t.TempDir()
+	if err := WriteSsoCache(dir, profile, &SsoCacheEntry{
+		AccessToken:  "at-1",
+		RefreshToken: "rt-1",
+		ExpiresAt:    time.Now().Add(-time.Minute).UTC().Format(time.RFC3339),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			state := &authState{mode: authModeSSO, ssoProfile: profile, configDir: dir, autoRefresh: true, httpClient: hc}
+			token, err := resolveSsoToken(context.Background(), state)
+			if err != nil || token != "at-2" {
+				t.Errorf("resolveSsoToken = %q, %v", token, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := refreshes.Load(); n != 1 {
+		t.Fatalf("expected one refresh, got %d", n)
+	}
+}
@@ -1,10 +1,11 @@
 module github.com/seclai/seclai-go
 
-go 1.22
+go 1.23
 
 require github.com/oapi-codegen/runtime v1.1.2
 
 require (
 	github.com/apapsch/go-jsonmerge/v2 v2.0.0 // indirect
-	github.com/google/uuid v1.5.0 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/stretchr/testify v1.9.0 // indirect
 )
@@ -0,0 +1,99 @@
+// Package routes maps request paths back to the OpenAPI path templates they
+// were built from, e.g. /agents/a_1/runs to /agents/{agent_id}/runs.
+package routes
+
+//go:generate go run ../../cmd/routegen -in ../../openapi/seclai.openapi.json -out routes_gen.go
+
+import "strings"
+
+// Route is a path template and the parameter values matched against it.
+type Route struct {
+	// Template is the path template as written in the spec.
+	Template string
+	// Params holds the path parameters by name, e.g. "agent_id".
+	Params map[string]string
+}
+
+type compiled struct {
+	template string
+	segments []string
+	literals int
+}
+
+var table = compile(templates)
+
+func compile(ts []string) []compiled {
+	out := make([]compiled, 0, len(ts))
+	for _, t := range ts {
+		segs := split(t)
+		n := 0
+		for _, s := range segs {
+			if !isParam(s) {
+				n++
+			}
+		}
+		out = append(out, compiled{template: t, segments: segs, literals: n})
+	}
+	return out
+}
+
+// Match finds the template for path. The path may carry a prefix the spec does
+// not know about, such as a base URL's /api, so templates are matched against
+// its trailing segments. When several templates match, the most specific one
+// wins: /agents/runs/search beats /agents/runs/{run_id}.
+func Match(path string) (Route, bool) {
+	segs := split(path)
+	var best *compiled
+	var bestOffset int
+	for i := range table {
+		t := &table[i]
+		offset := len(segs) - len(t.segments)
+		if offset < 0 || !t.matches(segs[offset:]) {
+			continue
+		}
+		if best == nil || t.literals > best.literals || (t.literals == best.literals && offset < bestOffset) {
+			best, bestOffset = t, offset
+		}
+	}
+	if best == nil {
+		return Route{}, false
+	}
+
+	r := Route{Template: best.template}
+	for i, s := range best.segments {
+		if isParam(s) {
+			if r.Params == nil {
+				r.Params = make(map[string]string)
+			}
+			r.Params[s[1:len(s)-1]] = segs[bestOffset+i]
+		}
+	}
+	return r, true
+}
+
+func (t *compiled) matches(segs []string) bool {
+	for i, s := range t.segments {
+		if isParam(s) {
+			if segs[i] == "" {
+				return false
+			}
+			continue
+		}
+		if s != segs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func split(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+func isParam(seg string) bool {
+	return len(seg) > 2 && seg[0] == '{' && seg[len(seg)-1] == '}'
+}
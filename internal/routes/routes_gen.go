@@ -0,0 +1,131 @@
+// Code generated by cmd/routegen from openapi/seclai.openapi.json. DO NOT EDIT.
+
+package routes
+
+// templates lists every path template in the OpenAPI spec.
+var templates = []string{
+	"/agents",
+	"/agents/agent-email-optouts",
+	"/agents/agent-email-optouts/{optout_id}",
+	"/agents/blocked-email-senders",
+	"/agents/blocked-email-senders/mode",
+	"/agents/blocked-email-senders/{blocked_id}",
+	"/agents/evaluation-criteria/{criteria_id}",
+	"/agents/evaluation-criteria/{criteria_id}/compatible-runs",
+	"/agents/evaluation-criteria/{criteria_id}/results",
+	"/agents/evaluation-criteria/{criteria_id}/summary",
+	"/agents/evaluation-results/non-manual-summary",
+	"/agents/inbound-email-rejections",
+	"/agents/inbound-email-status",
+	"/agents/inbound-email-status/cancel-queued",
+	"/agents/inbound-email-status/resume",
+	"/agents/preview-import",
+	"/agents/runs/search",
+	"/agents/runs/{run_id}",
+	"/agents/{agent_id}",
+	"/agents/{agent_id}/ai-assistant/conversations",
+	"/agents/{agent_id}/ai-assistant/generate-steps",
+	"/agents/{agent_id}/ai-assistant/step-config",
+	"/agents/{agent_id}/ai-assistant/{conversation_id}",
+	"/agents/{agent_id}/attachment-references",
+	"/agents/{agent_id}/callers",
+	"/agents/{agent_id}/definition",
+	"/agents/{agent_id}/disable",
+	"/agents/{agent_id}/enable",
+	"/agents/{agent_id}/evaluation-criteria",
+	"/agents/{agent_id}/evaluation-criteria/test-draft",
+	"/agents/{agent_id}/evaluation-results",
+	"/agents/{agent_id}/evaluation-runs",
+	"/agents/{agent_id}/export",
+	"/agents/{agent_id}/input-uploads/{upload_id}",
+	"/agents/{agent_id}/runs",
+	"/agents/{agent_id}/runs/stream",
+	"/agents/{agent_id}/runs/{run_id}/evaluation-results",
+	"/agents/{agent_id}/triggers/{trigger_id}/email-config",
+	"/agents/{agent_id}/upload-input",
+	"/ai-assistant/feedback",
+	"/ai-assistant/knowledge-base",
+	"/ai-assistant/memory-bank",
+	"/ai-assistant/memory-bank/last-conversation",
+	"/ai-assistant/memory-bank/{conversation_id}",
+	"/ai-assistant/solution",
+	"/ai-assistant/source",
+	"/ai-assistant/{conversation_id}/accept",
+	"/ai-assistant/{conversation_id}/decline",
+	"/alerts",
+	"/alerts/configs",
+	"/alerts/configs/{config_id}",
+	"/alerts/organization-preferences/list",
+	"/alerts/organization-preferences/{organization_id}/{alert_type}",
+	"/alerts/{alert_id}",
+	"/alerts/{alert_id}/comments",
+	"/alerts/{alert_id}/status",
+	"/alerts/{alert_id}/subscribe",
+	"/alerts/{alert_id}/unsubscribe",
+	"/contents/{source_connection_content_version}",
+	"/contents/{source_connection_content_version}/embeddings",
+	"/contents/{source_connection_content_version}/upload",
+	"/docs-search",
+	"/email-domains",
+	"/email-domains/use-shared-domain",
+	"/email-domains/{domain_id}",
+	"/email-domains/{domain_id}/dmarc",
+	"/email-domains/{domain_id}/primary",
+	"/email-domains/{domain_id}/test-email",
+	"/email-domains/{domain_id}/verify",
+	"/governance/ai-assistant",
+	"/governance/ai-assistant/conversations",
+	"/governance/ai-assistant/{conversation_id}/accept",
+	"/governance/ai-assistant/{conversation_id}/decline",
+	"/knowledge_bases",
+	"/knowledge_bases/{knowledge_base_id}",
+	"/me",
+	"/memory_banks",
+	"/memory_banks/ai-assistant",
+	"/memory_banks/ai-assistant/last-conversation",
+	"/memory_banks/ai-assistant/{conversation_id}",
+	"/memory_banks/templates",
+	"/memory_banks/test-compaction",
+	"/memory_banks/{memory_bank_id}",
+	"/memory_banks/{memory_bank_id}/agents",
+	"/memory_banks/{memory_bank_id}/compact",
+	"/memory_banks/{memory_bank_id}/source",
+	"/memory_banks/{memory_bank_id}/stats",
+	"/memory_banks/{memory_bank_id}/test-compaction",
+	"/models",
+	"/models/alerts",
+	"/models/alerts/mark-all-read",
+	"/models/alerts/unread-count",
+	"/models/alerts/{alert_id}/read",
+	"/models/generation-tiers",
+	"/models/playground/experiments",
+	"/models/playground/experiments/{experiment_id}",
+	"/models/playground/experiments/{experiment_id}/cancel",
+	"/models/{model_id}/details",
+	"/models/{model_id}/recommendations",
+	"/search",
+	"/solutions",
+	"/solutions/{solution_id}",
+	"/solutions/{solution_id}/agents",
+	"/solutions/{solution_id}/ai-assistant/generate",
+	"/solutions/{solution_id}/ai-assistant/knowledge-base",
+	"/solutions/{solution_id}/ai-assistant/source",
+	"/solutions/{solution_id}/ai-assistant/{conversation_id}/accept",
+	"/solutions/{solution_id}/ai-assistant/{conversation_id}/decline",
+	"/solutions/{solution_id}/conversations",
+	"/solutions/{solution_id}/conversations/{conversation_id}",
+	"/solutions/{solution_id}/knowledge-bases",
+	"/solutions/{solution_id}/source-connections",
+	"/sources",
+	"/sources/{source_connection_id}",
+	"/sources/{source_connection_id}/embedding-migration",
+	"/sources/{source_connection_id}/embedding-migration/cancel",
+	"/sources/{source_connection_id}/exports",
+	"/sources/{source_connection_id}/exports/estimate",
+	"/sources/{source_connection_id}/exports/{export_id}",
+	"/sources/{source_connection_id}/exports/{export_id}/cancel",
+	"/sources/{source_connection_id}/exports/{export_id}/download",
+	"/sources/{source_connection_id}/upload",
+	"/v2/agent-runs/{run_id}/attachments/{attachment_id}",
+	"/version",
+}
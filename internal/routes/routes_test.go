@@ -0,0 +1,61 @@
+package routes
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestTemplatesMatchTheSpec(t *testing.T) {
+	raw, err := os.ReadFile("../../openapi/seclai.openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Paths map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		t.Fatal(err)
+	}
+	var want []string
+	for p := range doc.Paths {
+		want = append(want, p)
+	}
+	sort.Strings(want)
+	if strings.Join(want, "\n") != strings.Join(templates, "\n") {
+		t.Fatal("routes_gen.go is stale; run go generate ./internal/routes")
+	}
+}
+
+func TestMatch(t *testing.T) {
+	cases := []struct {
+		path, template string
+		params         map[string]string
+	}{
+		{"/agents/a_1/runs", "/agents/{agent_id}/runs", map[string]string{"agent_id": "a_1"}},
+		{"/agents/runs/r_1", "/agents/runs/{run_id}", map[string]string{"run_id": "r_1"}},
+		{"/agents/runs/search", "/agents/runs/search", nil},
+		{"/api/agents/a_1/runs", "/agents/{agent_id}/runs", map[string]string{"agent_id": "a_1"}},
+		{"/v2/agent-runs/r_1/attachments/att", "/v2/agent-runs/{run_id}/attachments/{attachment_id}", map[string]string{"run_id": "r_1", "attachment_id": "att"}},
+	}
+	for _, tc := range cases {
+		r, ok := Match(tc.path)
+		if !ok || r.Template != tc.template {
+			t.Errorf("Match(%q) = %q, %v; want %q", tc.path, r.Template, ok, tc.template)
+			continue
+		}
+		for k, v := range tc.params {
+			if r.Params[k] != v {
+				t.Errorf("Match(%q): param %s = %q, want %q", tc.path, k, r.Params[k], v)
+			}
+		}
+		if len(r.Params) != len(tc.params) {
+			t.Errorf("Match(%q): unexpected params %v", tc.path, r.Params)
+		}
+	}
+	if _, ok := Match("/nope"); ok {
+		t.Error("expected no match for an unknown path")
+	}
+}
@@ -0,0 +1,124 @@
+package seclai
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TokenClaims are the claims of an SSO login's tokens, decoded without
+// verifying their signatures: they describe the client's own credentials,
+// which the server verifies, and must not be trusted as proof of identity.
+type TokenClaims struct {
+	// Subject is the user's ID (sub).
+	Subject string
+	// Username is the Cognito username (username, or cognito:username in
+	// an ID token).
+	Username string
+	// Email is the user's email address, from the ID token.
+	Email string
+	// ClientID is the app client the tokens were issued to (client_id, or
+	// aud in an ID token).
+	ClientID string
+	// Groups are the user's Cognito groups (cognito:groups).
+	Groups []string
+	// ExpiresAt is when the access token expires (exp).
+	ExpiresAt time.Time
+	// IssuedAt is when the access token was issued (iat).
+	IssuedAt time.Time
+}
+
+// jwtClaims is the payload of a Cognito access or ID token.
+type jwtClaims struct {
+	Sub             string      `json:"sub"`
+	Username        string      `json:"username"`
+	CognitoUsername string      `json:"cognito:username"`
+	Email           string      `json:"email"`
+	ClientID        string      `json:"client_id"`
+	Aud             any         `json:"aud"`
+	Groups          []string    `json:"cognito:groups"`
+	Exp             json.Number `json:"exp"`
+	Iat             json.Number `json:"iat"`
+}
+
+// Claims decodes the access token's claims, filling in those only the ID
+// token carries, such as the email address. It fails when the access token,
+// or an ID token that is present, is not a JWT.
+func (e *SsoCacheEntry) Claims() (*TokenClaims, error) {
+	var access jwtClaims
+	if err := decodeJWTPayload(e.AccessToken, &access); err != nil {
+		return nil, fmt.Errorf("access token: %w", err)
+	}
+	out := &TokenClaims{
+		Subject:   access.Sub,
+		Username:  access.Username,
+		Email:     access.Email,
+		ClientID:  access.ClientID,
+		Groups:    access.Groups,
+		ExpiresAt: unixClaim(access.Exp),
+		IssuedAt:  unixClaim(access.Iat),
+	}
+	if e.IDToken == "" {
+		return out, nil
+	}
+	var id jwtClaims
+	if err := decodeJWTPayload(e.IDToken, &id); err != nil {
+		return nil, fmt.Errorf("ID token: %w", err)
+	}
+	if out.Subject == "" {
+		out.Subject = id.Sub
+	}
+	if out.Username == "" {
+		out.Username = id.CognitoUsername
+	}
+	if out.Email == "" {
+		out.Email = id.Email
+	}
+	if aud, ok := id.Aud.(string); ok && out.ClientID == "" {
+		out.ClientID = aud
+	}
+	if out.Groups == nil {
+		out.Groups = id.Groups
+	}
+	return out, nil
+}
+
+// decodeJWTPayload decodes the payload of a JWT into v. The signature is not
+// checked: the client only reads its own tokens, which the server verifies.
+func decodeJWTPayload(token string, v any) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return errors.New("not a JWT")
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return errors.New("JWT payload is not base64url")
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("JWT payload is not JSON: %w", err)
+	}
+	return nil
+}
+
+// jwtExpiry returns the exp claim of a JWT. ok is false when token is not a
+// JWT or has no exp.
+func jwtExpiry(token string) (t time.Time, ok bool) {
+	var claims jwtClaims
+	if decodeJWTPayload(token, &claims) != nil {
+		return time.Time{}, false
+	}
+	t = unixClaim(claims.Exp)
+	return t, !t.IsZero()
+}
+
+// unixClaim reads a NumericDate claim, zero when it is absent or invalid.
+func unixClaim(n json.Number) time.Time {
+	secs, err := n.Float64()
+	if err != nil || secs <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(secs*float64(time.Second)))
+}
@@ -0,0 +1,132 @@
+package seclai
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testJWT returns an unsigned JWT carrying claims.
+func testJWT(t *testing.T, claims map[string]any) string {
+	t.Helper()
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "eyJhbGciOiJub25lIn0." + base64.RawURLEncoding.EncodeToString(payload) + ".sig"
+}
+
+func TestJWTExpiry(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Truncate(time.Second)
+	if got, ok := jwtExpiry(testJWT(t, map[string]any{"exp": exp.Unix()})); !ok || !got.Equal(exp) {
+		t.Fatalf("jwtExpiry = %v, %v", got, ok)
+	}
+	for _, tok := range []string{"opaque", "a.b.c", testJWT(t, map[string]any{"sub": "u"})} {
+		if _, ok := jwtExpiry(tok); ok {
+			t.Errorf("jwtExpiry(%q) should fail", tok)
+		}
+	}
+	if err := decodeJWTPayload("a.!!.c", &struct{}{}); err == nil {
+		t.Error("expected an error for a bad payload")
+	}
+}
+
+func TestSsoCacheEntry_Claims(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Truncate(time.Second)
+	iat := exp.Add(-time.Hour)
+	entry := &SsoCacheEntry{
+		AccessToken: testJWT(t, map[string]any{
+			"sub": "user-1", "username": "ada", "client_id": "client-1",
+			"cognito:groups": []string{"admins"}, "exp": exp.Unix(), "iat": iat.Unix(),
+		}),
+		IDToken: testJWT(t, map[string]any{
+			"sub": "user-1", "email": "ada@example.com", "aud": "client-1", "cognito:username": "ada",
+		}),
+	}
+	got, err := entry.Claims()
+	if err != nil {
+		t.Fatalf("Claims: %v", err)
+	}
+	want := &TokenClaims{
+		Subject: "user-1", Username: "ada", Email: "ada@example.com", ClientID: "client-1",
+		Groups: []string{"admins"}, ExpiresAt: exp, IssuedAt: iat,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Claims() =\n %+v\nwant\n %+v", got, want)
+	}
+
+	if _, err := (&SsoCacheEntry{AccessToken: "opaque"}).Claims(); err == nil {
+		t.Error("an opaque access token should fail")
+	}
+	if _, err := (&SsoCacheEntry{AccessToken: entry.AccessToken, IDToken: "x.y"}).Claims(); err == nil {
+		t.Error("a corrupt ID token should fail")
+	}
+}
+
+func TestIsTokenValid_FallsBackToExpClaim(t *testing.T) {
+	future := testJWT(t, map[string]any{"exp": time.Now().Add(time.Hour).Unix()})
+	past := testJWT(t, map[string]any{"exp": time.Now().Add(-time.Hour).Unix()})
+	for _, expiresAt := range []string{"", "not-a-time"} {
+		if !IsTokenValid(&SsoCacheEntry{AccessToken: future, ExpiresAt: expiresAt}) {
+			t.Errorf("ExpiresAt %q: the exp claim says the token is valid", expiresAt)
+		}
+		if IsTokenValid(&SsoCacheEntry{AccessToken: past, ExpiresAt: expiresAt}) {
+			t.Errorf("ExpiresAt %q: the exp claim says the token has expired", expiresAt)
+		}
+		if IsTokenValid(&SsoCacheEntry{AccessToken: "opaque", ExpiresAt: expiresAt}) {
+			t.Errorf("ExpiresAt %q: a token without any expiry is not valid", expiresAt)
+		}
+	}
+	// A readable ExpiresAt still wins over the claim.
+	if IsTokenValid(&SsoCacheEntry{AccessToken: future, ExpiresAt: time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)}) {
+		t.Error("ExpiresAt should take precedence over the exp claim")
+	}
+}
+
+func TestClient_Identity(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/me" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"account_id":"8a5c0f3e-5b6e-4f7a-9c1d-2e3f4a5b6c7d","organizations":[]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	dir := writeConfig(t, "[default]\nsso_domain = auth.example.com\nsso_client_id = client-1\n")
+	profile := &SsoProfile{SsoDomain: "auth.example.com", SsoClientID: "client-1"}
+	if err := WriteSsoCache(dir, profile, &SsoCacheEntry{
+		AccessToken: testJWT(t, map[string]any{"sub": "user-1", "exp": time.Now().Add(time.Hour).Unix()}),
+		IDToken:     testJWT(t, map[string]any{"email": "ada@example.com"}),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewClient(Options{ConfigDir: dir, BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	id, err := c.Identity(context.Background())
+	if err != nil {
+		t.Fatalf("Identity: %v", err)
+	}
+	if id.Me == nil || id.Claims == nil || id.Claims.Subject != "user-1" || id.Claims.Email != "ada@example.com" {
+		t.Fatalf("unexpected identity: %+v %+v", id.Me, id.Claims)
+	}
+
+	c, err = NewClient(Options{APIKey: "k", BaseURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err = c.Identity(context.Background())
+	if err != nil {
+		t.Fatalf("Identity: %v", err)
+	}
+	if id.Me == nil || id.Claims != nil {
+		t.Fatalf("an API key has no claims, got %+v", id.Claims)
+	}
+}
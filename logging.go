@@ -0,0 +1,223 @@
+package seclai
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LevelBody is the level at which the client logs request, response and
+// stream bodies. It sits below [slog.LevelDebug], so turning on debug logs
+// does not also log payloads; set the handler's level to LevelBody to opt in.
+// Bodies are truncated to [Options.LogBodyLimit] bytes.
+const LevelBody = slog.LevelDebug - 4
+
+const defaultLogBodyLimit = 4 << 10
+
+const redacted = "[REDACTED]"
+
+// discardHandler drops every record. It stands in for a nil Options.Logger so
+// call sites never need a nil check.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (d discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return d }
+func (d discardHandler) WithGroup(string) slog.Handler           { return d }
+
+// loggerOrDiscard returns l, or a logger that drops everything when l is nil.
+func loggerOrDiscard(l *slog.Logger) *slog.Logger {
+	if l == nil {
+		return slog.New(discardHandler{})
+	}
+	return l
+}
+
+// ── Redaction ───────────────────────────────────────────────────────────────
+
+var (
+	// sensitiveFieldNames are JSON and form fields whose values are never logged.
+	sensitiveFieldNames = `access_token|refresh_token|id_token|api_key|client_secret|device_code|code_verifier|` +
+		`accessToken|refreshToken|idToken|apiKey|clientSecret`
+
+	// A value cut short by truncation has no closing quote, hence the "$".
+	jsonSecretRe = regexp.MustCompile(`"(` + sensitiveFieldNames + `)"(\s*:\s*)"(?:[^"\\]|\\.)*("|$)`)
+	formSecretRe = regexp.MustCompile(`(^|&)(` + sensitiveFieldNames + `)=[^&]*`)
+	bearerRe     = regexp.MustCompile(`(?i)\b(bearer\s+)[^\s",]+`)
+)
+
+// redactor scrubs credentials from anything the client logs.
+type redactor struct {
+	headers map[string]bool // canonical header names whose values are hidden
+	secrets []string        // literal credentials known up front
+}
+
+func newRedactor(state *authState) redactor {
+	r := redactor{headers: map[string]bool{
+		"Authorization":       true,
+		"Proxy-Authorization": true,
+		"X-Api-Key":           true,
+		"Cookie":              true,
+		"Set-Cookie":          true,
+	}}
+	if state == nil {
+		return r
+	}
+	if state.apiKeyHeader != "" {
+		r.headers[http.CanonicalHeaderKey(state.apiKeyHeader)] = true
+	}
+	for _, s := range []string{state.apiKey, state.accessToken} {
+		if s != "" {
+			r.secrets = append(r.secrets, s)
+		}
+	}
+	return r
+}
+
+// string scrubs bearer tokens, known credentials and sensitive JSON or form
+// fields from s.
+func (r redactor) string(s string) string {
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, redacted)
+	}
+	s = bearerRe.ReplaceAllString(s, "${1}"+redacted)
+	s = jsonSecretRe.ReplaceAllString(s, `"$1"$2"`+redacted+`"`)
+	return formSecretRe.ReplaceAllString(s, "$1$2="+redacted)
+}
+
+// header returns h as a log group with credential headers hidden.
+func (r redactor) header(h http.Header) slog.Value {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, k := range keys {
+		v := strings.Join(h[k], ", ")
+		if r.headers[http.CanonicalHeaderKey(k)] {
+			v = redacted
+		} else {
+			v = r.string(v)
+		}
+		attrs = append(attrs, slog.String(k, v))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// ── HTTP logging ────────────────────────────────────────────────────────────
+
+// logTransport logs each request as it goes on the wire and the response
+// that comes back. It sits beneath [Options.Middleware], so what it logs
+// includes any headers middleware added.
+func (c *Client) logTransport(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		ctx := req.Context()
+		if !c.logger.Enabled(ctx, slog.LevelDebug) {
+			return next.RoundTrip(req)
+		}
+
+		reqURL := c.redact.string(req.URL.String())
+		c.logger.LogAttrs(ctx, slog.LevelDebug, "seclai: request",
+			slog.String("method", req.Method),
+			slog.String("url", reqURL),
+			slog.Any("headers", c.redact.header(req.Header)))
+		if c.logger.Enabled(ctx, LevelBody) && req.GetBody != nil {
+			if body, err := req.GetBody(); err == nil {
+				c.logBody(ctx, "seclai: request body", req.Header.Get("Content-Type"), body)
+				body.Close()
+			}
+		}
+
+		start := time.Now()
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			c.logger.LogAttrs(ctx, slog.LevelDebug, "seclai: request failed",
+				slog.String("method", req.Method),
+				slog.String("url", reqURL),
+				slog.Duration("duration", time.Since(start)),
+				slog.String("error", c.redact.string(err.Error())))
+			return nil, err
+		}
+
+		c.logger.LogAttrs(ctx, slog.LevelDebug, "seclai: response",
+			slog.String("method", req.Method),
+			slog.String("url", reqURL),
+			slog.Int("status", resp.StatusCode),
+			slog.Duration("duration", time.Since(start)),
+			slog.Any("headers", c.redact.header(resp.Header)))
+		// Stream events are logged one by one as they are read.
+		ct := resp.Header.Get("Content-Type")
+		if c.logger.Enabled(ctx, LevelBody) && !strings.HasPrefix(ct, "text/event-stream") {
+			resp.Body = c.peekBody(ctx, ct, resp.Body)
+		}
+		return resp, nil
+	})
+}
+
+// peekBody logs the start of a response body and returns a body that still
+// yields all of it.
+func (c *Client) peekBody(ctx context.Context, contentType string, body io.ReadCloser) io.ReadCloser {
+	head, _ := io.ReadAll(io.LimitReader(body, int64(c.logBodyLimit)+1))
+	c.logBody(ctx, "seclai: response body", contentType, bytes.NewReader(head))
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), body), body}
+}
+
+// logBody logs up to Options.LogBodyLimit bytes of r at [LevelBody]. Bodies
+// that are not text, such as file uploads and downloads, are logged by type.
+func (c *Client) logBody(ctx context.Context, msg, contentType string, r io.Reader) {
+	if !textual(contentType) {
+		c.logger.LogAttrs(ctx, LevelBody, msg, slog.String("content_type", contentType), slog.Bool("omitted", true))
+		return
+	}
+	head, _ := io.ReadAll(io.LimitReader(r, int64(c.logBodyLimit)+1))
+	c.logger.LogAttrs(ctx, LevelBody, msg, c.bodyAttrs(head)...)
+}
+
+// bodyAttrs returns a redacted, truncated body as log attributes.
+func (c *Client) bodyAttrs(b []byte) []slog.Attr {
+	truncated := len(b) > c.logBodyLimit
+	if truncated {
+		b = b[:c.logBodyLimit]
+	}
+	attrs := []slog.Attr{slog.String("body", c.redact.string(string(b)))}
+	if truncated {
+		attrs = append(attrs, slog.Bool("truncated", true))
+	}
+	return attrs
+}
+
+// textual reports whether a body of contentType is readable text.
+func textual(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(mt, "text/") || strings.HasSuffix(mt, "json") ||
+		strings.HasSuffix(mt, "xml") || mt == "application/x-www-form-urlencoded"
+}
+
+// ── Stream logging ──────────────────────────────────────────────────────────
+
+// logStreamEvent logs one SSE event at debug level, and its data at [LevelBody].
+func (c *Client) logStreamEvent(ctx context.Context, agentID, event, data string) {
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "seclai: stream event",
+		slog.String("agent_id", agentID), slog.String("event", event))
+	if data != "" && c.logger.Enabled(ctx, LevelBody) {
+		attrs := append([]slog.Attr{slog.String("agent_id", agentID), slog.String("event", event)}, c.bodyAttrs([]byte(data))...)
+		c.logger.LogAttrs(ctx, LevelBody, "seclai: stream event data", attrs...)
+	}
+}
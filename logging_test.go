@@ -0,0 +1,200 @@
+package seclai
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// logBuffer collects log output; the client may log from its stream goroutine.
+type logBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *logBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func newTestLogger(level slog.Level) (*slog.Logger, *logBuffer) {
+	buf := &logBuffer{}
+	return slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: level})), buf
+}
+
+func TestLogger_DebugRedactsCredentials(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"access_token":"resp-secret"}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	logger, buf := newTestLogger(slog.LevelDebug)
+	c, _ := NewClient(Options{APIKey: "sk-live-123", BaseURL: srv.URL, Logger: logger})
+	if err := c.Do(context.Background(), http.MethodPost, "/agents", nil, map[string]string{"name": "x"}, nil, nil); err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"seclai: request", "seclai: response", "status=200", "headers.X-Api-Key=[REDACTED]"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in logs:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "sk-live-123") {
+		t.Fatalf("API key leaked into logs:\n%s", out)
+	}
+	if strings.Contains(out, "body") {
+		t.Fatalf("bodies must only be logged at LevelBody:\n%s", out)
+	}
+}
+
+func TestLogger_BodyLevelTruncatesAndRedacts(t *testing.T) {
+	long := strings.Repeat("a", 100)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"refresh_token":"rt-secret","output":"`+long+`"}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	logger, buf := newTestLogger(LevelBody)
+	c, _ := NewClient(Options{AccessToken: "bearer-secret", BaseURL: srv.URL, Logger: logger, LogBodyLimit: 60})
+	var out map[string]string
+	if err := c.Do(context.Background(), http.MethodPost, "/agents", nil, map[string]string{"api_key": "body-secret"}, nil, &out); err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if out["output"] != long {
+		t.Fatal("logging the body must not consume it")
+	}
+
+	logs := buf.String()
+	for _, secret := range []string{"bearer-secret", "rt-secret", "body-secret"} {
+		if strings.Contains(logs, secret) {
+			t.Fatalf("%s leaked into logs:\n%s", secret, logs)
+		}
+	}
+	for _, want := range []string{"seclai: request body", "seclai: response body", "truncated=true", "headers.Authorization=[REDACTED]"} {
+		if !strings.Contains(logs, want) {
+			t.Errorf("expected %q in logs:\n%s", want, logs)
+		}
+	}
+}
+
+func TestLogger_RetriesAndStreamLifecycle(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(503)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "event: init\ndata: {\"run_id\":\"run_1\"}\n\n")
+		_, _ = io.WriteString(w, "event: done\ndata: {\"run_id\":\"run_1\",\"status\":\"completed\"}\n\n")
+	}))
+	t.Cleanup(srv.Close)
+
+	logger, buf := newTestLogger(slog.LevelDebug)
+	retry := fastRetry()
+	retry.RetryNonIdempotent = true
+	c, _ := NewClient(Options{APIKey: "k", BaseURL: srv.URL, Logger: logger, Retry: retry})
+	if _, err := c.RunStreamingAgentAndWait(context.Background(), "a_1", AgentRunStreamRequest{}); err != nil {
+		t.Fatalf("RunStreamingAgentAndWait: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"level=INFO msg=\"seclai: retrying request\"",
+		"status=503",
+		"seclai: stream opened",
+		"event=init",
+		"event=done",
+		"seclai: stream closed",
+		"events=2",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in logs:\n%s", want, out)
+		}
+	}
+}
+
+func TestLogger_MissingProfileWarning(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config"), []byte("[default]\nsso_region = us-west-2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SECLAI_API_KEY", "")
+
+	logger, buf := newTestLogger(slog.LevelWarn)
+	if _, err := NewClient(Options{ConfigDir: dir, Profile: "missing", Logger: logger}); err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if !strings.Contains(buf.String(), "SSO profile not found") || !strings.Contains(buf.String(), "profile=missing") {
+		t.Fatalf("expected a warning about the missing profile, got:\n%s", buf.String())
+	}
+}
+
+func TestLogger_RedactsRefreshFailures(t *testing.T) {
+	profile, hc := newSsoServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, `{"error":"invalid_grant","echo":"`+r.PostForm.Encode()+`"}`)
+	})
+	t.Setenv("SECLAI_API_KEY", "")
+	t.Setenv("SECLAI_SSO_DOMAIN", profile.SsoDomain)
+	t.Setenv("SECLAI_SSO_CLIENT_ID", profile.SsoClientID)
+
+	store := NewMemoryTokenStore()
+	_ = store.Put(context.Background(), profile, &SsoCacheEntry{
+		AccessToken:  "at-1",
+		RefreshToken: "rt-secret",
+		ExpiresAt:    time.Now().Add(-time.Minute).UTC().Format(time.RFC3339),
+	})
+	logger, buf := newTestLogger(slog.LevelWarn)
+	c, err := NewClient(Options{ConfigDir: t.TempDir(), TokenStore: store, HTTPClient: hc, Logger: logger})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Do(context.Background(), http.MethodGet, "/me", nil, nil, nil, nil); err == nil {
+		t.Fatal("expected the refresh to fail")
+	}
+	if out := buf.String(); !strings.Contains(out, "SSO token refresh failed") || strings.Contains(out, "rt-secret") {
+		t.Fatalf("expected the refresh failure logged without the refresh token, got:\n%s", out)
+	}
+}
+
+func TestRedactor_String(t *testing.T) {
+	r := newRedactor(&authState{apiKey: "sk-123", apiKeyHeader: "X-Custom-Key"})
+	cases := map[string]string{
+		`Bearer eyJhbGciOi.abc`:                    `Bearer [REDACTED]`,
+		`{"access_token": "abc", "x": 1}`:          `{"access_token": "[REDACTED]", "x": 1}`,
+		`{"idToken":"abc\"def"}`:                   `{"idToken":"[REDACTED]"}`,
+		`{"refresh_token":"cut-short-by-truncati`:  `{"refresh_token":"[REDACTED]"`,
+		`grant_type=refresh_token&refresh_token=x`: `grant_type=refresh_token&refresh_token=[REDACTED]`,
+		`https://host/path?key=sk-123`:             `https://host/path?key=[REDACTED]`,
+	}
+	for in, want := range cases {
+		if got := r.string(in); got != want {
+			t.Errorf("redact(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if !r.headers["X-Custom-Key"] {
+		t.Error("expected a custom API key header to be redacted")
+	}
+}
@@ -0,0 +1,482 @@
+package seclai
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LoginOptions configure the interactive SSO login helpers.
+type LoginOptions struct {
+	// ConfigDir is where the token cache is written. Defaults to
+	// SECLAI_CONFIG_DIR, then ~/.seclai.
+	ConfigDir string
+	// HTTPClient is used for the SSO endpoints. Defaults to a client with a
+	// 30s timeout.
+	HTTPClient *http.Client
+	// Scopes are the OAuth scopes to request. Empty requests the app
+	// client's defaults.
+	Scopes []string
+	// Logger receives login progress. Nil discards it.
+	Logger *slog.Logger
+	// TokenStore receives the tokens. Defaults to the plaintext file cache
+	// in ConfigDir.
+	TokenStore TokenStore
+
+	// OpenURL opens the authorization URL for [LoginWithBrowser]. Defaults to
+	// the platform's browser opener (open, xdg-open or rundll32).
+	OpenURL func(authorizeURL string) error
+	// Timeout bounds how long [LoginWithBrowser] waits for the browser to
+	// return. Defaults to 5 minutes.
+	Timeout time.Duration
+	// RedirectPort is the loopback port for [LoginWithBrowser]'s redirect,
+	// which must match a callback URL registered on the app client
+	// (http://127.0.0.1:<port>/callback). 0 picks a free port.
+	RedirectPort int
+}
+
+// deviceCodeGrantType is the RFC 8628 grant type for polling the token
+// endpoint.
+const deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// deviceCodeSlowDown is how much a slow_down response lengthens the polling
+// interval, per RFC 8628 §3.5.
+var deviceCodeSlowDown = 5 * time.Second
+
+// deviceCodeInterval is the polling interval when the server gives none, or
+// one that is not positive, per RFC 8628 §3.2.
+var deviceCodeInterval = 5 * time.Second
+
+// LoginWithDeviceCode signs in with the OAuth device authorization grant
+// (RFC 8628) against profile's SsoDomain and SsoClientID, and writes the
+// tokens to the SSO cache in the default config directory, where clients
+// using the profile find them.
+//
+// prompt is called once with the URL the user should open, on any device,
+// and the code to enter there. LoginWithDeviceCode then polls the token
+// endpoint until the user approves, denies, or the code expires. The
+// context bounds the whole wait.
+func LoginWithDeviceCode(ctx context.Context, profile *SsoProfile, prompt func(verificationURI, userCode string)) (*SsoCacheEntry, error) {
+	return LoginWithDeviceCodeWithOptions(ctx, profile, prompt, LoginOptions{})
+}
+
+// LoginWithDeviceCodeWithOptions is [LoginWithDeviceCode] with a config
+// directory or token store, HTTP client, scopes and logger.
+func LoginWithDeviceCodeWithOptions(ctx context.Context, profile *SsoProfile, prompt func(verificationURI, userCode string), opts LoginOptions) (*SsoCacheEntry, error) {
+	if profile == nil {
+		return nil, &ConfigurationError{Message: "LoginWithDeviceCode requires an SSO profile"}
+	}
+	configDir := resolveConfigDir(opts.ConfigDir)
+	if configDir == "" {
+		return nil, &ConfigurationError{Message: "cannot resolve the config directory; set LoginOptions.ConfigDir or SECLAI_CONFIG_DIR"}
+	}
+	hc := opts.HTTPClient
+	if hc == nil {
+		hc = &http.Client{Timeout: 30 * time.Second}
+	}
+	logger := loggerOrDiscard(opts.Logger)
+
+	form := url.Values{"client_id": {profile.SsoClientID}}
+	if len(opts.Scopes) > 0 {
+		form.Set("scope", strings.Join(opts.Scopes, " "))
+	}
+	var auth struct {
+		DeviceCode      string `json:"device_code"`
+		UserCode        string `json:"user_code"`
+		VerificationURI string `json:"verification_uri"`
+		ExpiresIn       int    `json:"expires_in"`
+		Interval        *int   `json:"interval"`
+	}
+	if err := postForm(ctx, hc, fmt.Sprintf("https://%s/oauth2/device_authorization", profile.SsoDomain), form, &auth); err != nil {
+		return nil, fmt.Errorf("device authorization failed: %w", err)
+	}
+	if auth.DeviceCode == "" || auth.VerificationURI == "" {
+		return nil, fmt.Errorf("device authorization failed: response has no device_code or verification_uri")
+	}
+
+	interval := deviceCodeInterval
+	// An interval of 0 would poll the token endpoint in a tight loop.
+	if auth.Interval != nil && *auth.Interval > 0 {
+		interval = time.Duration(*auth.Interval) * time.Second
+	}
+	if auth.ExpiresIn > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(auth.ExpiresIn)*time.Second)
+		defer cancel()
+	}
+
+	if prompt != nil {
+		prompt(auth.VerificationURI, auth.UserCode)
+	}
+	logger.InfoContext(ctx, "seclai: waiting for device login approval", slog.String("domain", profile.SsoDomain))
+
+	tokenURL := fmt.Sprintf("https://%s/oauth2/token", profile.SsoDomain)
+	poll := url.Values{
+		"grant_type":  {deviceCodeGrantType},
+		"device_code": {auth.DeviceCode},
+		"client_id":   {profile.SsoClientID},
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, fmt.Errorf("device login was not approved in time: %w", ctx.Err())
+			}
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		var tok tokenResponse
+		err := postForm(ctx, hc, tokenURL, poll, &tok)
+		var oe *OAuthError
+		switch {
+		case err == nil:
+			entry := tok.cacheEntry(profile, "")
+			if err := storeLogin(ctx, opts.TokenStore, configDir, profile, entry); err != nil {
+				return nil, err
+			}
+			logger.InfoContext(ctx, "seclai: device login complete", slog.String("expires_at", entry.ExpiresAt))
+			return entry, nil
+		case errors.As(err, &oe) && oe.Code == "authorization_pending":
+		case errors.As(err, &oe) && oe.Code == "slow_down":
+			interval += deviceCodeSlowDown
+		case ctx.Err() != nil:
+			// Report the expiry or cancellation from the top of the loop.
+		default:
+			return nil, fmt.Errorf("device login failed: %w", err)
+		}
+	}
+}
+
+// LoginWithBrowser signs in with the OAuth authorization code grant and PKCE
+// (S256) against profile's SsoDomain and SsoClientID, and writes the tokens
+// to opts.TokenStore, by default the SSO cache.
+//
+// It listens on a loopback port, opens the /oauth2/authorize URL with
+// opts.OpenURL, and waits for the browser to be redirected back with a code,
+// which it exchanges at /oauth2/token. A redirect whose state does not match
+// the one sent is rejected and fails the login, as does one without a code
+// or with an error from the authorization server. The wait ends after opts.Timeout or when ctx is done.
+func LoginWithBrowser(ctx context.Context, profile *SsoProfile, opts LoginOptions) (*SsoCacheEntry, error) {
+	if profile == nil {
+		return nil, &ConfigurationError{Message: "LoginWithBrowser requires an SSO profile"}
+	}
+	configDir := resolveConfigDir(opts.ConfigDir)
+	if configDir == "" {
+		return nil, &ConfigurationError{Message: "cannot resolve the config directory; set LoginOptions.ConfigDir or SECLAI_CONFIG_DIR"}
+	}
+	hc := opts.HTTPClient
+	if hc == nil {
+		hc = &http.Client{Timeout: 30 * time.Second}
+	}
+	openURL := opts.OpenURL
+	if openURL == nil {
+		openURL = openBrowser
+	}
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Minute
+	}
+	logger := loggerOrDiscard(opts.Logger)
+
+	ln, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(opts.RedirectPort)))
+	if err != nil {
+		return nil, fmt.Errorf("start login callback listener: %w", err)
+	}
+	redirectURI := fmt.Sprintf("http://127.0.0.1:%d/callback", ln.Addr().(*net.TCPAddr).Port)
+
+	verifier := randomToken(32)
+	challenge := sha256.Sum256([]byte(verifier))
+	state := randomToken(16)
+
+	q := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {profile.SsoClientID},
+		"redirect_uri":          {redirectURI},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+		"state":                 {state},
+	}
+	if len(opts.Scopes) > 0 {
+		q.Set("scope", strings.Join(opts.Scopes, " "))
+	}
+	authorizeURL := fmt.Sprintf("https://%s/oauth2/authorize?%s", profile.SsoDomain, q.Encode())
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type result struct {
+		entry *SsoCacheEntry
+		err   error
+	}
+	done := make(chan result, 1)
+	var once sync.Once
+	finish := func(r result) { once.Do(func() { done <- r }) }
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		params := r.URL.Query()
+		if subtle.ConstantTimeCompare([]byte(params.Get("state")), []byte(state)) != 1 {
+			http.Error(w, "Login failed: the response did not match this login attempt.", http.StatusBadRequest)
+			finish(result{err: errors.New("login callback state does not match; the redirect did not come from this login attempt")})
+			return
+		}
+		if code := params.Get("error"); code != "" {
+			http.Error(w, "Login failed: "+code, http.StatusBadRequest)
+			finish(result{err: &OAuthError{StatusCode: http.StatusBadRequest, Code: code, Description: params.Get("error_description")}})
+			return
+		}
+		code := params.Get("code")
+		if code == "" {
+			http.Error(w, "Login failed: the response has no authorization code.", http.StatusBadRequest)
+			finish(result{err: &OAuthError{StatusCode: http.StatusBadRequest, Code: "invalid_request", Description: "the login redirect has no authorization code"}})
+			return
+		}
+		var tok tokenResponse
+		err := postForm(ctx, hc, fmt.Sprintf("https://%s/oauth2/token", profile.SsoDomain), url.Values{
+			"grant_type":    {"authorization_code"},
+			"client_id":     {profile.SsoClientID},
+			"code":          {code},
+			"redirect_uri":  {redirectURI},
+			"code_verifier": {verifier},
+		}, &tok)
+		if err != nil {
+			http.Error(w, "Login failed: the authorization code could not be exchanged.", http.StatusBadGateway)
+			finish(result{err: fmt.Errorf("authorization code exchange failed: %w", err)})
+			return
+		}
+		entry := tok.cacheEntry(profile, "")
+		if err := storeLogin(ctx, opts.TokenStore, configDir, profile, entry); err != nil {
+			http.Error(w, "Login failed: the token cache could not be written.", http.StatusInternalServerError)
+			finish(result{err: err})
+			return
+		}
+		_, _ = io.WriteString(w, "Login complete. You can close this window.\n")
+		finish(result{entry: entry})
+	})
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() { _ = srv.Serve(ln) }()
+	defer srv.Close()
+
+	logger.InfoContext(ctx, "seclai: opening browser for SSO login", slog.String("domain", profile.SsoDomain), slog.String("redirect_uri", redirectURI))
+	if err := openURL(authorizeURL); err != nil {
+		return nil, fmt.Errorf("open login URL %s: %w", authorizeURL, err)
+	}
+
+	select {
+	case r := <-done:
+		if r.err == nil {
+			logger.InfoContext(ctx, "seclai: browser login complete", slog.String("expires_at", r.entry.ExpiresAt))
+		}
+		return r.entry, r.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("browser login did not complete in time: %w", ctx.Err())
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// randomToken returns n random bytes, base64url-encoded without padding.
+func randomToken(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err) // crypto/rand never fails on supported platforms
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// openBrowser opens u in the platform's default browser.
+func openBrowser(u string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", u)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", u)
+	default:
+		cmd = exec.Command("xdg-open", u)
+	}
+	return cmd.Start()
+}
+
+// LogoutResult reports what [Logout] did, so that an off-boarding script can
+// check that a profile's credentials no longer work.
+type LogoutResult struct {
+	// Cached reports whether the store held tokens for the profile.
+	Cached bool
+	// Revoked reports whether Cognito revoked the refresh token, which also
+	// invalidates the access and ID tokens issued from it.
+	Revoked bool
+	// RevokeError is why revocation failed. It is nil when revocation
+	// succeeded, or when there was no refresh token to revoke: the cached
+	// access token, if any, then stays valid until it expires.
+	RevokeError error
+	// Deleted reports whether the cached tokens were removed.
+	Deleted bool
+}
+
+// Logout signs a profile out: it revokes the cached refresh token at the
+// Cognito /oauth2/revoke endpoint of profile's SsoDomain, then removes the
+// tokens from the SSO cache in configDir. Empty configDir uses
+// SECLAI_CONFIG_DIR, then ~/.seclai.
+//
+// The cache entry is removed even when revocation fails, and the failure is
+// reported in [LogoutResult.RevokeError] rather than returned: Logout
+// returns an error only when the cache cannot be read or cleared.
+func Logout(ctx context.Context, configDir string, profile *SsoProfile) (*LogoutResult, error) {
+	return LogoutWithOptions(ctx, profile, LoginOptions{ConfigDir: configDir})
+}
+
+// LogoutWithOptions is [Logout] with a config directory or token store, HTTP
+// client and logger.
+func LogoutWithOptions(ctx context.Context, profile *SsoProfile, opts LoginOptions) (*LogoutResult, error) {
+	if profile == nil {
+		return nil, &ConfigurationError{Message: "Logout requires an SSO profile"}
+	}
+	configDir := resolveConfigDir(opts.ConfigDir)
+	if configDir == "" {
+		return nil, &ConfigurationError{Message: "cannot resolve the config directory; set LoginOptions.ConfigDir or SECLAI_CONFIG_DIR"}
+	}
+	hc := opts.HTTPClient
+	if hc == nil {
+		hc = &http.Client{Timeout: 30 * time.Second}
+	}
+	logger := loggerOrDiscard(opts.Logger)
+	store := opts.TokenStore
+	if store == nil {
+		store = &FileTokenStore{configDir: configDir}
+	}
+	// Hold the lock so that a concurrent refresh cannot store a new refresh
+	// token between revoking the old one and deleting the entry.
+	if locker, ok := store.(TokenLocker); ok {
+		unlock, err := locker.Lock(ctx, profile)
+		if err != nil {
+			return nil, err
+		}
+		defer unlock()
+	}
+
+	res := &LogoutResult{}
+	entry, err := store.Get(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return res, nil
+	}
+	res.Cached = true
+	if entry.RefreshToken != "" {
+		form := url.Values{"token": {entry.RefreshToken}, "client_id": {profile.SsoClientID}}
+		err := postForm(ctx, hc, fmt.Sprintf("https://%s/oauth2/revoke", profile.SsoDomain), form, nil)
+		if err != nil {
+			logger.WarnContext(ctx, "seclai: SSO token revocation failed", slog.String("error", err.Error()))
+			res.RevokeError = err
+		} else {
+			logger.InfoContext(ctx, "seclai: SSO refresh token revoked", slog.String("domain", profile.SsoDomain))
+			res.Revoked = true
+		}
+	}
+	if err := store.Delete(ctx, profile); err != nil {
+		return res, err
+	}
+	res.Deleted = true
+	return res, nil
+}
+
+// storeLogin saves a login's tokens to store, under its lock when it has
+// one, defaulting to the file cache in configDir.
+func storeLogin(ctx context.Context, store TokenStore, configDir string, profile *SsoProfile, entry *SsoCacheEntry) error {
+	if store == nil {
+		store = &FileTokenStore{configDir: configDir}
+	}
+	if locker, ok := store.(TokenLocker); ok {
+		unlock, err := locker.Lock(ctx, profile)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+	return store.Put(ctx, profile, entry)
+}
+
+// tokenResponse is a successful response from the token endpoint.
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	IDToken      string `json:"id_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// cacheEntry converts t into a cache entry for profile, keeping
+// refreshToken when the response did not rotate it.
+func (t *tokenResponse) cacheEntry(profile *SsoProfile, refreshToken string) *SsoCacheEntry {
+	if t.RefreshToken != "" {
+		refreshToken = t.RefreshToken
+	}
+	return &SsoCacheEntry{
+		AccessToken:   t.AccessToken,
+		RefreshToken:  refreshToken,
+		IDToken:       t.IDToken,
+		ExpiresAt:     time.Now().Add(time.Duration(t.ExpiresIn) * time.Second).UTC().Format(time.RFC3339),
+		ClientID:      profile.SsoClientID,
+		Region:        profile.SsoRegion,
+		CognitoDomain: profile.SsoDomain,
+	}
+}
+
+// postForm posts a form to an OAuth endpoint and decodes a 200 response
+// into out, unless out is nil. Any other status is returned as an
+// [OAuthError].
+func postForm(ctx context.Context, hc *http.Client, endpoint string, form url.Values, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		oe := &OAuthError{StatusCode: resp.StatusCode}
+		var body struct {
+			Error            string `json:"error"`
+			ErrorDescription string `json:"error_description"`
+		}
+		if json.Unmarshal(raw, &body) == nil && body.Error != "" {
+			oe.Code, oe.Description = body.Error, body.ErrorDescription
+		} else {
+			oe.Description = strings.TrimSpace(string(raw))
+		}
+		return oe
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(raw, out)
+}
@@ -0,0 +1,385 @@
+package seclai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newSsoServer starts a TLS stand-in for the Cognito domain and returns a
+// profile pointing at it and a client that trusts it.
+func newSsoServer(t *testing.T, h http.HandlerFunc) (*SsoProfile, *http.Client) {
+	t.Helper()
+	srv := httptest.NewTLSServer(h)
+	t.Cleanup(srv.Close)
+	return &SsoProfile{
+		SsoDomain:   strings.TrimPrefix(srv.URL, "https://"),
+		SsoClientID: "client-1",
+		SsoRegion:   "us-west-2",
+	}, srv.Client()
+}
+
+// fastDevicePolling shortens the device login intervals that a server
+// sending "interval": 0 falls back to.
+func fastDevicePolling(t *testing.T, d time.Duration) {
+	t.Helper()
+	oldInterval, oldSlowDown := deviceCodeInterval, deviceCodeSlowDown
+	deviceCodeInterval, deviceCodeSlowDown = d, d
+	t.Cleanup(func() { deviceCodeInterval, deviceCodeSlowDown = oldInterval, oldSlowDown })
+}
+
+func TestLoginWithDeviceCode_PollsUntilApproved(t *testing.T) {
+	fastDevicePolling(t, time.Millisecond)
+
+	var polls atomic.Int32
+	profile, hc := newSsoServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.Form.Get("client_id") != "client-1" {
+			t.Errorf("client_id = %q", r.Form.Get("client_id"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/oauth2/device_authorization":
+			if r.Form.Get("scope") != "openid email" {
+				t.Errorf("scope = %q", r.Form.Get("scope"))
+			}
+			_, _ = w.Write([]byte(`{"device_code":"dc","user_code":"ABCD-EFGH","verification_uri":"https://auth.example/device","expires_in":60,"interval":0}`))
+		case "/oauth2/token":
+			if r.Form.Get("grant_type") != deviceCodeGrantType || r.Form.Get("device_code") != "dc" {
+				t.Errorf("unexpected token request %v", r.Form)
+			}
+			switch polls.Add(1) {
+			case 1:
+				w.WriteHeader(400)
+				_, _ = w.Write([]byte(`{"error":"authorization_pending"}`))
+			case 2:
+				w.WriteHeader(400)
+				_, _ = w.Write([]byte(`{"error":"slow_down"}`))
+			default:
+				_, _ = w.Write([]byte(`{"access_token":"at","refresh_token":"rt","id_token":"it","expires_in":3600}`))
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	dir := t.TempDir()
+	var gotURI, gotCode string
+	entry, err := LoginWithDeviceCodeWithOptions(context.Background(), profile, func(uri, code string) {
+		gotURI, gotCode = uri, code
+	}, LoginOptions{ConfigDir: dir, HTTPClient: hc, Scopes: []string{"openid", "email"}})
+	if err != nil {
+		t.Fatalf("LoginWithDeviceCode: %v", err)
+	}
+	if gotURI != "https://auth.example/device" || gotCode != "ABCD-EFGH" {
+		t.Fatalf("prompt got %q, %q", gotURI, gotCode)
+	}
+	if polls.Load() != 3 {
+		t.Fatalf("expected 3 polls, got %d", polls.Load())
+	}
+
+	cached, err := ReadSsoCache(dir, profile)
+	if err != nil || cached == nil {
+		t.Fatalf("ReadSsoCache = %v, %v", cached, err)
+	}
+	if cached.AccessToken != "at" || cached.RefreshToken != "rt" || cached.ClientID != "client-1" || !IsTokenValid(cached) {
+		t.Fatalf("unexpected cache entry %+v", cached)
+	}
+	if *entry != *cached {
+		t.Fatalf("returned entry %+v differs from the cached one %+v", entry, cached)
+	}
+}
+
+func TestLoginWithDeviceCode_Denied(t *testing.T) {
+	fastDevicePolling(t, time.Millisecond)
+	profile, hc := newSsoServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/oauth2/device_authorization" {
+			_, _ = w.Write([]byte(`{"device_code":"dc","user_code":"U","verification_uri":"https://x","interval":0}`))
+			return
+		}
+		w.WriteHeader(400)
+		_, _ = w.Write([]byte(`{"error":"access_denied","error_description":"user said no"}`))
+	})
+
+	dir := t.TempDir()
+	_, err := LoginWithDeviceCodeWithOptions(context.Background(), profile, nil, LoginOptions{ConfigDir: dir, HTTPClient: hc})
+	var oe *OAuthError
+	if !errors.As(err, &oe) || oe.Code != "access_denied" || oe.StatusCode != 400 {
+		t.Fatalf("expected access_denied OAuthError, got %v", err)
+	}
+	if cached, _ := ReadSsoCache(dir, profile); cached != nil {
+		t.Fatalf("a denied login should not write the cache, got %+v", cached)
+	}
+}
+
+func TestLoginWithDeviceCode_ContextBoundsTheWait(t *testing.T) {
+	fastDevicePolling(t, time.Millisecond)
+	profile, hc := newSsoServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth2/device_authorization" {
+			_, _ = w.Write([]byte(`{"device_code":"dc","user_code":"U","verification_uri":"https://x","interval":0}`))
+			return
+		}
+		w.WriteHeader(400)
+		_, _ = w.Write([]byte(`{"error":"authorization_pending"}`))
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := LoginWithDeviceCodeWithOptions(ctx, profile, nil, LoginOptions{ConfigDir: t.TempDir(), HTTPClient: hc})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline to end the wait, got %v", err)
+	}
+}
+
+func TestLoginWithDeviceCode_ZeroIntervalUsesTheDefault(t *testing.T) {
+	fastDevicePolling(t, 20*time.Millisecond)
+	var polls atomic.Int32
+	profile, hc := newSsoServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth2/device_authorization" {
+			_, _ = w.Write([]byte(`{"device_code":"dc","user_code":"U","verification_uri":"https://x","interval":0}`))
+			return
+		}
+		polls.Add(1)
+		w.WriteHeader(400)
+		_, _ = w.Write([]byte(`{"error":"authorization_pending"}`))
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, _ = LoginWithDeviceCodeWithOptions(ctx, profile, nil, LoginOptions{ConfigDir: t.TempDir(), HTTPClient: hc})
+	// 200ms at the 20ms default allows about 10 polls; a tight loop makes
+	// hundreds.
+	if n := polls.Load(); n == 0 || n > 12 {
+		t.Fatalf("expected polling at the default interval, got %d polls", n)
+	}
+}
+
+// follow stands in for the browser: it loads the authorize URL's redirect
+// with the given code and state, the way the authorization server would.
+func follow(t *testing.T, authorizeURL, code, state string) (*url.URL, chan int) {
+	t.Helper()
+	u, err := url.Parse(authorizeURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status := make(chan int, 1)
+	redirect := u.Query().Get("redirect_uri") + "?" + url.Values{"code": {code}, "state": {state}}.Encode()
+	go func() {
+		resp, err := http.Get(redirect)
+		if err != nil {
+			status <- 0
+			return
+		}
+		resp.Body.Close()
+		status <- resp.StatusCode
+	}()
+	return u, status
+}
+
+func TestLoginWithBrowser_ExchangesCodeWithPKCE(t *testing.T) {
+	var verifier string
+	profile, hc := newSsoServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.URL.Path != "/oauth2/token" || r.Form.Get("grant_type") != "authorization_code" || r.Form.Get("code") != "c0de" {
+			t.Errorf("unexpected token request %s %v", r.URL.Path, r.Form)
+		}
+		verifier = r.Form.Get("code_verifier")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"at","refresh_token":"rt","expires_in":3600}`))
+	})
+
+	dir := t.TempDir()
+	var authorize *url.URL
+	var status chan int
+	entry, err := LoginWithBrowser(context.Background(), profile, LoginOptions{
+		ConfigDir:  dir,
+		HTTPClient: hc,
+		Scopes:     []string{"openid"},
+		OpenURL: func(u string) error {
+			parsed, _ := url.Parse(u)
+			authorize, status = follow(t, u, "c0de", parsed.Query().Get("state"))
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("LoginWithBrowser: %v", err)
+	}
+	if got := <-status; got != http.StatusOK {
+		t.Fatalf("the browser should see a success page, got %d", got)
+	}
+
+	q := authorize.Query()
+	if authorize.Host != profile.SsoDomain || authorize.Path != "/oauth2/authorize" || q.Get("response_type") != "code" ||
+		q.Get("client_id") != "client-1" || q.Get("scope") != "openid" || q.Get("code_challenge_method") != "S256" ||
+		!strings.HasPrefix(q.Get("redirect_uri"), "http://127.0.0.1:") {
+		t.Fatalf("unexpected authorize URL %s", authorize)
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	if q.Get("code_challenge") != base64.RawURLEncoding.EncodeToString(sum[:]) {
+		t.Fatalf("code_challenge %q does not match the verifier %q", q.Get("code_challenge"), verifier)
+	}
+
+	cached, _ := ReadSsoCache(dir, profile)
+	if cached == nil || cached.AccessToken != "at" || entry.RefreshToken != "rt" {
+		t.Fatalf("cache = %+v, entry = %+v", cached, entry)
+	}
+}
+
+func TestLoginWithBrowser_RejectsStateMismatch(t *testing.T) {
+	var exchanged atomic.Bool
+	profile, hc := newSsoServer(t, func(w http.ResponseWriter, r *http.Request) {
+		exchanged.Store(true)
+	})
+
+	dir := t.TempDir()
+	var status chan int
+	_, err := LoginWithBrowser(context.Background(), profile, LoginOptions{
+		ConfigDir:  dir,
+		HTTPClient: hc,
+		OpenURL: func(u string) error {
+			_, status = follow(t, u, "c0de", "forged")
+			return nil
+		},
+	})
+	if err == nil || !strings.Contains(err.Error(), "state") {
+		t.Fatalf("expected a state mismatch error, got %v", err)
+	}
+	if got := <-status; got != http.StatusBadRequest {
+		t.Fatalf("the browser should see a 400, got %d", got)
+	}
+	if exchanged.Load() {
+		t.Fatal("a mismatched state must not reach the token endpoint")
+	}
+	if cached, _ := ReadSsoCache(dir, profile); cached != nil {
+		t.Fatalf("cache written: %+v", cached)
+	}
+}
+
+func TestLoginWithBrowser_RejectsMissingCode(t *testing.T) {
+	var exchanged atomic.Bool
+	profile, hc := newSsoServer(t, func(w http.ResponseWriter, r *http.Request) {
+		exchanged.Store(true)
+	})
+
+	var status chan int
+	_, err := LoginWithBrowser(context.Background(), profile, LoginOptions{
+		ConfigDir:  t.TempDir(),
+		HTTPClient: hc,
+		OpenURL: func(u string) error {
+			parsed, _ := url.Parse(u)
+			_, status = follow(t, u, "", parsed.Query().Get("state"))
+			return nil
+		},
+	})
+	var oe *OAuthError
+	if !errors.As(err, &oe) || oe.Code != "invalid_request" {
+		t.Fatalf("expected an invalid_request OAuthError, got %v", err)
+	}
+	if got := <-status; got != http.StatusBadRequest {
+		t.Fatalf("the browser should see a 400, got %d", got)
+	}
+	if exchanged.Load() {
+		t.Fatal("a redirect without a code must not reach the token endpoint")
+	}
+}
+
+func TestLoginWithBrowser_Timeout(t *testing.T) {
+	profile, hc := newSsoServer(t, http.NotFound)
+	_, err := LoginWithBrowser(context.Background(), profile, LoginOptions{
+		ConfigDir:  t.TempDir(),
+		HTTPClient: hc,
+		Timeout:    20 * time.Millisecond,
+		OpenURL:    func(string) error { return nil },
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a timeout, got %v", err)
+	}
+}
+
+func TestLogout_RevokesThenDeletes(t *testing.T) {
+	var revoked atomic.Int32
+	profile, hc := newSsoServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.URL.Path != "/oauth2/revoke" || r.Form.Get("token") != "rt-1" || r.Form.Get("client_id") != "client-1" {
+			t.Errorf("unexpected request %s %v", r.URL.Path, r.Form)
+		}
+		revoked.Add(1)
+		// Cognito answers a revocation with an empty 200.
+	})
+	dir := t.TempDir()
+	if err := WriteSsoCache(dir, profile, &SsoCacheEntry{AccessToken: "at", RefreshToken: "rt-1", ExpiresAt: time.Now().Add(time.Hour).UTC().Format(time.RFC3339)}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := LogoutWithOptions(context.Background(), profile, LoginOptions{ConfigDir: dir, HTTPClient: hc})
+	if err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+	if !res.Cached || !res.Revoked || res.RevokeError != nil || !res.Deleted {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if revoked.Load() != 1 {
+		t.Fatalf("expected one revocation, got %d", revoked.Load())
+	}
+	if entry, _ := ReadSsoCache(dir, profile); entry != nil {
+		t.Fatal("the cache entry should be deleted")
+	}
+
+	// Logging out again finds nothing to do.
+	res, err = LogoutWithOptions(context.Background(), profile, LoginOptions{ConfigDir: dir, HTTPClient: hc})
+	if err != nil {
+		t.Fatalf("second Logout: %v", err)
+	}
+	if res.Cached || res.Revoked || res.Deleted {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+func TestLogout_DeletesWhenRevocationFails(t *testing.T) {
+	profile, hc := newSsoServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"unauthorized_client","error_description":"revocation is disabled"}`))
+	})
+	store := NewMemoryTokenStore()
+	if err := store.Put(context.Background(), profile, &SsoCacheEntry{AccessToken: "at", RefreshToken: "rt-1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := LogoutWithOptions(context.Background(), profile, LoginOptions{ConfigDir: t.TempDir(), HTTPClient: hc, TokenStore: store})
+	if err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+	var oe *OAuthError
+	if res.Revoked || !errors.As(res.RevokeError, &oe) || oe.Code != "unauthorized_client" || !res.Deleted {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if entry, _ := store.Get(context.Background(), profile); entry != nil {
+		t.Fatal("the cache entry should be deleted even when revocation fails")
+	}
+}
+
+func TestLogout_WithoutRefreshToken(t *testing.T) {
+	profile, hc := newSsoServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL.Path)
+	})
+	dir := t.TempDir()
+	if err := WriteSsoCache(dir, profile, &SsoCacheEntry{AccessToken: "at"}); err != nil {
+		t.Fatal(err)
+	}
+	res, err := LogoutWithOptions(context.Background(), profile, LoginOptions{ConfigDir: dir, HTTPClient: hc})
+	if err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+	if !res.Cached || res.Revoked || res.RevokeError != nil || !res.Deleted {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
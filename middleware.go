@@ -0,0 +1,69 @@
+package seclai
+
+import (
+	"net/http"
+
+	"github.com/seclai/seclai-go/internal/routes"
+)
+
+// Middleware observes or changes the client's HTTP traffic.
+//
+// RoundTrip receives each outgoing request, fully built — default headers and
+// auth applied — and next, the rest of the chain. It may edit the request,
+// call next.RoundTrip, and inspect or replace the response, or return an error
+// without calling next at all to refuse the request.
+//
+// Middleware runs once per attempt, so a retried request passes through the
+// chain again, and it covers every request path: [Client.Do] and the methods
+// built on it, uploads, streaming runs, downloads and [Client.Generated]. Like
+// an [http.RoundTripper], it must not retain the request after returning, and
+// a streaming response's body must be passed on unread.
+type Middleware interface {
+	RoundTrip(req *http.Request, next http.RoundTripper) (*http.Response, error)
+}
+
+// MiddlewareFunc adapts a function to [Middleware].
+type MiddlewareFunc func(req *http.Request, next http.RoundTripper) (*http.Response, error)
+
+// RoundTrip calls f(req, next).
+func (f MiddlewareFunc) RoundTrip(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+	return f(req, next)
+}
+
+// Route is the OpenAPI path template a request path was built from.
+type Route struct {
+	// Template is the path template as written in the spec, e.g.
+	// /agents/{agent_id}/runs.
+	Template string
+	// Params holds the path parameters by name, e.g. "agent_id".
+	Params map[string]string
+}
+
+// MatchRoute finds the route of a request path, so middleware can label
+// requests by endpoint rather than by URL. A prefix the spec does not know
+// about, such as a base URL's /api, is ignored. It reports false for a path
+// matching no operation in the bundled spec.
+func MatchRoute(path string) (Route, bool) {
+	r, ok := routes.Match(path)
+	return Route{Template: r.Template, Params: r.Params}, ok
+}
+
+// roundTripperFunc adapts a function to [http.RoundTripper].
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// chainMiddleware wraps transport in mw. The first middleware is outermost:
+// it sees the request first and the response last.
+func chainMiddleware(transport http.RoundTripper, mw []Middleware) http.RoundTripper {
+	for i := len(mw) - 1; i >= 0; i-- {
+		m, next := mw[i], transport
+		if m == nil {
+			continue
+		}
+		transport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return m.RoundTrip(req, next)
+		})
+	}
+	return transport
+}
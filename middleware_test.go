@@ -0,0 +1,178 @@
+package seclai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder is a Middleware that notes each request it sees and stamps a header.
+type recorder struct {
+	mu    sync.Mutex
+	paths []string
+}
+
+func (r *recorder) RoundTrip(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+	r.mu.Lock()
+	r.paths = append(r.paths, req.Method+" "+req.URL.Path)
+	r.mu.Unlock()
+	req.Header.Set("X-Audit", "1")
+	return next.RoundTrip(req)
+}
+
+func TestMiddleware_CoversEveryPath(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Audit") != "1" {
+			w.WriteHeader(400)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/runs/stream") {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = io.WriteString(w, "event: done\ndata: {\"run_id\":\"run_1\",\"status\":\"completed\"}\n\n")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	rec := &recorder{}
+	c, _ := NewClient(Options{APIKey: "k", BaseURL: srv.URL, Middleware: []Middleware{rec}})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := c.GetAgent(ctx, "a_1"); err != nil {
+		t.Fatalf("GetAgent: %v", err)
+	}
+	if _, err := c.UploadFileToSource(ctx, "s_1", UploadFileRequest{File: []byte("x"), FileName: "a.txt"}); err != nil {
+		t.Fatalf("UploadFileToSource: %v", err)
+	}
+	if _, err := c.RunStreamingAgentAndWait(ctx, "a_1", AgentRunStreamRequest{}); err != nil {
+		t.Fatalf("RunStreamingAgentAndWait: %v", err)
+	}
+	events, errCh := c.RunStreamingAgent(ctx, "a_1", AgentRunStreamRequest{})
+	for range events {
+	}
+	if err := <-errCh; err != nil {
+		t.Fatalf("RunStreamingAgent: %v", err)
+	}
+	for _, download := range []func() (*http.Response, error){
+		func() (*http.Response, error) { return c.DownloadSourceExport(ctx, "s_1", "e_1") },
+		func() (*http.Response, error) { return c.DownloadAgentRunAttachment(ctx, "r_1", "att", "") },
+	} {
+		resp, err := download()
+		if err != nil {
+			t.Fatalf("download: %v", err)
+		}
+		resp.Body.Close()
+	}
+	if _, err := c.Generated().ListSourcesApiSourcesGetWithResponse(ctx, nil); err != nil {
+		t.Fatalf("generated ListSources: %v", err)
+	}
+
+	want := []string{
+		"GET /agents/a_1",
+		"POST /sources/s_1/upload",
+		"POST /agents/a_1/runs/stream",
+		"POST /agents/a_1/runs/stream",
+		"GET /sources/s_1/exports/e_1/download",
+		"GET /v2/agent-runs/r_1/attachments/att",
+		"GET /sources",
+	}
+	if strings.Join(rec.paths, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected traffic:\n%s", strings.Join(rec.paths, "\n"))
+	}
+}
+
+func TestMiddleware_RunsInOrderAndSeesTheResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	var order []string
+	mw := func(name string) Middleware {
+		return MiddlewareFunc(func(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+			order = append(order, name+" request")
+			if req.Header.Get("x-api-key") != "k" {
+				t.Errorf("%s: expected auth applied before middleware", name)
+			}
+			resp, err := next.RoundTrip(req)
+			if err == nil {
+				order = append(order, name+" response "+resp.Status)
+			}
+			return resp, err
+		})
+	}
+	c, _ := NewClient(Options{APIKey: "k", BaseURL: srv.URL, Middleware: []Middleware{mw("outer"), nil, mw("inner")}})
+	if err := c.Do(context.Background(), http.MethodGet, "/agents", nil, nil, nil, nil); err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+
+	want := "outer request,inner request,inner response 200 OK,outer response 200 OK"
+	if got := strings.Join(order, ","); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestMiddleware_CanRefuseARequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request should not reach the server")
+	}))
+	t.Cleanup(srv.Close)
+
+	denied := errors.New("denied by policy")
+	c, _ := NewClient(Options{
+		APIKey:  "k",
+		BaseURL: srv.URL,
+		Retry:   fastRetry(),
+		Middleware: []Middleware{MiddlewareFunc(func(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+			if req.Method == http.MethodDelete {
+				return nil, denied
+			}
+			return next.RoundTrip(req)
+		})},
+	})
+	if err := c.DeleteAgent(context.Background(), "a_1"); !errors.Is(err, denied) {
+		t.Fatalf("expected the policy error, got %v", err)
+	}
+}
+
+func TestMiddleware_SeesEachRetry(t *testing.T) {
+	var n int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(503)
+	}))
+	t.Cleanup(srv.Close)
+
+	c, _ := NewClient(Options{
+		APIKey:  "k",
+		BaseURL: srv.URL,
+		Retry:   fastRetry(),
+		Middleware: []Middleware{MiddlewareFunc(func(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+			n++
+			return next.RoundTrip(req)
+		})},
+	})
+	_ = c.Do(context.Background(), http.MethodGet, "/agents", nil, nil, nil, nil)
+	if n != 3 {
+		t.Fatalf("expected middleware to run for all 3 attempts, got %d", n)
+	}
+}
+
+func TestMatchRoute(t *testing.T) {
+	r, ok := MatchRoute("/api/agents/a_1/runs/r_1/evaluation-results")
+	if !ok || r.Template != "/agents/{agent_id}/runs/{run_id}/evaluation-results" || r.Params["agent_id"] != "a_1" || r.Params["run_id"] != "r_1" {
+		t.Fatalf("MatchRoute = %+v, %v", r, ok)
+	}
+	if _, ok := MatchRoute("/not/an/endpoint"); ok {
+		t.Fatal("expected no route for an unknown path")
+	}
+}
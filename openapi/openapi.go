@@ -0,0 +1,11 @@
+// Package openapi embeds the OpenAPI document the SDK is generated from, so
+// tooling such as [github.com/seclai/seclai-go/seclaitest] can check requests
+// against the same contract the client was built for.
+package openapi
+
+import _ "embed"
+
+// Spec is the contents of seclai.openapi.json.
+//
+//go:embed seclai.openapi.json
+var Spec []byte
@@ -0,0 +1,25 @@
+module github.com/seclai/seclai-go/otel
+
+go 1.23
+
+require (
+	github.com/seclai/seclai-go v1.7.0
+	go.opentelemetry.io/otel v1.32.0
+	go.opentelemetry.io/otel/metric v1.32.0
+	go.opentelemetry.io/otel/sdk v1.32.0
+	go.opentelemetry.io/otel/sdk/metric v1.32.0
+	go.opentelemetry.io/otel/trace v1.32.0
+)
+
+require (
+	github.com/apapsch/go-jsonmerge/v2 v2.0.0 // indirect
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/oapi-codegen/runtime v1.1.2 // indirect
+	golang.org/x/sys v0.27.0 // indirect
+)
+
+// Builds in this repository use the SDK beside it; dependents get the
+// version required above.
+replace github.com/seclai/seclai-go => ../
@@ -0,0 +1,355 @@
+// Package otel instruments a Seclai client with OpenTelemetry.
+//
+// An [Instrumentation] is a [seclai.Middleware]: add it to
+// [seclai.Options.Middleware] and every API call — including retries, uploads,
+// downloads, streaming runs and the generated client — gets a client span and
+// is counted in the request metrics.
+//
+//	inst, err := otel.New()
+//	if err != nil {
+//		return err
+//	}
+//	client, err := seclai.NewClient(seclai.Options{
+//		Middleware: []seclai.Middleware{inst},
+//	})
+//
+// Spans are named after the route template, e.g. "POST /agents/{agent_id}/runs",
+// and carry the HTTP method, status code, the agent and run IDs found in the
+// path, and the Seclai-Version the request was sent with. Trace context is
+// propagated to the API with the configured propagator.
+//
+// A request that asks for an event stream, as every streaming run does, also
+// gets a "seclai.agent_run.stream" parent span that lasts until the response
+// body is read to the end or closed, with a span event per SSE event.
+//
+// This package is a separate module, so that only programs that import it
+// depend on OpenTelemetry.
+package otel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	otelapi "go.opentelemetry.io/otel"
+
+	seclai "github.com/seclai/seclai-go"
+	"github.com/seclai/seclai-go/sse"
+)
+
+// ScopeName is the instrumentation scope of the tracer and meter.
+const ScopeName = "github.com/seclai/seclai-go/otel"
+
+// Attribute keys specific to Seclai. HTTP attributes follow the OpenTelemetry
+// semantic conventions.
+const (
+	AgentIDKey    = attribute.Key("seclai.agent_id")
+	RunIDKey      = attribute.Key("seclai.run_id")
+	RunStatusKey  = attribute.Key("seclai.run.status")
+	APIVersionKey = attribute.Key("seclai.api_version")
+)
+
+const (
+	methodKey     = attribute.Key("http.request.method")
+	statusCodeKey = attribute.Key("http.response.status_code")
+	templateKey   = attribute.Key("url.template")
+	urlKey        = attribute.Key("url.full")
+	serverKey     = attribute.Key("server.address")
+	errorTypeKey  = attribute.Key("error.type")
+)
+
+// Option configures [New].
+type Option func(*config)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	propagator     propagation.TextMapPropagator
+}
+
+// WithTracerProvider sets the tracer provider. The global one is used by default.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) { c.tracerProvider = tp }
+}
+
+// WithMeterProvider sets the meter provider. The global one is used by default.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) { c.meterProvider = mp }
+}
+
+// WithPropagator sets the propagator used to inject trace context into
+// outgoing requests. The global one is used by default.
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return func(c *config) { c.propagator = p }
+}
+
+// Instrumentation records spans and metrics for a Seclai client. It is safe
+// for concurrent use and may be shared by several clients.
+type Instrumentation struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	duration   metric.Float64Histogram
+	errors     metric.Int64Counter
+}
+
+var _ seclai.Middleware = (*Instrumentation)(nil)
+
+// New creates an Instrumentation. It records two metrics:
+//
+//   - seclai.client.request.duration, a histogram of attempt durations in seconds;
+//   - seclai.client.request.errors, a count of attempts that failed, either
+//     with a transport error or an HTTP status of 400 or above.
+func New(opts ...Option) (*Instrumentation, error) {
+	cfg := config{
+		tracerProvider: otelapi.GetTracerProvider(),
+		meterProvider:  otelapi.GetMeterProvider(),
+		propagator:     otelapi.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	meter := cfg.meterProvider.Meter(ScopeName)
+	duration, err := meter.Float64Histogram("seclai.client.request.duration",
+		metric.WithDescription("Duration of Seclai API requests."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	errs, err := meter.Int64Counter("seclai.client.request.errors",
+		metric.WithDescription("Number of Seclai API requests that failed."),
+		metric.WithUnit("{error}"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Instrumentation{
+		tracer:     cfg.tracerProvider.Tracer(ScopeName),
+		propagator: cfg.propagator,
+		duration:   duration,
+		errors:     errs,
+	}, nil
+}
+
+// RoundTrip implements [seclai.Middleware]. It wraps each attempt in a client
+// span; the span ends when the response headers arrive, so a streaming body
+// is not included in its duration. A request whose Accept header asks for
+// text/event-stream also gets a parent stream span, which ends with the
+// response body.
+func (in *Instrumentation) RoundTrip(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+	r, matched := seclai.MatchRoute(req.URL.Path)
+	if !acceptsEventStream(req) {
+		return in.roundTrip(req, next, r, matched)
+	}
+
+	var attrs []attribute.KeyValue
+	if id, ok := r.Params["agent_id"]; ok {
+		attrs = append(attrs, AgentIDKey.String(id))
+	}
+	ctx, span := in.tracer.Start(req.Context(), "seclai.agent_run.stream", trace.WithAttributes(attrs...))
+	resp, err := in.roundTrip(req.WithContext(ctx), next, r, matched)
+	switch {
+	case err != nil:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.End()
+	case resp.StatusCode >= 400:
+		span.SetStatus(codes.Error, resp.Status)
+		span.End()
+	case !isEventStream(resp):
+		// The server answered with a plain body; there are no events.
+		span.End()
+	default:
+		resp.Body = newStreamBody(resp.Body, span)
+	}
+	return resp, err
+}
+
+func (in *Instrumentation) roundTrip(req *http.Request, next http.RoundTripper, r seclai.Route, matched bool) (*http.Response, error) {
+	start := time.Now()
+
+	name := req.Method
+	attrs := []attribute.KeyValue{
+		methodKey.String(req.Method),
+		urlKey.String(req.URL.String()),
+		serverKey.String(req.URL.Hostname()),
+	}
+	metricAttrs := []attribute.KeyValue{methodKey.String(req.Method)}
+	if matched {
+		name += " " + r.Template
+		attrs = append(attrs, templateKey.String(r.Template))
+		metricAttrs = append(metricAttrs, templateKey.String(r.Template))
+		if id, ok := r.Params["agent_id"]; ok {
+			attrs = append(attrs, AgentIDKey.String(id))
+		}
+		if id, ok := r.Params["run_id"]; ok {
+			attrs = append(attrs, RunIDKey.String(id))
+		}
+	}
+	if v := req.Header.Get("Seclai-Version"); v != "" {
+		attrs = append(attrs, APIVersionKey.String(v))
+	}
+
+	ctx, span := in.tracer.Start(req.Context(), name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+	defer span.End()
+
+	req = req.Clone(ctx)
+	in.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := next.RoundTrip(req)
+
+	var errorType string
+	switch {
+	case err != nil:
+		errorType = errorTypeOf(err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	default:
+		span.SetAttributes(statusCodeKey.Int(resp.StatusCode))
+		metricAttrs = append(metricAttrs, statusCodeKey.Int(resp.StatusCode))
+		if resp.StatusCode >= 400 {
+			errorType = strconv.Itoa(resp.StatusCode)
+			span.SetStatus(codes.Error, resp.Status)
+		}
+	}
+	if errorType != "" {
+		span.SetAttributes(errorTypeKey.String(errorType))
+		metricAttrs = append(metricAttrs, errorTypeKey.String(errorType))
+		in.errors.Add(ctx, 1, metric.WithAttributes(metricAttrs...))
+	}
+	in.duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(metricAttrs...))
+
+	return resp, err
+}
+
+func acceptsEventStream(req *http.Request) bool {
+	mt, _, _ := mime.ParseMediaType(req.Header.Get("Accept"))
+	return mt == "text/event-stream"
+}
+
+func isEventStream(resp *http.Response) bool {
+	mt, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return mt == "text/event-stream"
+}
+
+// streamBody passes an event stream through to the client while a decoder
+// goroutine reads a copy of it, adding each event to the stream span. The
+// span ends when the body is read to the end or closed, whichever comes
+// first, and the goroutine with it.
+type streamBody struct {
+	io.ReadCloser
+	span trace.Span
+	pw   *io.PipeWriter
+	done chan struct{}
+	once sync.Once
+
+	// status is the last run status seen, written by the decoder goroutine
+	// before done is closed.
+	status string
+}
+
+func newStreamBody(body io.ReadCloser, span trace.Span) *streamBody {
+	pr, pw := io.Pipe()
+	b := &streamBody{ReadCloser: body, span: span, pw: pw, done: make(chan struct{})}
+	go b.decode(pr)
+	return b
+}
+
+func (b *streamBody) decode(pr *io.PipeReader) {
+	defer close(b.done)
+	// Drain whatever the decoder leaves, so Read never blocks on the pipe.
+	defer func() { _, _ = io.Copy(io.Discard, pr) }()
+
+	dec := sse.NewDecoder(pr)
+	dec.SetDispatchAtEOF(true)
+	for {
+		ev, err := dec.Next()
+		if err != nil {
+			return
+		}
+		name := ev.Type
+		if name == "" {
+			name = "message"
+		}
+		var run struct {
+			RunID  string `json:"run_id"`
+			Status string `json:"status"`
+		}
+		_ = json.Unmarshal([]byte(ev.Data), &run)
+		var attrs []attribute.KeyValue
+		if run.RunID != "" {
+			attrs = append(attrs, RunIDKey.String(run.RunID))
+			b.span.SetAttributes(RunIDKey.String(run.RunID))
+		}
+		if run.Status != "" {
+			b.status = run.Status
+			attrs = append(attrs, RunStatusKey.String(run.Status))
+		}
+		b.span.AddEvent(name, trace.WithAttributes(attrs...))
+	}
+}
+
+func (b *streamBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		_, _ = b.pw.Write(p[:n])
+	}
+	if err == io.EOF {
+		b.finish(nil)
+	} else if err != nil {
+		b.finish(err)
+	}
+	return n, err
+}
+
+func (b *streamBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.finish(nil)
+	return err
+}
+
+// finish ends the span once, after the decoder has seen every byte read so
+// far, so the span is complete before the client sees the stream end.
+func (b *streamBody) finish(err error) {
+	b.once.Do(func() {
+		_ = b.pw.Close()
+		<-b.done
+		if b.status != "" {
+			b.span.SetAttributes(RunStatusKey.String(b.status))
+		}
+		switch {
+		case err != nil:
+			b.span.RecordError(err)
+			b.span.SetStatus(codes.Error, err.Error())
+		case b.status == "failed":
+			b.span.SetStatus(codes.Error, "agent run failed")
+		}
+		b.span.End()
+	})
+}
+
+// errorTypeOf names a transport error for the error.type attribute.
+func errorTypeOf(err error) string {
+	var t interface{ Timeout() bool }
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &t) && t.Timeout():
+		return "timeout"
+	}
+	return "_OTHER"
+}
@@ -0,0 +1,231 @@
+package otel
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	seclai "github.com/seclai/seclai-go"
+)
+
+type harness struct {
+	spans  *tracetest.SpanRecorder
+	reader *sdkmetric.ManualReader
+	client *seclai.Client
+	inst   *Instrumentation
+}
+
+func newHarness(t *testing.T, h http.HandlerFunc) *harness {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	inst, err := New(
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		WithPropagator(propagation.TraceContext{}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, _ := seclai.NewClient(seclai.Options{
+		APIKey:     "k",
+		BaseURL:    srv.URL,
+		APIVersion: seclai.KnownAPIVersions[0],
+		Middleware: []seclai.Middleware{inst},
+	})
+	return &harness{spans: spans, reader: reader, client: c, inst: inst}
+}
+
+func attr(attrs []attribute.KeyValue, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range attrs {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestRoundTrip_RecordsAClientSpan(t *testing.T) {
+	var traceparent string
+	h := newHarness(t, func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("Traceparent")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{}`)
+	})
+	if _, err := h.client.GetAgentRun(context.Background(), "run_1", nil); err != nil {
+		t.Fatalf("GetAgentRun: %v", err)
+	}
+
+	ended := h.spans.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(ended))
+	}
+	span := ended[0]
+	if span.Name() != "GET /agents/runs/{run_id}" {
+		t.Fatalf("unexpected span name %q", span.Name())
+	}
+	for key, want := range map[attribute.Key]string{
+		methodKey:     "GET",
+		templateKey:   "/agents/runs/{run_id}",
+		RunIDKey:      "run_1",
+		APIVersionKey: seclai.KnownAPIVersions[0],
+	} {
+		if v, ok := attr(span.Attributes(), key); !ok || v.AsString() != want {
+			t.Errorf("%s = %q, want %q", key, v.AsString(), want)
+		}
+	}
+	if v, _ := attr(span.Attributes(), statusCodeKey); v.AsInt64() != 200 {
+		t.Errorf("expected status 200, got %v", v.AsInt64())
+	}
+	if traceparent == "" || span.SpanContext().TraceID().String() != traceparent[3:35] {
+		t.Errorf("expected the trace context to be propagated, got %q", traceparent)
+	}
+}
+
+func TestRoundTrip_RecordsMetrics(t *testing.T) {
+	h := newHarness(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+	})
+	_ = h.client.DeleteAgent(context.Background(), "a_1")
+
+	var rm metricdata.ResourceMetrics
+	if err := h.reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	found := map[string]bool{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Histogram[float64]:
+				if data.DataPoints[0].Count == 1 {
+					found[m.Name] = true
+				}
+			case metricdata.Sum[int64]:
+				dp := data.DataPoints[0]
+				if v, _ := dp.Attributes.Value(errorTypeKey); dp.Value == 1 && v.AsString() == "404" {
+					found[m.Name] = true
+				}
+			}
+		}
+	}
+	if !found["seclai.client.request.duration"] || !found["seclai.client.request.errors"] {
+		t.Fatalf("expected a duration and an error data point, got %v", found)
+	}
+
+	span := h.spans.Ended()[0]
+	if span.Status().Code != codes.Error {
+		t.Fatalf("expected an error status, got %v", span.Status())
+	}
+	if v, _ := attr(span.Attributes(), AgentIDKey); v.AsString() != "a_1" {
+		t.Fatalf("expected the agent ID, got %q", v.AsString())
+	}
+}
+
+const stream = "event: init\ndata: {\"run_id\":\"run_1\",\"status\":\"pending\"}\n\n" +
+	"event: done\ndata: {\"run_id\":\"run_1\",\"status\":\"completed\"}\n\n"
+
+func streamHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	_, _ = io.WriteString(w, stream)
+}
+
+// streamSpans returns the stream span and the request span under it.
+func streamSpans(t *testing.T, h *harness) (parent, child sdktrace.ReadOnlySpan) {
+	t.Helper()
+	for _, s := range h.spans.Ended() {
+		switch s.Name() {
+		case "seclai.agent_run.stream":
+			parent = s
+		case "POST /agents/{agent_id}/runs/stream":
+			child = s
+		}
+	}
+	if parent == nil || child == nil {
+		t.Fatalf("expected a stream span and a request span, got %d spans", len(h.spans.Ended()))
+	}
+	if child.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Fatal("expected the request span to be a child of the stream span")
+	}
+	return parent, child
+}
+
+func TestRoundTrip_StreamSpanWithEvents(t *testing.T) {
+	h := newHarness(t, streamHandler)
+
+	events, errCh := h.client.RunStreamingAgent(context.Background(), "a_1", seclai.AgentRunStreamRequest{})
+	var n int
+	for range events {
+		n++
+	}
+	if err := <-errCh; err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("expected 2 events, got %d", n)
+	}
+
+	parent, _ := streamSpans(t, h)
+	got := parent.Events()
+	if len(got) != 2 || got[0].Name != "init" || got[1].Name != "done" {
+		t.Fatalf("unexpected span events: %+v", got)
+	}
+	if v, _ := attr(got[1].Attributes, RunStatusKey); v.AsString() != "completed" {
+		t.Fatalf("expected the run status on the event, got %q", v.AsString())
+	}
+	for key, want := range map[attribute.Key]string{AgentIDKey: "a_1", RunIDKey: "run_1", RunStatusKey: "completed"} {
+		if v, _ := attr(parent.Attributes(), key); v.AsString() != want {
+			t.Errorf("%s = %q on the stream span, want %q", key, v.AsString(), want)
+		}
+	}
+}
+
+func TestRoundTrip_StreamSpanForEveryEntryPoint(t *testing.T) {
+	h := newHarness(t, streamHandler)
+	if _, err := h.client.RunStreamingAgentAndWait(context.Background(), "a_1", seclai.AgentRunStreamRequest{}); err != nil {
+		t.Fatalf("RunStreamingAgentAndWait: %v", err)
+	}
+	if parent, _ := streamSpans(t, h); len(parent.Events()) != 2 {
+		t.Fatalf("unexpected span events: %+v", parent.Events())
+	}
+
+	// Stopping early closes the body, which ends the span with the events
+	// decoded so far.
+	h = newHarness(t, streamHandler)
+	for evt, err := range h.client.RunStreamingAgentEvents(context.Background(), "a_1", seclai.AgentRunStreamRequest{}) {
+		if err != nil {
+			t.Fatalf("RunStreamingAgentEvents: %v", err)
+		}
+		if evt.Kind() == seclai.AgentRunEventInit {
+			break
+		}
+	}
+	if parent, _ := streamSpans(t, h); len(parent.Events()) == 0 || parent.Events()[0].Name != "init" {
+		t.Fatalf("unexpected span events: %+v", parent.Events())
+	}
+}
+
+func TestRoundTrip_FailedStream(t *testing.T) {
+	h := newHarness(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	err := h.client.RunStreamingAgentWithHandler(context.Background(), "a_1", seclai.AgentRunStreamRequest{}, seclai.StreamHandlerFuncs{})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if parent, _ := streamSpans(t, h); parent.Status().Code != codes.Error {
+		t.Fatalf("expected an error status, got %v", parent.Status())
+	}
+}
@@ -0,0 +1,408 @@
+package seclai
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+)
+
+// ── Pagination ──────────────────────────────────────────────────────────────
+//
+// List endpoints paginate in one of three ways: page/limit, limit/offset, or
+// — on endpoints whose shape is version-gated — whatever envelope the API
+// version selects: the canonical {data, pagination}, a legacy {items, total}
+// style object, or a bare array. The All* iterators below hide all of it
+// behind one pager, so callers range over items and never see a page.
+
+// pagePosition is where the next page starts, in both coordinate systems.
+// Endpoints send whichever they declare.
+type pagePosition struct {
+	// Page is the 1-based page number.
+	Page int
+	// Offset is the number of items before the page.
+	Offset int
+	// sizeUnknown marks a start past page 1 with no page size, whose Offset
+	// waits for the first page to report the size the server chose.
+	sizeUnknown bool
+}
+
+// pageResult is one page as the pager needs to see it.
+type pageResult[T any] struct {
+	Items []T
+	// Pagination is the canonical envelope's metadata, nil on other shapes.
+	Pagination *PaginationResponse
+	// Total is the item count from a flat or legacy envelope; zero when the
+	// response carries none.
+	Total int
+	// Limit is the page size a flat or legacy envelope reports; zero when the
+	// response carries none.
+	Limit int
+}
+
+// size returns the page size the server used: the reported limit, or failing
+// that the number of items on the page.
+func (r *pageResult[T]) size() int {
+	switch {
+	case r.Pagination != nil && r.Pagination.Limit > 0:
+		return r.Pagination.Limit
+	case r.Limit > 0:
+		return r.Limit
+	default:
+		return len(r.Items)
+	}
+}
+
+// more reports whether another page follows this one.
+//
+// The canonical envelope says so outright. A flat or legacy envelope carries a
+// total to count against. A bare array carries nothing, so a full page is taken
+// to mean there may be more; the worst case is one extra, empty request.
+func (r *pageResult[T]) more(pos pagePosition, pageSize int) bool {
+	switch {
+	case len(r.Items) == 0:
+		return false
+	case r.Pagination != nil:
+		return r.Pagination.HasNext
+	case r.Total > 0:
+		return pos.Offset+len(r.Items) < r.Total
+	default:
+		return pageSize > 0 && len(r.Items) >= pageSize
+	}
+}
+
+// paginate yields every item from start onwards, fetching a page at a time.
+//
+// pageSize is the requested page size, or zero to let the server choose, in
+// which case the first page's length stands in for it. Iteration stops at the
+// first error, which is yielded with a zero T.
+func paginate[T any](ctx context.Context, start pagePosition, pageSize int, fetch func(ctx context.Context, pos pagePosition) (*pageResult[T], error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		pos := start
+		if pos.Page < 1 {
+			pos.Page = 1
+		}
+		for {
+			if err := ctx.Err(); err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			res, err := fetch(ctx, pos)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range res.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if pageSize <= 0 {
+				pageSize = res.size()
+			}
+			if pos.sizeUnknown {
+				pos.Offset, pos.sizeUnknown = (pos.Page-1)*pageSize, false
+			}
+			if !res.more(pos, pageSize) {
+				return
+			}
+			pos.Page++
+			pos.Offset += len(res.Items)
+		}
+	}
+}
+
+// pageStart is where iteration begins for a page/limit endpoint. Without a
+// limit the offset of a later page is not known until the server has said
+// how large its pages are.
+func pageStart(opts ListOptions) pagePosition {
+	pos := pagePosition{Page: opts.Page}
+	if opts.Page > 1 {
+		pos.Offset = (opts.Page - 1) * opts.Limit
+		pos.sizeUnknown = opts.Limit <= 0
+	}
+	return pos
+}
+
+// ── Agents ──────────────────────────────────────────────────────────────────
+
+// AllAgents iterates over every agent, fetching pages as needed. opts.Page
+// selects the first page and opts.Limit the page size.
+//
+//	for agent, err := range client.AllAgents(ctx, seclai.ListOptions{Limit: 100}) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(agent.Id)
+//	}
+func (c *Client) AllAgents(ctx context.Context, opts ListOptions) iter.Seq2[AgentSummaryResponse, error] {
+	return paginate(ctx, pageStart(opts), opts.Limit, func(ctx context.Context, pos pagePosition) (*pageResult[AgentSummaryResponse], error) {
+		opts.Page = pos.Page
+		res, err := c.ListAgents(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &pageResult[AgentSummaryResponse]{Items: res.Data, Pagination: &res.Pagination}, nil
+	})
+}
+
+// AllAgentRuns iterates over every run of an agent, fetching pages as needed.
+func (c *Client) AllAgentRuns(ctx context.Context, agentID string, opts ListAgentRunsOptions) iter.Seq2[AgentRunResponse, error] {
+	start := pageStart(ListOptions{Page: opts.Page, Limit: opts.Limit})
+	return paginate(ctx, start, opts.Limit, func(ctx context.Context, pos pagePosition) (*pageResult[AgentRunResponse], error) {
+		opts.Page = pos.Page
+		res, err := c.ListAgentRuns(ctx, agentID, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &pageResult[AgentRunResponse]{Items: res.Data, Pagination: &res.Pagination}, nil
+	})
+}
+
+// ── Agent Evaluations ───────────────────────────────────────────────────────
+
+// AllEvaluationCriteria iterates over every evaluation criteria of an agent,
+// whichever envelope the API version selects.
+func (c *Client) AllEvaluationCriteria(ctx context.Context, agentID string, opts ListOptions) iter.Seq2[EvaluationCriteriaResponse, error] {
+	return paginate(ctx, pageStart(opts), opts.Limit, func(ctx context.Context, pos pagePosition) (*pageResult[EvaluationCriteriaResponse], error) {
+		opts.Page = pos.Page
+		res, err := c.ListEvaluationCriteriaPage(ctx, agentID, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &pageResult[EvaluationCriteriaResponse]{Items: res.Data, Pagination: res.Pagination}, nil
+	})
+}
+
+// AllEvaluationResults iterates over every result recorded for evaluation criteria.
+func (c *Client) AllEvaluationResults(ctx context.Context, criteriaID string, opts ListOptions) iter.Seq2[EvaluationResultResponse, error] {
+	return paginate(ctx, pageStart(opts), opts.Limit, func(ctx context.Context, pos pagePosition) (*pageResult[EvaluationResultResponse], error) {
+		opts.Page = pos.Page
+		res, err := c.ListEvaluationResults(ctx, criteriaID, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &pageResult[EvaluationResultResponse]{Items: res.Data, Total: res.Total, Limit: res.Limit}, nil
+	})
+}
+
+// AllCompatibleRuns iterates over every run compatible with evaluation criteria.
+func (c *Client) AllCompatibleRuns(ctx context.Context, criteriaID string, opts ListOptions) iter.Seq2[CompatibleRunResponse, error] {
+	return paginate(ctx, pageStart(opts), opts.Limit, func(ctx context.Context, pos pagePosition) (*pageResult[CompatibleRunResponse], error) {
+		opts.Page = pos.Page
+		res, err := c.ListCompatibleRuns(ctx, criteriaID, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &pageResult[CompatibleRunResponse]{Items: res.Data, Total: res.Total, Limit: res.Limit}, nil
+	})
+}
+
+// AllAgentEvaluationResults iterates over every evaluation result of an agent.
+func (c *Client) AllAgentEvaluationResults(ctx context.Context, agentID string, opts ListOptions) iter.Seq2[EvaluationResultWithCriteriaResponse, error] {
+	return paginate(ctx, pageStart(opts), opts.Limit, func(ctx context.Context, pos pagePosition) (*pageResult[EvaluationResultWithCriteriaResponse], error) {
+		opts.Page = pos.Page
+		res, err := c.ListAgentEvaluationResults(ctx, agentID, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &pageResult[EvaluationResultWithCriteriaResponse]{Items: res.Data, Pagination: res.Pagination, Total: res.Total, Limit: res.Limit}, nil
+	})
+}
+
+// AllRunEvaluationResults iterates over every evaluation result of a run,
+// whichever envelope the API version selects.
+func (c *Client) AllRunEvaluationResults(ctx context.Context, agentID, runID string, opts ListOptions) iter.Seq2[EvaluationResultWithCriteriaResponse, error] {
+	return paginate(ctx, pageStart(opts), opts.Limit, func(ctx context.Context, pos pagePosition) (*pageResult[EvaluationResultWithCriteriaResponse], error) {
+		opts.Page = pos.Page
+		res, err := c.ListRunEvaluationResults(ctx, agentID, runID, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &pageResult[EvaluationResultWithCriteriaResponse]{Items: res.Data, Pagination: res.Pagination, Total: res.Total, Limit: res.Limit}, nil
+	})
+}
+
+// AllEvaluationRuns iterates over every evaluation run summary of an agent.
+func (c *Client) AllEvaluationRuns(ctx context.Context, agentID string, opts ListOptions) iter.Seq2[EvaluationRunSummaryResponse, error] {
+	return paginate(ctx, pageStart(opts), opts.Limit, func(ctx context.Context, pos pagePosition) (*pageResult[EvaluationRunSummaryResponse], error) {
+		opts.Page = pos.Page
+		res, err := c.ListEvaluationRuns(ctx, agentID, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &pageResult[EvaluationRunSummaryResponse]{Items: res.Data, Total: res.Total, Limit: res.Limit}, nil
+	})
+}
+
+// ── Agent Email Governance ────────────────────────────────────────────────────
+
+// AllAgentEmailOptOuts iterates over every agent-email opt-out. The endpoint
+// paginates by limit/offset; opts.Offset selects where to begin.
+func (c *Client) AllAgentEmailOptOuts(ctx context.Context, opts AgentEmailOptOutOptions) iter.Seq2[AgentEmailOptOutResponse, error] {
+	return paginate(ctx, pagePosition{Offset: opts.Offset}, opts.Limit, func(ctx context.Context, pos pagePosition) (*pageResult[AgentEmailOptOutResponse], error) {
+		opts.Offset = pos.Offset
+		res, err := c.ListAgentEmailOptOuts(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &pageResult[AgentEmailOptOutResponse]{Items: res.Items, Total: res.Total}, nil
+	})
+}
+
+// AllBlockedEmailSenders iterates over every blocked inbound email sender,
+// newest first. The endpoint paginates by limit/offset; opts.Offset selects
+// where to begin.
+func (c *Client) AllBlockedEmailSenders(ctx context.Context, opts BlockedEmailSenderOptions) iter.Seq2[BlockedEmailSenderResponse, error] {
+	return paginate(ctx, pagePosition{Offset: opts.Offset}, opts.Limit, func(ctx context.Context, pos pagePosition) (*pageResult[BlockedEmailSenderResponse], error) {
+		opts.Offset = pos.Offset
+		res, err := c.ListBlockedEmailSenders(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &pageResult[BlockedEmailSenderResponse]{Items: res.Items, Total: res.Total}, nil
+	})
+}
+
+// ── Knowledge Bases & Memory Banks ──────────────────────────────────────────
+
+// AllKnowledgeBases iterates over every knowledge base.
+func (c *Client) AllKnowledgeBases(ctx context.Context, opts SortableListOptions) iter.Seq2[KnowledgeBaseResponse, error] {
+	return paginate(ctx, pageStart(opts.ListOptions), opts.Limit, func(ctx context.Context, pos pagePosition) (*pageResult[KnowledgeBaseResponse], error) {
+		opts.Page = pos.Page
+		res, err := c.ListKnowledgeBases(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &pageResult[KnowledgeBaseResponse]{Items: res.KnowledgeBases, Total: res.Total, Limit: res.Limit}, nil
+	})
+}
+
+// AllMemoryBanks iterates over every memory bank.
+func (c *Client) AllMemoryBanks(ctx context.Context, opts SortableListOptions) iter.Seq2[MemoryBankResponse, error] {
+	return paginate(ctx, pageStart(opts.ListOptions), opts.Limit, func(ctx context.Context, pos pagePosition) (*pageResult[MemoryBankResponse], error) {
+		opts.Page = pos.Page
+		res, err := c.ListMemoryBanks(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &pageResult[MemoryBankResponse]{Items: res.MemoryBanks, Total: res.Total, Limit: res.Limit}, nil
+	})
+}
+
+// ── Sources & Content ───────────────────────────────────────────────────────
+
+// AllSources iterates over every source.
+func (c *Client) AllSources(ctx context.Context, opts ListSourcesOptions) iter.Seq2[SourceResponse, error] {
+	return paginate(ctx, pageStart(opts.ListOptions), opts.Limit, func(ctx context.Context, pos pagePosition) (*pageResult[SourceResponse], error) {
+		opts.Page = pos.Page
+		res, err := c.ListSources(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &pageResult[SourceResponse]{Items: res.Data, Pagination: &res.Pagination}, nil
+	})
+}
+
+// AllSourceExports iterates over every export of a source.
+//
+// Unlike [Client.ListSourceExports], whose generated response leaves the items
+// untyped, it yields decoded [ExportResponse] values.
+func (c *Client) AllSourceExports(ctx context.Context, sourceID string, opts ListOptions) iter.Seq2[ExportResponse, error] {
+	return paginate(ctx, pageStart(opts), opts.Limit, func(ctx context.Context, pos pagePosition) (*pageResult[ExportResponse], error) {
+		var out struct {
+			Data       []ExportResponse   `json:"data"`
+			Pagination PaginationResponse `json:"pagination"`
+		}
+		if err := c.Do(ctx, http.MethodGet, fmt.Sprintf("/sources/%s/exports", url.PathEscape(sourceID)), listQuery(pos.Page, opts.Limit), nil, nil, &out); err != nil {
+			return nil, err
+		}
+		return &pageResult[ExportResponse]{Items: out.Data, Pagination: &out.Pagination}, nil
+	})
+}
+
+// AllContentEmbeddings iterates over every embedding of a content version.
+func (c *Client) AllContentEmbeddings(ctx context.Context, contentVersionID string, opts ListOptions) iter.Seq2[ContentEmbeddingResponse, error] {
+	return paginate(ctx, pageStart(opts), opts.Limit, func(ctx context.Context, pos pagePosition) (*pageResult[ContentEmbeddingResponse], error) {
+		opts.Page = pos.Page
+		res, err := c.ListContentEmbeddings(ctx, contentVersionID, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &pageResult[ContentEmbeddingResponse]{Items: res.Data, Pagination: &res.Pagination}, nil
+	})
+}
+
+// ── Solutions ───────────────────────────────────────────────────────────────
+
+// AllSolutions iterates over every solution.
+func (c *Client) AllSolutions(ctx context.Context, opts SortableListOptions) iter.Seq2[SolutionSummaryResponse, error] {
+	return paginate(ctx, pageStart(opts.ListOptions), opts.Limit, func(ctx context.Context, pos pagePosition) (*pageResult[SolutionSummaryResponse], error) {
+		opts.Page = pos.Page
+		res, err := c.ListSolutions(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &pageResult[SolutionSummaryResponse]{Items: res.Data, Pagination: &res.Pagination}, nil
+	})
+}
+
+// ── Alerts ──────────────────────────────────────────────────────────────────
+
+// AllAlerts iterates over every alert.
+func (c *Client) AllAlerts(ctx context.Context, opts ListAlertsOptions) iter.Seq2[AlertResponse, error] {
+	return paginate(ctx, pageStart(opts.ListOptions), opts.Limit, func(ctx context.Context, pos pagePosition) (*pageResult[AlertResponse], error) {
+		opts.Page = pos.Page
+		res, err := c.Typed().ListAlerts(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &pageResult[AlertResponse]{Items: res.Data, Pagination: &res.Pagination}, nil
+	})
+}
+
+// AllAlertConfigs iterates over every alert configuration, whichever envelope
+// the API version selects.
+func (c *Client) AllAlertConfigs(ctx context.Context, opts ListOptions) iter.Seq2[AlertConfigResponse, error] {
+	return paginate(ctx, pageStart(opts), opts.Limit, func(ctx context.Context, pos pagePosition) (*pageResult[AlertConfigResponse], error) {
+		opts.Page = pos.Page
+		res, err := c.Typed().ListAlertConfigs(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &pageResult[AlertConfigResponse]{Items: res.Items(), Pagination: res.Pagination, Total: res.Total}, nil
+	})
+}
+
+// AllModelAlerts iterates over every model lifecycle alert, whichever envelope
+// the API version selects.
+func (c *Client) AllModelAlerts(ctx context.Context, opts ListOptions) iter.Seq2[ModelAlertResponse, error] {
+	return paginate(ctx, pageStart(opts), opts.Limit, func(ctx context.Context, pos pagePosition) (*pageResult[ModelAlertResponse], error) {
+		// ListModelAlerts translates the page into the offset the endpoint declares.
+		opts.Page = pos.Page
+		res, err := c.Typed().ListModelAlerts(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &pageResult[ModelAlertResponse]{Items: res.Items(), Pagination: res.Pagination, Total: res.Total}, nil
+	})
+}
+
+// AllExperiments iterates over every model playground experiment. The
+// endpoint paginates by limit/offset; opts.Offset selects where to begin.
+func (c *Client) AllExperiments(ctx context.Context, opts ListExperimentsOptions) iter.Seq2[ExperimentSummaryResponse, error] {
+	return paginate(ctx, pagePosition{Offset: opts.Offset}, opts.Limit, func(ctx context.Context, pos pagePosition) (*pageResult[ExperimentSummaryResponse], error) {
+		opts.Offset = pos.Offset
+		res, err := c.Typed().ListExperiments(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &pageResult[ExperimentSummaryResponse]{Items: res.Experiments, Total: res.Total}, nil
+	})
+}
@@ -0,0 +1,196 @@
+package seclai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// pagedServer answers with page(query) and records each request's query.
+func pagedServer(t *testing.T, page func(q map[string]string) string) (*Client, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.RawQuery)
+		mu.Unlock()
+		q := map[string]string{}
+		for k, v := range r.URL.Query() {
+			q[k] = v[0]
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, page(q))
+	}))
+	t.Cleanup(srv.Close)
+	c, _ := NewClient(Options{APIKey: "k", BaseURL: srv.URL})
+	return c, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), queries...)
+	}
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+func TestAllAgents_FollowsHasNext(t *testing.T) {
+	c, queries := pagedServer(t, func(q map[string]string) string {
+		p := atoi(q["page"])
+		return fmt.Sprintf(`{"data":[{"id":"a%d"},{"id":"b%d"}],"pagination":{"page":%d,"limit":2,"total":6,"pages":3,"has_next":%t,"has_prev":%t}}`,
+			p, p, p, p < 3, p > 1)
+	})
+
+	var ids []string
+	for agent, err := range c.AllAgents(context.Background(), ListOptions{Limit: 2}) {
+		if err != nil {
+			t.Fatalf("AllAgents: %v", err)
+		}
+		ids = append(ids, agent.Id)
+	}
+	if got := strings.Join(ids, ","); got != "a1,b1,a2,b2,a3,b3" {
+		t.Fatalf("unexpected agents %s", got)
+	}
+	if got := strings.Join(queries(), " "); got != "limit=2&page=1 limit=2&page=2 limit=2&page=3" {
+		t.Fatalf("unexpected requests %s", got)
+	}
+}
+
+func TestAllBlockedEmailSenders_AdvancesOffsetToTotal(t *testing.T) {
+	c, queries := pagedServer(t, func(q map[string]string) string {
+		off := atoi(q["offset"])
+		var items []string
+		for i := off; i < off+2 && i < 5; i++ {
+			items = append(items, fmt.Sprintf(`{"id":"00000000-0000-0000-0000-00000000000%d"}`, i))
+		}
+		return `{"auto_block_mode":"disabled","total":5,"items":[` + strings.Join(items, ",") + `]}`
+	})
+
+	var n int
+	for _, err := range c.AllBlockedEmailSenders(context.Background(), BlockedEmailSenderOptions{Limit: 2}) {
+		if err != nil {
+			t.Fatalf("AllBlockedEmailSenders: %v", err)
+		}
+		n++
+	}
+	if n != 5 {
+		t.Fatalf("expected 5 senders, got %d", n)
+	}
+	if got := strings.Join(queries(), " "); got != "limit=2 limit=2&offset=2 limit=2&offset=4" {
+		t.Fatalf("unexpected requests %s", got)
+	}
+}
+
+func TestAllKnowledgeBases_LaterStartPageWithoutLimit(t *testing.T) {
+	// The server's default page size is 3, over 7 knowledge bases.
+	c, queries := pagedServer(t, func(q map[string]string) string {
+		p := atoi(q["page"])
+		var items []string
+		for i := (p - 1) * 3; i < p*3 && i < 7; i++ {
+			items = append(items, "{}")
+		}
+		return fmt.Sprintf(`{"knowledge_bases":[%s],"total":7,"page":%d,"limit":3}`, strings.Join(items, ","), p)
+	})
+
+	var n int
+	for _, err := range c.AllKnowledgeBases(context.Background(), SortableListOptions{ListOptions: ListOptions{Page: 2}}) {
+		if err != nil {
+			t.Fatalf("AllKnowledgeBases: %v", err)
+		}
+		n++
+	}
+	if n != 4 {
+		t.Fatalf("expected the 4 knowledge bases from page 2 on, got %d", n)
+	}
+	if got := strings.Join(queries(), " "); got != "page=2 page=3" {
+		t.Fatalf("unexpected requests %s", got)
+	}
+}
+
+func TestAllAlertConfigs_LegacyAndCanonicalEnvelopes(t *testing.T) {
+	legacy, _ := pagedServer(t, func(q map[string]string) string {
+		if q["page"] == "1" {
+			return `{"configs":[{"id":"c1"},{"id":"c2"}],"total":3}`
+		}
+		return `{"configs":[{"id":"c3"}],"total":3}`
+	})
+	canonical, _ := pagedServer(t, func(q map[string]string) string {
+		if q["page"] == "1" {
+			return `{"data":[{"id":"c1"},{"id":"c2"}],"pagination":{"page":1,"limit":2,"total":3,"pages":2,"has_next":true,"has_prev":false}}`
+		}
+		return `{"data":[{"id":"c3"}],"pagination":{"page":2,"limit":2,"total":3,"pages":2,"has_next":false,"has_prev":true}}`
+	})
+
+	for name, c := range map[string]*Client{"legacy": legacy, "canonical": canonical} {
+		var ids []string
+		for cfg, err := range c.AllAlertConfigs(context.Background(), ListOptions{Limit: 2}) {
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			ids = append(ids, cfg.Id)
+		}
+		if got := strings.Join(ids, ","); got != "c1,c2,c3" {
+			t.Errorf("%s: unexpected configs %s", name, got)
+		}
+	}
+}
+
+func TestAllEvaluationCriteria_BareArrayStopsOnShortPage(t *testing.T) {
+	c, queries := pagedServer(t, func(q map[string]string) string {
+		if q["page"] == "1" {
+			return `[{"id":"00000000-0000-0000-0000-000000000001"},{"id":"00000000-0000-0000-0000-000000000002"}]`
+		}
+		return `[{"id":"00000000-0000-0000-0000-000000000003"}]`
+	})
+
+	var n int
+	for _, err := range c.AllEvaluationCriteria(context.Background(), "a_1", ListOptions{Limit: 2}) {
+		if err != nil {
+			t.Fatalf("AllEvaluationCriteria: %v", err)
+		}
+		n++
+	}
+	if n != 3 || len(queries()) != 2 {
+		t.Fatalf("expected 3 criteria over 2 requests, got %d over %d", n, len(queries()))
+	}
+}
+
+func TestPaginate_StopsWhenTheLoopBreaks(t *testing.T) {
+	c, queries := pagedServer(t, func(q map[string]string) string {
+		return `{"data":[{"id":"a"},{"id":"b"}],"pagination":{"page":1,"limit":2,"total":100,"pages":50,"has_next":true,"has_prev":false}}`
+	})
+	for range c.AllSources(context.Background(), ListSourcesOptions{}) {
+		break
+	}
+	if n := len(queries()); n != 1 {
+		t.Fatalf("expected a single request, got %d", n)
+	}
+}
+
+func TestPaginate_YieldsTheErrorAndStops(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
+	}))
+	t.Cleanup(srv.Close)
+	c, _ := NewClient(Options{APIKey: "k", BaseURL: srv.URL})
+
+	var errs int
+	for _, err := range c.AllAgentRuns(context.Background(), "a_1", ListAgentRunsOptions{}) {
+		var apiErr *APIStatusError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != 500 {
+			t.Fatalf("expected the 500, got %v", err)
+		}
+		errs++
+	}
+	if errs != 1 {
+		t.Fatalf("expected one error, got %d", errs)
+	}
+}
@@ -0,0 +1,100 @@
+package seclai
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RateLimiter paces outgoing requests. Wait blocks until the next request may
+// be sent, or returns an error once ctx is done.
+//
+// *rate.Limiter from golang.org/x/time/rate satisfies it, as does [TokenBucket].
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// TokenBucket is a [RateLimiter] that allows bursts of up to Burst requests
+// and refills at a steady rate. It is safe for concurrent use, so one bucket
+// can pace every client in a process.
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket returns a full bucket that refills at perSecond tokens per
+// second and holds at most burst. A burst below 1 is treated as 1.
+func NewTokenBucket(perSecond float64, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{rate: perSecond, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// Wait takes a token, blocking until one is available.
+//
+// It fails straight away, without waiting, when ctx's deadline would pass
+// before the token arrives.
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	wait, err := b.reserve(ctx)
+	if err != nil || wait <= 0 {
+		return err
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		b.refund()
+		return ctx.Err()
+	}
+}
+
+// reserve takes a token, possibly on credit, and returns how long until it is
+// actually available.
+func (b *TokenBucket) reserve(ctx context.Context) (time.Duration, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, nil
+	}
+	if b.rate <= 0 {
+		return 0, fmt.Errorf("seclai: rate limiter has no refill rate")
+	}
+	wait := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+	if deadline, ok := ctx.Deadline(); ok && now.Add(wait).After(deadline) {
+		return 0, fmt.Errorf("seclai: rate limiter wait of %s exceeds the context deadline: %w", wait, context.DeadlineExceeded)
+	}
+	b.tokens--
+	return wait, nil
+}
+
+// refund returns a token taken by a Wait that gave up.
+func (b *TokenBucket) refund() {
+	b.mu.Lock()
+	b.tokens++
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.mu.Unlock()
+}
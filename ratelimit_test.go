@@ -0,0 +1,223 @@
+package seclai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimitError_ParsesHeaders(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "7")
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("RateLimit-Remaining", "0")
+		w.Header().Set("RateLimit-Reset", "42")
+		w.WriteHeader(429)
+		_, _ = io.WriteString(w, `{"detail":"slow down"}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	c, _ := NewClient(Options{APIKey: "k", BaseURL: srv.URL})
+	_, err := c.RunAgent(context.Background(), "a_1", AgentRunRequest{})
+
+	var rl *RateLimitError
+	if !errors.As(err, &rl) {
+		t.Fatalf("expected RateLimitError, got %T %v", err, err)
+	}
+	if rl.RetryAfter != 7*time.Second {
+		t.Fatalf("expected RetryAfter 7s, got %v", rl.RetryAfter)
+	}
+	if rl.Limit == nil || *rl.Limit != 100 || rl.Remaining == nil || *rl.Remaining != 0 {
+		t.Fatalf("unexpected limit/remaining: %v %v", rl.Limit, rl.Remaining)
+	}
+	if rl.Reset == nil || *rl.Reset != 42*time.Second {
+		t.Fatalf("unexpected reset: %v", rl.Reset)
+	}
+	if rl.Header.Get("X-RateLimit-Limit") != "100" {
+		t.Fatalf("expected raw headers to be kept, got %v", rl.Header)
+	}
+	// Code matching on the base type must keep seeing 429s.
+	var apiErr *APIStatusError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 429 {
+		t.Fatalf("expected RateLimitError to unwrap to a 429 APIStatusError, got %v", apiErr)
+	}
+}
+
+func TestRateLimitError_ReturnedFromEveryPath(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(429)
+	}))
+	t.Cleanup(srv.Close)
+	c, _ := NewClient(Options{APIKey: "k", BaseURL: srv.URL})
+	ctx := context.Background()
+
+	_, uploadErr := c.UploadFileToSource(ctx, "s_1", UploadFileRequest{File: []byte("x"), FileName: "a.txt"})
+	_, waitErr := c.RunStreamingAgentAndWait(ctx, "a_1", AgentRunStreamRequest{})
+	_, errCh := c.RunStreamingAgent(ctx, "a_1", AgentRunStreamRequest{})
+	streamErr := <-errCh
+	_, exportErr := c.DownloadSourceExport(ctx, "s_1", "e_1")
+	_, attachErr := c.DownloadAgentRunAttachment(ctx, "r_1", "att", "")
+
+	for name, err := range map[string]error{
+		"upload": uploadErr, "stream-and-wait": waitErr, "stream": streamErr,
+		"export download": exportErr, "attachment download": attachErr,
+	} {
+		var rl *RateLimitError
+		if !errors.As(err, &rl) {
+			t.Errorf("%s: expected RateLimitError, got %T %v", name, err, err)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"", 0, false},
+		{"3", 3 * time.Second, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{"Wed, 01 Jul 2026 12:00:30 GMT", 30 * time.Second, true},
+		{"Wed, 01 Jul 2026 11:00:00 GMT", 0, true},
+	}
+	for _, tc := range cases {
+		got, ok := parseRetryAfter(tc.in, now)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tc.in, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestRetry_RetryAfterReplacesBackoff(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(429)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	// An hour of backoff would time the test out; the server's zero wins.
+	c, _ := NewClient(Options{APIKey: "k", BaseURL: srv.URL, Retry: &RetryPolicy{MaxAttempts: 2, BaseDelay: time.Hour, MaxDelay: time.Hour}})
+	if err := c.Do(context.Background(), http.MethodGet, "/agents", nil, nil, nil, nil); err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Fatalf("expected 2 attempts, got %d", n)
+	}
+}
+
+func TestRetry_RetryAfterBeyondTheDeadlineReturnsImmediately(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "20")
+		w.WriteHeader(429)
+	}))
+	t.Cleanup(srv.Close)
+
+	c, _ := NewClient(Options{APIKey: "k", BaseURL: srv.URL, Retry: &RetryPolicy{MaxAttempts: 3, MaxDelay: time.Minute}})
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	start := time.Now()
+	err := c.Do(ctx, http.MethodGet, "/agents", nil, nil, nil, nil)
+	var rl *RateLimitError
+	if !errors.As(err, &rl) || rl.RetryAfter != 20*time.Second {
+		t.Fatalf("expected the RateLimitError, got %T %v", err, err)
+	}
+	if time.Since(start) > time.Second || calls.Load() != 1 {
+		t.Fatalf("expected no wait and one attempt, got %v and %d", time.Since(start), calls.Load())
+	}
+}
+
+func TestRetry_RetryAfterBeyondMaxDelayIsNotWaited(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(429)
+	}))
+	t.Cleanup(srv.Close)
+
+	c, _ := NewClient(Options{APIKey: "k", BaseURL: srv.URL, Retry: DefaultRetryPolicy()})
+	err := c.Do(context.Background(), http.MethodGet, "/agents", nil, nil, nil, nil)
+	var rl *RateLimitError
+	if !errors.As(err, &rl) || calls.Load() != 1 {
+		t.Fatalf("expected one attempt ending in RateLimitError, got %d and %v", calls.Load(), err)
+	}
+}
+
+type countingLimiter struct{ n atomic.Int32 }
+
+func (l *countingLimiter) Wait(ctx context.Context) error {
+	l.n.Add(1)
+	return ctx.Err()
+}
+
+func TestRateLimiter_PacesEveryAttempt(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(503)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"data":[],"pagination":{"page":1,"limit":20,"total":0,"pages":0,"has_next":false,"has_prev":false}}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	lim := &countingLimiter{}
+	c, _ := NewClient(Options{APIKey: "k", BaseURL: srv.URL, RateLimiter: lim, Retry: fastRetry()})
+	if _, err := c.GetAgent(context.Background(), "a_1"); err != nil {
+		t.Fatalf("GetAgent: %v", err)
+	}
+	if _, err := c.Generated().ListSourcesApiSourcesGetWithResponse(context.Background(), nil); err != nil {
+		t.Fatalf("ListSources: %v", err)
+	}
+	if n := lim.n.Load(); n != 3 {
+		t.Fatalf("expected the limiter consulted for all 3 attempts, got %d", n)
+	}
+}
+
+func TestTokenBucket_AllowsBurstThenPaces(t *testing.T) {
+	b := NewTokenBucket(50, 2)
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := b.Wait(ctx); err != nil {
+			t.Fatalf("Wait %d: %v", i, err)
+		}
+	}
+	// Two tokens are free; the third arrives after 1/50s.
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Fatalf("expected the third token to wait, took %v", elapsed)
+	}
+}
+
+func TestTokenBucket_FailsFastPastTheDeadline(t *testing.T) {
+	b := NewTokenBucket(0.1, 1)
+	_ = b.Wait(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := b.Wait(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+	if time.Since(start) > 40*time.Millisecond {
+		t.Fatal("expected Wait to fail without sleeping")
+	}
+}
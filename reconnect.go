@@ -0,0 +1,163 @@
+package seclai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/seclai/seclai-go/generated"
+)
+
+// StreamReconnectPolicy configures [Options.StreamReconnect]: how
+// [Client.RunStreamingAgent] follows a run whose stream drops before the
+// done event. The run keeps executing server-side, so the client picks it
+// up again instead of reporting a bare read error.
+//
+// Once the init event has named the run, the client polls
+// [Client.GetAgentRun] until the run is terminal, emitting an "update"
+// event for each new snapshot and a "done" event for the last, after a
+// synthetic "reconnected" event whose data is a [ReconnectedEvent]. The
+// stream is not reopened: the API has no endpoint that resumes a run's
+// stream, and repeating the POST would start, and bill, a second run.
+//
+// A stream that drops before the init event cannot be followed and still
+// fails, as does one that exceeds the maximum event size.
+type StreamReconnectPolicy struct {
+	// PollInterval is how often the run is fetched. Defaults to 2s.
+	PollInterval time.Duration
+
+	// MaxPollRetries is how many consecutive transient GetAgentRun failures
+	// (connection errors, timeouts, 408, 429 and 5xx) are retried before the
+	// error is returned. The wait doubles from PollInterval with each one.
+	// Defaults to 3.
+	MaxPollRetries int
+
+	// IncludeStepOutputs requests step details in the polled snapshots.
+	IncludeStepOutputs bool
+}
+
+const (
+	defaultPollInterval   = 2 * time.Second
+	defaultMaxPollRetries = 3
+)
+
+// ReconnectedEvent is the data of the synthetic "reconnected" event.
+type ReconnectedEvent struct {
+	// RunID is the run being followed.
+	RunID string `json:"run_id"`
+	// Error is the failure that interrupted the stream, empty when it ended
+	// without one.
+	Error string `json:"error,omitempty"`
+}
+
+// normalized returns p with its defaults applied.
+func (p StreamReconnectPolicy) normalized() StreamReconnectPolicy {
+	if p.PollInterval <= 0 {
+		p.PollInterval = defaultPollInterval
+	}
+	if p.MaxPollRetries <= 0 {
+		p.MaxPollRetries = defaultMaxPollRetries
+	}
+	return p
+}
+
+// recover follows the run by polling after its stream ended before the done
+// event, cause being the read error or nil for a clean end.
+func (s *runStream) recover(ctx context.Context, p StreamReconnectPolicy, cause error) error {
+	if s.runID == "" {
+		if cause != nil {
+			return cause
+		}
+		return &StreamingError{Message: "stream ended before the init event named the run"}
+	}
+	ev := ReconnectedEvent{RunID: s.runID}
+	attrs := []slog.Attr{slog.String("agent_id", s.agentID), slog.String("run_id", s.runID)}
+	if cause != nil {
+		ev.Error = s.c.redact.string(cause.Error())
+		attrs = append(attrs, slog.String("error", ev.Error))
+	}
+	s.c.logger.LogAttrs(ctx, slog.LevelWarn, "seclai: stream interrupted; polling the run", attrs...)
+
+	data, _ := json.Marshal(ev)
+	if err := s.send(newAgentRunEvent(string(AgentRunEventReconnected), string(data), s.lastEventID)); err != nil {
+		return err
+	}
+	return s.poll(ctx, p)
+}
+
+// poll follows the run with GetAgentRun until it is terminal, retrying
+// transient failures.
+func (s *runStream) poll(ctx context.Context, p StreamReconnectPolicy) error {
+	var opts *GetAgentRunOptions
+	if p.IncludeStepOutputs {
+		opts = &GetAgentRunOptions{IncludeStepOutputs: true}
+	}
+	var last []byte
+	failures := 0
+	for {
+		run, err := s.c.GetAgentRun(ctx, s.runID, opts)
+		if err != nil {
+			if ctx.Err() != nil || failures >= p.MaxPollRetries || !transientPollError(err) {
+				return err
+			}
+			failures++
+			wait := p.PollInterval << (failures - 1)
+			s.c.logger.LogAttrs(ctx, slog.LevelWarn, "seclai: polling the run failed; retrying",
+				slog.String("run_id", s.runID), slog.Int("attempt", failures),
+				slog.Duration("wait", wait), slog.String("error", s.c.redact.string(err.Error())))
+			if err := sleepContext(ctx, wait); err != nil {
+				return err
+			}
+			continue
+		}
+		failures = 0
+		data, err := json.Marshal(run)
+		if err != nil {
+			return err
+		}
+		if isTerminalRunStatus(run.Status) {
+			s.done = true
+			return s.send(newAgentRunEvent(string(AgentRunEventDone), string(data), s.lastEventID))
+		}
+		if string(data) != string(last) {
+			last = data
+			if err := s.send(newAgentRunEvent(string(AgentRunEventUpdate), string(data), s.lastEventID)); err != nil {
+				return err
+			}
+		}
+		if err := sleepContext(ctx, p.PollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// transientPollError reports whether a failed GetAgentRun may succeed if
+// repeated: a transient network error or a status [DefaultRetryPolicy]
+// retries.
+func transientPollError(err error) bool {
+	var statusErr *APIStatusError
+	if errors.As(err, &statusErr) {
+		return slices.Contains(defaultRetryableStatusCodes, statusErr.StatusCode)
+	}
+	return isTransientNetworkError(err)
+}
+
+// isTerminalRunStatus reports whether a run has finished.
+func isTerminalRunStatus(status generated.PendingProcessingCompletedFailedStatus) bool {
+	return status == generated.PendingProcessingCompletedFailedStatusCompleted ||
+		status == generated.PendingProcessingCompletedFailedStatusFailed
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
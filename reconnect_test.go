@@ -0,0 +1,198 @@
+package seclai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const (
+	runProcessing = `{"attempts":[],"error_count":0,"priority":true,"run_id":"run_1","status":"processing"}`
+	runCompleted  = `{"attempts":[],"error_count":0,"priority":true,"run_id":"run_1","status":"completed","output":"ok"}`
+)
+
+// collectEvents drains a RunStreamingAgent call.
+func collectEvents(t *testing.T, c *Client) ([]AgentRunEvent, error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ch, errCh := c.RunStreamingAgent(ctx, "agent_1", AgentRunStreamRequest{})
+	var events []AgentRunEvent
+	for evt := range ch {
+		events = append(events, evt)
+	}
+	return events, <-errCh
+}
+
+func eventNames(events []AgentRunEvent) string {
+	names := make([]string, len(events))
+	for i, e := range events {
+		names[i] = e.Event
+	}
+	return strings.Join(names, ",")
+}
+
+func reconnectedData(t *testing.T, evt AgentRunEvent) ReconnectedEvent {
+	t.Helper()
+	var ev ReconnectedEvent
+	if err := json.Unmarshal([]byte(evt.Data), &ev); err != nil {
+		t.Fatalf("reconnected data %q: %v", evt.Data, err)
+	}
+	return ev
+}
+
+// dropConnection ends the response mid-stream without finishing it, as a
+// failed network would.
+func dropConnection(t *testing.T, w http.ResponseWriter) {
+	t.Helper()
+	w.(http.Flusher).Flush()
+	conn, _, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		t.Fatalf("hijack: %v", err)
+	}
+	_ = conn.Close()
+}
+
+func TestRunStreamingAgent_PollsInsteadOfRepeatingThePost(t *testing.T) {
+	var posts, gets atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost:
+			posts.Add(1)
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = io.WriteString(w, "id: 1\nevent: init\ndata: "+runProcessing+"\n\nid: 2\nevent: update\ndata: "+runProcessing+"\n\n")
+			dropConnection(t, w)
+		case r.Method == http.MethodGet && r.URL.Path == "/agents/runs/run_1":
+			w.Header().Set("Content-Type", "application/json")
+			if gets.Add(1) < 3 {
+				_, _ = io.WriteString(w, runProcessing)
+			} else {
+				_, _ = io.WriteString(w, runCompleted)
+			}
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient(Options{APIKey: "k", BaseURL: srv.URL, StreamReconnect: &StreamReconnectPolicy{PollInterval: 10 * time.Millisecond}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, err := collectEvents(t, c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Repeating the POST would start a second run, even with event ids.
+	if n := posts.Load(); n != 1 {
+		t.Fatalf("expected one POST, got %d", n)
+	}
+	// The second poll repeats the first snapshot, so it is not re-emitted.
+	if got := eventNames(events); got != "init,update,reconnected,update,done" {
+		t.Fatalf("events = %s", got)
+	}
+	if ev := reconnectedData(t, events[2]); ev.RunID != "run_1" || ev.Error == "" {
+		t.Fatalf("unexpected reconnected event: %+v", ev)
+	}
+	if done := events[4]; done.ID != "2" || done.Run == nil || done.Run.Status != "completed" {
+		t.Fatalf("unexpected done event: %+v", done)
+	}
+}
+
+func TestRunStreamingAgent_PollsAfterACleanEnd(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = io.WriteString(w, "event: init\ndata: "+runProcessing+"\n\n")
+		case http.MethodGet:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, runCompleted)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient(Options{APIKey: "k", BaseURL: srv.URL, StreamReconnect: &StreamReconnectPolicy{PollInterval: 10 * time.Millisecond}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, err := collectEvents(t, c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := eventNames(events); got != "init,reconnected,done" {
+		t.Fatalf("events = %s", got)
+	}
+	if ev := reconnectedData(t, events[1]); ev.RunID != "run_1" || ev.Error != "" {
+		t.Fatalf("unexpected reconnected event: %+v", ev)
+	}
+}
+
+func TestRunStreamingAgent_PollRetriesTransientErrors(t *testing.T) {
+	newServer := func(statuses ...int) (*Client, *atomic.Int32) {
+		var gets atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost {
+				w.Header().Set("Content-Type", "text/event-stream")
+				_, _ = io.WriteString(w, "event: init\ndata: "+runProcessing+"\n\n")
+				return
+			}
+			if n := int(gets.Add(1)); n <= len(statuses) {
+				w.WriteHeader(statuses[n-1])
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, runCompleted)
+		}))
+		t.Cleanup(srv.Close)
+		c, err := NewClient(Options{APIKey: "k", BaseURL: srv.URL, StreamReconnect: &StreamReconnectPolicy{PollInterval: time.Millisecond, MaxPollRetries: 2}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c, &gets
+	}
+
+	c, gets := newServer(503, 502)
+	events, err := collectEvents(t, c)
+	if err != nil || eventNames(events) != "init,reconnected,done" || gets.Load() != 3 {
+		t.Fatalf("expected two retried failures, got %s, %v after %d polls", eventNames(events), err, gets.Load())
+	}
+
+	c, gets = newServer(503, 503, 503)
+	_, err = collectEvents(t, c)
+	var apiErr *APIStatusError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 503 || gets.Load() != 3 {
+		t.Fatalf("expected the 503 after MaxPollRetries, got %v after %d polls", err, gets.Load())
+	}
+
+	c, gets = newServer(404)
+	_, err = collectEvents(t, c)
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 404 || gets.Load() != 1 {
+		t.Fatalf("expected the 404 without a retry, got %v after %d polls", err, gets.Load())
+	}
+}
+
+func TestRunStreamingAgent_ReconnectNeedsTheRunID(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, ": keepalive\n\n")
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient(Options{APIKey: "k", BaseURL: srv.URL, StreamReconnect: &StreamReconnectPolicy{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, err := collectEvents(t, c)
+	var streamErr *StreamingError
+	if len(events) != 0 || !errors.As(err, &streamErr) {
+		t.Fatalf("expected a StreamingError, got %d events and %v", len(events), err)
+	}
+}
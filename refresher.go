@@ -0,0 +1,239 @@
+package seclai
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// RefreshPolicy configures [Options.BackgroundRefresh]: a goroutine that
+// renews the client's token ahead of expiry, so requests neither wait on a
+// refresh nor fail with one.
+//
+// It covers SSO tokens, renewed with their refresh token, and tokens from an
+// [AccessTokenProvider] that are JWTs with an exp claim, which the client
+// then reuses until they are renewed instead of calling the provider per
+// request. Other credentials have nothing to renew. Stop it with
+// [Client.Close].
+type RefreshPolicy struct {
+	// Fraction is how far through a token's lifetime it is renewed, between
+	// 0 and 1. Defaults to 0.75. A token's lifetime is counted from when the
+	// client first saw it, so a cached SSO token is renewed at Fraction of
+	// the time it had left when the client started.
+	Fraction float64
+
+	// RetryInterval is the wait after a failed refresh. Defaults to 30s. A
+	// request that finds the token expired in the meantime still refreshes
+	// it itself, as without background refresh.
+	RetryInterval time.Duration
+
+	// OnError receives each failed refresh, from the refresher's goroutine.
+	// Failures are also logged at warn level.
+	OnError func(error)
+}
+
+const (
+	defaultRefreshFraction      = 0.75
+	defaultRefreshRetryInterval = 30 * time.Second
+)
+
+// minRefreshWait keeps a token with a very short lifetime from being
+// refreshed in a tight loop.
+var minRefreshWait = time.Second
+
+// errNoRefresh stops the refresher: the credential has nothing to renew.
+var errNoRefresh = errors.New("nothing to refresh")
+
+// providerToken is a token from an AccessTokenProvider, kept while the
+// background refresher renews it.
+type providerToken struct {
+	token     string
+	expiresAt time.Time
+}
+
+// refresher renews a client's token in the background.
+type refresher struct {
+	state  *authState
+	policy RefreshPolicy
+	logger *slog.Logger
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	// seen and since are the token the refresher last saw and when, the
+	// start of its lifetime.
+	seen  string
+	since time.Time
+}
+
+// startRefresher starts renewing state's token, or returns nil when it has
+// none that can be renewed.
+func startRefresher(state *authState, policy RefreshPolicy) *refresher {
+	switch {
+	case state.mode == authModeSSO && state.autoRefresh:
+	case state.mode == authModeBearerProvider:
+	default:
+		return nil
+	}
+	if policy.Fraction <= 0 || policy.Fraction >= 1 {
+		policy.Fraction = defaultRefreshFraction
+	}
+	if policy.RetryInterval <= 0 {
+		policy.RetryInterval = defaultRefreshRetryInterval
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &refresher{
+		state:  state,
+		policy: policy,
+		logger: loggerOrDiscard(state.logger),
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go r.run(ctx)
+	return r
+}
+
+// stop stops the refresher and waits for it to exit.
+func (r *refresher) stop() {
+	r.cancel()
+	<-r.done
+}
+
+func (r *refresher) run(ctx context.Context) {
+	defer close(r.done)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		var wait time.Duration
+		var err error
+		if r.state.mode == authModeSSO {
+			wait, err = r.refreshSso(ctx)
+		} else {
+			wait, err = r.refreshProvider(ctx)
+		}
+		if errors.Is(err, errNoRefresh) {
+			r.logger.DebugContext(ctx, "seclai: background token refresh stopped: the token has no expiry")
+			return
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			r.logger.WarnContext(ctx, "seclai: background token refresh failed", slog.String("error", err.Error()))
+			if r.policy.OnError != nil {
+				r.policy.OnError(err)
+			}
+			wait = r.policy.RetryInterval
+		}
+		timer.Reset(max(wait, minRefreshWait))
+	}
+}
+
+// due returns how long until token, which expires at expiresAt, should be
+// renewed: at Fraction of its lifetime, and no later than a request would
+// find it expired.
+func (r *refresher) due(token string, expiresAt time.Time) time.Duration {
+	now := time.Now()
+	if token != r.seen {
+		r.seen, r.since = token, now
+	}
+	at := r.since.Add(time.Duration(r.policy.Fraction * float64(expiresAt.Sub(r.since))))
+	if latest := expiresAt.Add(-expiryBuffer); latest.Before(at) {
+		at = latest
+	}
+	return at.Sub(now)
+}
+
+// refreshSso renews the SSO token once it is due and returns the wait
+// until the next one.
+func (r *refresher) refreshSso(ctx context.Context) (time.Duration, error) {
+	cached, err := r.state.tokenStore().Get(ctx, r.state.ssoProfile)
+	if err != nil {
+		return 0, err
+	}
+	if cached == nil {
+		return 0, &ConfigurationError{Message: "No cached SSO token found. Run `seclai auth login`, or call seclai.LoginWithDeviceCode, to authenticate via SSO."}
+	}
+	expiresAt, ok := cached.expiry()
+	if !ok {
+		return 0, &ConfigurationError{Message: "cached SSO token has no valid expiry"}
+	}
+	if wait := r.due(cached.AccessToken, expiresAt); wait > 0 {
+		return wait, nil
+	}
+	refreshed, err := refreshSsoToken(ctx, r.state, cached)
+	if err != nil {
+		return 0, err
+	}
+	if refreshed == nil {
+		return 0, &ConfigurationError{Message: "SSO token has no refresh token. Run `seclai auth login`, or call seclai.LoginWithDeviceCode, to authenticate."}
+	}
+	expiresAt, ok = refreshed.expiry()
+	if !ok {
+		return 0, &ConfigurationError{Message: "refreshed SSO token has no valid expiry"}
+	}
+	return r.due(refreshed.AccessToken, expiresAt), nil
+}
+
+// refreshProvider calls the provider once its token is due, keeps the new
+// token for requests, and returns the wait until the next call.
+func (r *refresher) refreshProvider(ctx context.Context) (time.Duration, error) {
+	if cur := r.state.cachedProviderToken(); cur != nil {
+		if wait := r.due(cur.token, cur.expiresAt); wait > 0 {
+			return wait, nil
+		}
+	}
+	token, err := r.state.tokenProvider(ctx)
+	if err != nil {
+		return 0, err
+	}
+	expiresAt, ok := jwtExpiry(token)
+	if !ok {
+		return 0, errNoRefresh
+	}
+	r.state.setProviderToken(&providerToken{token: token, expiresAt: expiresAt})
+	return r.due(token, expiresAt), nil
+}
+
+// cachedProviderToken returns the provider token the refresher keeps, or nil.
+func (s *authState) cachedProviderToken() *providerToken {
+	s.providerMu.Lock()
+	defer s.providerMu.Unlock()
+	return s.provider
+}
+
+func (s *authState) setProviderToken(t *providerToken) {
+	s.providerMu.Lock()
+	defer s.providerMu.Unlock()
+	s.provider = t
+}
+
+// validProviderToken returns the kept provider token while it is valid, with
+// the same buffer before expiry as SSO tokens.
+func (s *authState) validProviderToken() (string, bool) {
+	t := s.cachedProviderToken()
+	if t == nil || !time.Now().Add(expiryBuffer).Before(t.expiresAt) {
+		return "", false
+	}
+	return t.token, true
+}
+
+// Close stops the client's background token refresh, if any, and waits for
+// it to exit. The client keeps working afterwards, refreshing tokens when a
+// request finds them expired. Close is safe to call more than once.
+func (c *Client) Close() error {
+	if c == nil {
+		return nil
+	}
+	c.closeOnce.Do(func() {
+		if c.refresher != nil {
+			c.refresher.stop()
+		}
+	})
+	return nil
+}
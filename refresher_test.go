@@ -0,0 +1,208 @@
+package seclai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func fastRefresh(t *testing.T) {
+	old := minRefreshWait
+	minRefreshWait = 10 * time.Millisecond
+	t.Cleanup(func() { minRefreshWait = old })
+}
+
+// eventually polls cond until it holds or five seconds pass.
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func ssoConfig(t *testing.T, profile *SsoProfile) string {
+	t.Helper()
+	return writeConfig(t, "[default]\nsso_domain = "+profile.SsoDomain+"\nsso_client_id = "+profile.SsoClientID+"\n")
+}
+
+func TestBackgroundRefresh_RenewsSsoTokenBeforeExpiry(t *testing.T) {
+	fastRefresh(t)
+	var refreshes atomic.Int32
+	profile, hc := newSsoServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.Form.Get("refresh_token") != "rt-1" {
+			w.WriteHeader(400)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		refreshes.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"at-2","refresh_token":"rt-1","expires_in":3600}`))
+	})
+	dir := ssoConfig(t, profile)
+	// Still valid, so no request would refresh it yet.
+	if err := WriteSsoCache(dir, profile, &SsoCacheEntry{
+		AccessToken:  "at-1",
+		RefreshToken: "rt-1",
+		ExpiresAt:    time.Now().Add(40 * time.Second).UTC().Format(time.RFC3339),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Renewed about a second in.
+	c, err := NewClient(Options{
+		ConfigDir:         dir,
+		HTTPClient:        hc,
+		BackgroundRefresh: &RefreshPolicy{Fraction: 0.025, OnError: func(err error) { t.Errorf("OnError: %v", err) }},
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	eventually(t, "the background refresh", func() bool {
+		entry, _ := ReadSsoCache(dir, profile)
+		return entry != nil && entry.AccessToken == "at-2"
+	})
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+	if n := refreshes.Load(); n != 1 {
+		t.Fatalf("expected one refresh, got %d", n)
+	}
+}
+
+func TestBackgroundRefresh_ReportsFailures(t *testing.T) {
+	fastRefresh(t)
+	profile, hc := newSsoServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(400)
+		_, _ = w.Write([]byte(`{"error":"invalid_grant","error_description":"refresh token revoked"}`))
+	})
+	dir := ssoConfig(t, profile)
+	if err := WriteSsoCache(dir, profile, &SsoCacheEntry{
+		AccessToken:  "at-1",
+		RefreshToken: "rt-1",
+		ExpiresAt:    time.Now().Add(-time.Minute).UTC().Format(time.RFC3339),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	errs := make(chan error, 16)
+	c, err := NewClient(Options{
+		ConfigDir:  dir,
+		HTTPClient: hc,
+		BackgroundRefresh: &RefreshPolicy{
+			RetryInterval: 10 * time.Millisecond,
+			OnError: func(err error) {
+				select {
+				case errs <- err:
+				default:
+				}
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer c.Close()
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-errs:
+			if !strings.Contains(err.Error(), "refresh token revoked") {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected failure %d to be reported and retried", i+1)
+		}
+	}
+}
+
+func TestBackgroundRefresh_ReusesProviderTokenUntilRenewed(t *testing.T) {
+	fastRefresh(t)
+	var seen atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen.Store(r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[],"pagination":{"page":1,"limit":20,"total":0,"pages":0,"has_next":false,"has_prev":false}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	var calls atomic.Int32
+	c, err := NewClient(Options{
+		BaseURL: srv.URL,
+		AccessTokenProvider: func(context.Context) (string, error) {
+			n := calls.Add(1)
+			return testJWT(t, map[string]any{"n": n, "exp": time.Now().Add(40 * time.Second).Unix()}), nil
+		},
+		// Renewed about a second in.
+		BackgroundRefresh: &RefreshPolicy{Fraction: 0.025},
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer c.Close()
+
+	eventually(t, "the first provider token", func() bool {
+		_, ok := c.auth.validProviderToken()
+		return ok
+	})
+	for i := 0; i < 3; i++ {
+		if _, err := c.ListAgents(context.Background(), ListOptions{}); err != nil {
+			t.Fatalf("ListAgents: %v", err)
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("requests should reuse the provider's token, got %d calls", n)
+	}
+	first := seen.Load()
+	eventually(t, "the provider token to be renewed", func() bool { return calls.Load() >= 2 })
+	if _, err := c.ListAgents(context.Background(), ListOptions{}); err != nil {
+		t.Fatalf("ListAgents: %v", err)
+	}
+	if seen.Load() == first {
+		t.Fatal("expected the renewed token on the next request")
+	}
+}
+
+func TestBackgroundRefresh_StopsForOpaqueProviderTokens(t *testing.T) {
+	var calls atomic.Int32
+	state := &authState{mode: authModeBearerProvider, tokenProvider: func(context.Context) (string, error) {
+		calls.Add(1)
+		return "opaque", nil
+	}}
+	r := startRefresher(state, RefreshPolicy{})
+	select {
+	case <-r.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the refresher should stop for a token without an expiry")
+	}
+	r.stop()
+	if _, ok := state.validProviderToken(); ok {
+		t.Fatal("an opaque token must not be kept")
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected one provider call, got %d", calls.Load())
+	}
+}
+
+func TestBackgroundRefresh_NotStartedForStaticCredentials(t *testing.T) {
+	if r := startRefresher(&authState{mode: authModeAPIKey}, RefreshPolicy{}); r != nil {
+		t.Fatal("an API key has nothing to refresh")
+	}
+	c, err := NewClient(Options{APIKey: "k", BackgroundRefresh: &RefreshPolicy{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,269 @@
+package seclai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how the client retries failed requests.
+//
+// It applies to every request the client sends: [Client.Do], the convenience
+// methods built on it, uploads, streaming runs, downloads and [Client.Generated].
+// A streaming run is retried only until the response headers arrive; once
+// events are flowing a dropped connection is reported, not replayed.
+//
+// POST and PATCH are not idempotent, so they are retried only when a retry
+// cannot run the operation twice: the connection failed before the request
+// was sent, the server answered 429, or the request carries an
+// Idempotency-Key header. Set RetryNonIdempotent to lift that restriction.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Values below 2 disable retries.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry; each later retry doubles it.
+	// Defaults to 500ms.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay between attempts. Defaults to 30s.
+	//
+	// A Retry-After header replaces the computed backoff. One asking for
+	// longer than MaxDelay, or for longer than the context has left, ends
+	// the retries and returns the [RateLimitError] instead.
+	MaxDelay time.Duration
+
+	// Jitter is the fraction of each delay, between 0 and 1, that is randomised
+	// so that clients failing together do not retry together. 0 waits exactly
+	// the backoff; 1 waits anywhere from zero up to it.
+	Jitter float64
+
+	// RetryableStatusCodes lists the HTTP statuses that are retried.
+	// Defaults to 408, 429, 500, 502, 503 and 504 when nil.
+	RetryableStatusCodes []int
+
+	// RetryableError reports whether a transport error is worth retrying.
+	// Defaults to timeouts, connection resets and refusals, and connections
+	// closed before a response arrived. Context cancellation is never retried.
+	RetryableError func(err error) bool
+
+	// RetryNonIdempotent permits retrying POST and PATCH requests that may
+	// already have reached the server.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns the recommended policy: three attempts with
+// exponential backoff from 500ms, capped at 30s, with 20% jitter.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    30 * time.Second,
+		Jitter:      0.2,
+	}
+}
+
+var defaultRetryableStatusCodes = []int{
+	http.StatusRequestTimeout,
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// maxAttempts returns the attempt budget, treating a nil policy as one attempt.
+func (p *RetryPolicy) maxAttempts() int {
+	if p == nil || p.MaxAttempts < 2 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// backoff returns the delay before retry number n (1-based).
+func (p *RetryPolicy) backoff(n int) time.Duration {
+	base := p.BaseDelay
+	if base <= 0 {
+		base = 500 * time.Millisecond
+	}
+	ceiling := p.maxDelay()
+	d := base
+	for i := 1; i < n && d < ceiling; i++ {
+		d *= 2
+	}
+	if d > ceiling {
+		d = ceiling
+	}
+	if j := p.Jitter; j > 0 {
+		if j > 1 {
+			j = 1
+		}
+		d -= time.Duration(float64(d) * j * rand.Float64())
+	}
+	return d
+}
+
+// maxDelay returns MaxDelay or its default.
+func (p *RetryPolicy) maxDelay() time.Duration {
+	if p.MaxDelay <= 0 {
+		return 30 * time.Second
+	}
+	return p.MaxDelay
+}
+
+// retryableStatus reports whether the policy retries an HTTP status.
+func (p *RetryPolicy) retryableStatus(code int) bool {
+	codes := p.RetryableStatusCodes
+	if codes == nil {
+		codes = defaultRetryableStatusCodes
+	}
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// retryableError reports whether the policy retries a transport error.
+func (p *RetryPolicy) retryableError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if p.RetryableError != nil {
+		return p.RetryableError(err)
+	}
+	return isTransientNetworkError(err)
+}
+
+// isTransientNetworkError is the default [RetryPolicy.RetryableError].
+func isTransientNetworkError(err error) bool {
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNABORTED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
+
+// notSent reports whether a transport error happened before any of the
+// request could have reached the server.
+func notSent(err error) bool {
+	var oe *net.OpError
+	if errors.As(err, &oe) && oe.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
+
+// idempotent reports whether repeating req cannot change the outcome.
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != ""
+}
+
+// send issues req and returns its response, pacing it through
+// [Options.RateLimiter], passing each attempt through [Options.Middleware],
+// and retrying per [Options.Retry].
+//
+// Every request path goes through here — the convenience methods, uploads,
+// streaming, downloads and the generated client — so retry behaviour cannot
+// differ between them. A request is only retried when its body can be
+// replayed, which holds for every body this package builds.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	policy := c.retry
+	attempts := policy.maxAttempts()
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		try := req
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			try = req.Clone(ctx)
+			try.Body = body
+		}
+
+		if c.limiter != nil {
+			if err := c.limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+		resp, err := c.transport.RoundTrip(try)
+		if attempt >= attempts || !c.shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		delay := policy.backoff(attempt)
+		if resp != nil {
+			if ra, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				if ra > policy.maxDelay() {
+					return resp, nil
+				}
+				delay = ra
+			}
+		}
+		// Sleeping past the deadline would only trade the server's answer for
+		// a context error, so hand back what we have instead.
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return resp, err
+		}
+		attrs := []slog.Attr{
+			slog.String("method", req.Method),
+			slog.String("url", c.redact.string(req.URL.String())),
+			slog.Int("attempt", attempt),
+			slog.Duration("delay", delay),
+		}
+		if resp != nil {
+			attrs = append(attrs, slog.Int("status", resp.StatusCode))
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		} else {
+			attrs = append(attrs, slog.String("error", c.redact.string(err.Error())))
+		}
+		c.logger.LogAttrs(ctx, slog.LevelInfo, "seclai: retrying request", attrs...)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// shouldRetry decides whether one attempt's outcome warrants another.
+func (c *Client) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	policy := c.retry
+	safe := policy.RetryNonIdempotent || idempotent(req)
+	if err != nil {
+		return policy.retryableError(err) && (safe || notSent(err))
+	}
+	if !policy.retryableStatus(resp.StatusCode) {
+		return false
+	}
+	// A 429 was turned away before the operation ran, so it is safe to repeat.
+	return safe || resp.StatusCode == http.StatusTooManyRequests
+}
@@ -0,0 +1,291 @@
+package seclai
+
+import (
+	"context"
+	"crypto/x509"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func fastRetry() *RetryPolicy {
+	return &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+}
+
+func TestRetry_GetIsRetriedOnServerError(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(503)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"ok":true}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	c, _ := NewClient(Options{APIKey: "k", BaseURL: srv.URL, Retry: fastRetry()})
+	var out map[string]any
+	if err := c.Do(context.Background(), http.MethodGet, "/agents", nil, nil, nil, &out); err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if n := calls.Load(); n != 3 {
+		t.Fatalf("expected 3 attempts, got %d", n)
+	}
+}
+
+func TestRetry_DisabledByDefault(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(503)
+	}))
+	t.Cleanup(srv.Close)
+
+	c, _ := NewClient(Options{APIKey: "k", BaseURL: srv.URL})
+	if err := c.Do(context.Background(), http.MethodGet, "/agents", nil, nil, nil, nil); err == nil {
+		t.Fatal("expected error")
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("expected a single attempt, got %d", n)
+	}
+}
+
+func TestRetry_GivesUpAfterMaxAttempts(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(502)
+	}))
+	t.Cleanup(srv.Close)
+
+	c, _ := NewClient(Options{APIKey: "k", BaseURL: srv.URL, Retry: fastRetry()})
+	err := c.Do(context.Background(), http.MethodGet, "/agents", nil, nil, nil, nil)
+	apiErr, ok := err.(*APIStatusError)
+	if !ok || apiErr.StatusCode != 502 {
+		t.Fatalf("expected the last 502, got %T %v", err, err)
+	}
+	if n := calls.Load(); n != 3 {
+		t.Fatalf("expected 3 attempts, got %d", n)
+	}
+}
+
+func TestRetry_PostIsNotRetriedOnServerError(t *testing.T) {
+	// The server may have run the operation before failing; repeating a POST
+	// could run it twice.
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(500)
+	}))
+	t.Cleanup(srv.Close)
+
+	c, _ := NewClient(Options{APIKey: "k", BaseURL: srv.URL, Retry: fastRetry()})
+	if _, err := c.RunAgent(context.Background(), "a_1", AgentRunRequest{}); err == nil {
+		t.Fatal("expected error")
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("expected a single attempt, got %d", n)
+	}
+}
+
+func TestRetry_PostIsRetriedWhenKnownSafe(t *testing.T) {
+	cases := []struct {
+		name    string
+		status  int
+		headers map[string]string
+		policy  func(*RetryPolicy)
+	}{
+		{name: "429", status: 429},
+		{name: "idempotency key", status: 500, headers: map[string]string{"Idempotency-Key": "abc"}},
+		{name: "opt-in", status: 500, policy: func(p *RetryPolicy) { p.RetryNonIdempotent = true }},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls atomic.Int32
+			var bodies []string
+			bodyCh := make(chan string, 3)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				bodyCh <- string(b)
+				if calls.Add(1) == 1 {
+					w.WriteHeader(tc.status)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = io.WriteString(w, `{}`)
+			}))
+			t.Cleanup(srv.Close)
+
+			policy := fastRetry()
+			if tc.policy != nil {
+				tc.policy(policy)
+			}
+			c, _ := NewClient(Options{APIKey: "k", BaseURL: srv.URL, Retry: policy})
+			err := c.Do(context.Background(), http.MethodPost, "/agents", nil, map[string]string{"name": "x"}, tc.headers, nil)
+			if err != nil {
+				t.Fatalf("Do: %v", err)
+			}
+			close(bodyCh)
+			for b := range bodyCh {
+				bodies = append(bodies, b)
+			}
+			if len(bodies) != 2 || bodies[0] != bodies[1] || bodies[1] != `{"name":"x"}` {
+				t.Fatalf("expected the JSON body replayed on retry, got %q", bodies)
+			}
+		})
+	}
+}
+
+func TestRetry_UploadReplaysMultipartBody(t *testing.T) {
+	var calls atomic.Int32
+	bodyCh := make(chan string, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodyCh <- string(b)
+		if calls.Add(1) == 1 {
+			w.WriteHeader(429)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	c, _ := NewClient(Options{APIKey: "k", BaseURL: srv.URL, Retry: fastRetry()})
+	_, err := c.UploadFileToSource(context.Background(), "s_1", UploadFileRequest{File: []byte("hello"), FileName: "a.txt"})
+	if err != nil {
+		t.Fatalf("UploadFileToSource: %v", err)
+	}
+	first, second := <-bodyCh, <-bodyCh
+	if first == "" || first != second {
+		t.Fatalf("expected identical multipart bodies, got %q and %q", first, second)
+	}
+}
+
+func TestRetry_StreamingRetriesBeforeTheStreamStarts(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(429)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "event: done\ndata: {\"run_id\":\"run_1\",\"status\":\"completed\"}\n\n")
+	}))
+	t.Cleanup(srv.Close)
+
+	c, _ := NewClient(Options{APIKey: "k", BaseURL: srv.URL, Retry: fastRetry()})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	run, err := c.RunStreamingAgentAndWait(ctx, "a_1", AgentRunStreamRequest{})
+	if err != nil {
+		t.Fatalf("RunStreamingAgentAndWait: %v", err)
+	}
+	if run.RunId != "run_1" {
+		t.Fatalf("expected run_1, got %q", run.RunId)
+	}
+}
+
+func TestRetry_GeneratedClientIsCovered(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(504)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"data":[],"pagination":{"page":1,"limit":20,"total":0,"pages":0,"has_next":false,"has_prev":false}}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	c, _ := NewClient(Options{APIKey: "k", BaseURL: srv.URL, Retry: fastRetry()})
+	resp, err := c.Generated().ListSourcesApiSourcesGetWithResponse(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListSources: %v", err)
+	}
+	if resp.StatusCode() != 200 || calls.Load() != 2 {
+		t.Fatalf("expected a retried 200, got %d after %d attempts", resp.StatusCode(), calls.Load())
+	}
+}
+
+func TestRetry_ConnectionRefusedIsRetried(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	addr := srv.URL
+	srv.Close()
+
+	var seen atomic.Int32
+	policy := fastRetry()
+	policy.RetryableError = func(err error) bool {
+		seen.Add(1)
+		return isTransientNetworkError(err)
+	}
+	c, _ := NewClient(Options{APIKey: "k", BaseURL: addr, Retry: policy})
+	if _, err := c.RunAgent(context.Background(), "a_1", AgentRunRequest{}); err == nil {
+		t.Fatal("expected error")
+	}
+	// A refused connection never carried the POST, so it is retried too.
+	if n := seen.Load(); n != 2 {
+		t.Fatalf("expected the classifier consulted before each retry, got %d", n)
+	}
+}
+
+func TestIsTransientNetworkError(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"reset", &net.OpError{Op: "read", Err: syscall.ECONNRESET}, true},
+		{"refused", &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, true},
+		{"timeout", &net.OpError{Op: "dial", Err: &net.DNSError{IsTimeout: true}}, true},
+		{"unexpected EOF", io.ErrUnexpectedEOF, true},
+		{"unreachable", &net.OpError{Op: "dial", Err: syscall.ENETUNREACH}, false},
+		{"no such host", &net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", IsNotFound: true}}, false},
+		{"tls", &net.OpError{Op: "remote error", Err: x509.UnknownAuthorityError{}}, false},
+	} {
+		if got := isTransientNetworkError(tc.err); got != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestRetry_StopsWhenContextIsDone(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(503)
+	}))
+	t.Cleanup(srv.Close)
+
+	c, _ := NewClient(Options{APIKey: "k", BaseURL: srv.URL, Retry: &RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour}})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := c.Do(ctx, http.MethodGet, "/agents", nil, nil, nil, nil); err == nil {
+		t.Fatal("expected error")
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Fatal("expected the backoff wait to end with the context")
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := &RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: 350 * time.Millisecond}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 350 * time.Millisecond, 350 * time.Millisecond}
+	for i, w := range want {
+		if got := p.backoff(i + 1); got != w {
+			t.Fatalf("retry %d: expected %v, got %v", i+1, w, got)
+		}
+	}
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := p.backoff(1); got < 50*time.Millisecond || got > 100*time.Millisecond {
+			t.Fatalf("jittered delay %v outside [50ms, 100ms]", got)
+		}
+	}
+}
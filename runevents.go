@@ -0,0 +1,167 @@
+package seclai
+
+import "encoding/json"
+
+// AgentRunEventKind is the SSE event type of an [AgentRunEvent].
+type AgentRunEventKind string
+
+// The kinds of event an agent run stream carries, as documented for
+// POST /agents/{agent_id}/runs/stream. The stream also forwards step events
+// from the run, without documenting their kinds; they arrive as
+// [StepEvent]. Other kinds arrive as [UnknownEvent].
+const (
+	// AgentRunEventInit is the first event, with the run's initial snapshot.
+	AgentRunEventInit AgentRunEventKind = "init"
+	// AgentRunEventUpdate carries a snapshot after the run changes.
+	AgentRunEventUpdate AgentRunEventKind = "update"
+	// AgentRunEventStatus is sent when the run's status changes.
+	AgentRunEventStatus AgentRunEventKind = "status"
+	// AgentRunEventStreamToken carries one LLM token from a streaming_result
+	// step.
+	AgentRunEventStreamToken AgentRunEventKind = "stream_token"
+	// AgentRunEventStreamEnd ends a streaming_result step's tokens.
+	AgentRunEventStreamEnd AgentRunEventKind = "stream_end"
+	// AgentRunEventDone is the last event, with the run's terminal snapshot.
+	AgentRunEventDone AgentRunEventKind = "done"
+	// AgentRunEventError ends a stream that failed; the run may still be
+	// executing.
+	AgentRunEventError AgentRunEventKind = "error"
+	// AgentRunEventTimeout ends a stream that stopped waiting for the run,
+	// which is still executing.
+	AgentRunEventTimeout AgentRunEventKind = "timeout"
+	// AgentRunEventReconnected is the client's own event, marking where
+	// [Options.StreamReconnect] started polling a dropped stream's run.
+	AgentRunEventReconnected AgentRunEventKind = "reconnected"
+)
+
+// AgentRunEventPayload is the typed data of an [AgentRunEvent]: one of
+// *[InitEvent], *[UpdateEvent], *[StatusEvent], *[StepEvent],
+// *[StreamTokenEvent], *[StreamEndEvent], *[DoneEvent], *[ErrorEvent],
+// *[TimeoutEvent], *[ReconnectedEvent] or *[UnknownEvent]. Switch on its
+// type:
+//
+//	switch p := evt.Payload.(type) {
+//	case *seclai.StepEvent:
+//		fmt.Println(p.AgentStepId, p.Status)
+//	case *seclai.DoneEvent:
+//		fmt.Println(p.Output)
+//	}
+type AgentRunEventPayload interface {
+	// Kind returns the event's kind.
+	Kind() AgentRunEventKind
+}
+
+// InitEvent is the data of an init event.
+type InitEvent struct {
+	AgentRunResponse
+}
+
+// UpdateEvent is the data of an update event.
+type UpdateEvent struct {
+	AgentRunResponse
+}
+
+// StatusEvent is the data of a status event. It may be a partial snapshot:
+// fields the server leaves out are zero.
+type StatusEvent struct {
+	AgentRunResponse
+}
+
+// DoneEvent is the data of a done event: the run's terminal snapshot.
+type DoneEvent struct {
+	AgentRunResponse
+}
+
+// StepEvent is the data of a step event forwarded from the run. Their kinds
+// are not documented, so an event of any kind not listed above whose data
+// names a step by agent_step_id is decoded as one; EventKind is the kind
+// the server sent. Fields the event leaves out are zero.
+type StepEvent struct {
+	EventKind AgentRunEventKind `json:"-"`
+	RunID     string            `json:"run_id"`
+	AgentRunStepResponse
+}
+
+// StreamTokenEvent is the data of a stream_token event.
+type StreamTokenEvent struct {
+	Token string `json:"token"`
+}
+
+// StreamEndEvent is the data of a stream_end event.
+type StreamEndEvent struct{}
+
+// ErrorEvent is the data of an error event. Poll the run with
+// [Client.GetAgentRun] to find how it ended.
+type ErrorEvent struct {
+	RunID string `json:"run_id"`
+	Error string `json:"error,omitempty"`
+}
+
+// TimeoutEvent is the data of a timeout event. Poll the run with
+// [Client.GetAgentRun] to follow it.
+type TimeoutEvent struct {
+	RunID string `json:"run_id"`
+}
+
+// UnknownEvent passes through an event of a kind this release does not
+// know, or whose data did not decode as its kind's payload.
+type UnknownEvent struct {
+	EventKind AgentRunEventKind
+	// Data is the event's raw data.
+	Data json.RawMessage
+}
+
+func (*InitEvent) Kind() AgentRunEventKind        { return AgentRunEventInit }
+func (*UpdateEvent) Kind() AgentRunEventKind      { return AgentRunEventUpdate }
+func (*StatusEvent) Kind() AgentRunEventKind      { return AgentRunEventStatus }
+func (*DoneEvent) Kind() AgentRunEventKind        { return AgentRunEventDone }
+func (e *StepEvent) Kind() AgentRunEventKind      { return e.EventKind }
+func (*StreamTokenEvent) Kind() AgentRunEventKind { return AgentRunEventStreamToken }
+func (*StreamEndEvent) Kind() AgentRunEventKind   { return AgentRunEventStreamEnd }
+func (*ErrorEvent) Kind() AgentRunEventKind       { return AgentRunEventError }
+func (*TimeoutEvent) Kind() AgentRunEventKind     { return AgentRunEventTimeout }
+func (*ReconnectedEvent) Kind() AgentRunEventKind { return AgentRunEventReconnected }
+func (e *UnknownEvent) Kind() AgentRunEventKind   { return e.EventKind }
+
+// newAgentRunEvent builds the event for one SSE event, decoding its payload.
+func newAgentRunEvent(event, data, id string) AgentRunEvent {
+	evt := AgentRunEvent{Event: event, Data: data, ID: id, Payload: decodeRunEventPayload(AgentRunEventKind(event), data)}
+	var parsed AgentRunResponse
+	if json.Unmarshal([]byte(data), &parsed) == nil {
+		evt.Run = &parsed
+	}
+	return evt
+}
+
+func decodeRunEventPayload(kind AgentRunEventKind, data string) AgentRunEventPayload {
+	var p AgentRunEventPayload
+	switch kind {
+	case AgentRunEventInit:
+		p = &InitEvent{}
+	case AgentRunEventUpdate:
+		p = &UpdateEvent{}
+	case AgentRunEventStatus:
+		p = &StatusEvent{}
+	case AgentRunEventDone:
+		p = &DoneEvent{}
+	case AgentRunEventStreamToken:
+		p = &StreamTokenEvent{}
+	case AgentRunEventStreamEnd:
+		p = &StreamEndEvent{}
+	case AgentRunEventError:
+		p = &ErrorEvent{}
+	case AgentRunEventTimeout:
+		p = &TimeoutEvent{}
+	case AgentRunEventReconnected:
+		p = &ReconnectedEvent{}
+	default:
+		step := &StepEvent{EventKind: kind}
+		if json.Unmarshal([]byte(data), step) == nil && step.AgentStepId != "" {
+			return step
+		}
+	}
+	if p == nil || json.Unmarshal([]byte(data), p) != nil {
+		return &UnknownEvent{EventKind: kind, Data: json.RawMessage(data)}
+	}
+	return p
+}
@@ -0,0 +1,112 @@
+package seclai
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDecodeRunEventPayload(t *testing.T) {
+	for _, tc := range []struct {
+		kind  AgentRunEventKind
+		data  string
+		check func(p AgentRunEventPayload) bool
+	}{
+		{AgentRunEventInit, runProcessing, func(p AgentRunEventPayload) bool {
+			e, ok := p.(*InitEvent)
+			return ok && e.RunId == "run_1" && e.Status == "processing"
+		}},
+		{AgentRunEventUpdate, runProcessing, func(p AgentRunEventPayload) bool {
+			e, ok := p.(*UpdateEvent)
+			return ok && e.RunId == "run_1"
+		}},
+		{AgentRunEventDone, runCompleted, func(p AgentRunEventPayload) bool {
+			e, ok := p.(*DoneEvent)
+			return ok && e.Output != nil && *e.Output == "ok"
+		}},
+		{AgentRunEventStatus, `{"run_id":"run_1","status":"processing"}`, func(p AgentRunEventPayload) bool {
+			e, ok := p.(*StatusEvent)
+			return ok && e.RunId == "run_1" && e.Status == "processing"
+		}},
+		// Step events have no documented kind; their agent_step_id marks them.
+		{"step", `{"run_id":"run_1","agent_step_id":"s1","step_type":"prompt_call","status":"completed","output":"hi"}`, func(p AgentRunEventPayload) bool {
+			e, ok := p.(*StepEvent)
+			return ok && e.RunID == "run_1" && e.AgentStepId == "s1" && e.StepType == "prompt_call" &&
+				e.Status == "completed" && e.Output != nil && *e.Output == "hi"
+		}},
+		{AgentRunEventStreamToken, `{"token":"Hel"}`, func(p AgentRunEventPayload) bool {
+			e, ok := p.(*StreamTokenEvent)
+			return ok && e.Token == "Hel"
+		}},
+		{AgentRunEventStreamEnd, `{}`, func(p AgentRunEventPayload) bool {
+			_, ok := p.(*StreamEndEvent)
+			return ok
+		}},
+		{AgentRunEventError, `{"run_id":"run_1","error":"boom"}`, func(p AgentRunEventPayload) bool {
+			e, ok := p.(*ErrorEvent)
+			return ok && e.Error == "boom"
+		}},
+		{AgentRunEventTimeout, `{"run_id":"run_1"}`, func(p AgentRunEventPayload) bool {
+			e, ok := p.(*TimeoutEvent)
+			return ok && e.RunID == "run_1"
+		}},
+		{AgentRunEventReconnected, `{"run_id":"run_1","error":"EOF"}`, func(p AgentRunEventPayload) bool {
+			e, ok := p.(*ReconnectedEvent)
+			return ok && e.RunID == "run_1" && e.Error == "EOF"
+		}},
+		{"memory_written", `{"bank":"m1"}`, func(p AgentRunEventPayload) bool {
+			e, ok := p.(*UnknownEvent)
+			return ok && e.EventKind == "memory_written" && string(e.Data) == `{"bank":"m1"}`
+		}},
+		// A known kind whose data does not decode is passed through too.
+		{AgentRunEventStreamToken, `not json`, func(p AgentRunEventPayload) bool {
+			e, ok := p.(*UnknownEvent)
+			return ok && e.EventKind == AgentRunEventStreamToken && string(e.Data) == "not json"
+		}},
+	} {
+		p := decodeRunEventPayload(tc.kind, tc.data)
+		if p.Kind() != tc.kind || !tc.check(p) {
+			t.Errorf("%s %s: unexpected payload %#v", tc.kind, tc.data, p)
+		}
+	}
+}
+
+func TestRunStreamingAgent_TypedPayloads(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "event: init\ndata: "+runProcessing+"\n\n"+
+			"event: step\ndata: {\"run_id\":\"run_1\",\"agent_step_id\":\"s1\",\"status\":\"completed\"}\n\n"+
+			"event: custom\ndata: [1,2]\n\n"+
+			"event: done\ndata: "+runCompleted+"\n\n")
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient(Options{APIKey: "k", BaseURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, err := collectEvents(t, c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var kinds []AgentRunEventKind
+	for _, evt := range events {
+		if evt.Payload == nil || evt.Payload.Kind() != evt.Kind() {
+			t.Fatalf("event %s has payload %#v", evt.Event, evt.Payload)
+		}
+		kinds = append(kinds, evt.Kind())
+	}
+	if len(kinds) != 4 {
+		t.Fatalf("kinds = %v", kinds)
+	}
+	if st, ok := events[1].Payload.(*StepEvent); !ok || st.AgentStepId != "s1" {
+		t.Fatalf("expected a StepEvent, got %#v", events[1].Payload)
+	}
+	if u, ok := events[2].Payload.(*UnknownEvent); !ok || string(u.Data) != "[1,2]" {
+		t.Fatalf("expected an UnknownEvent, got %#v", events[2].Payload)
+	}
+	if d, ok := events[3].Payload.(*DoneEvent); !ok || d.RunId != "run_1" {
+		t.Fatalf("expected a DoneEvent, got %#v", events[3].Payload)
+	}
+}
@@ -0,0 +1,1660 @@
+// Code generated by cmd/mockgen from api.go. DO NOT EDIT.
+
+package seclaimock
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"net/http"
+
+	seclai "github.com/seclai/seclai-go"
+)
+
+// Client is a mock of [seclai.API], made of the mock of each interface it
+// embeds.
+type Client struct {
+	AccountAPI
+	AgentsAPI
+	RunsAPI
+	EvaluationsAPI
+	KnowledgeBasesAPI
+	MemoryBanksAPI
+	SourcesAPI
+	ContentAPI
+	SolutionsAPI
+	GovernanceAPI
+	AlertsAPI
+	ModelsAPI
+	EmailAPI
+	SearchAPI
+	AIAssistantAPI
+}
+
+var _ seclai.API = (*Client)(nil)
+
+// AccountAPI is a mock of [seclai.AccountAPI].
+type AccountAPI struct {
+	GetMeFunc            func(ctx context.Context) (*seclai.MeResponse, error)
+	IdentityFunc         func(ctx context.Context) (*seclai.Identity, error)
+	GetAPIVersionFunc    func(ctx context.Context) (*seclai.ApiVersionResponse, error)
+	UpdateAPIVersionFunc func(ctx context.Context, version *string) (*seclai.ApiVersionResponse, error)
+}
+
+var _ seclai.AccountAPI = (*AccountAPI)(nil)
+
+func (m *AccountAPI) GetMe(ctx context.Context) (*seclai.MeResponse, error) {
+	if m.GetMeFunc == nil {
+		panic("seclaimock: AccountAPI.GetMe called with a nil GetMeFunc")
+	}
+	return m.GetMeFunc(ctx)
+}
+
+func (m *AccountAPI) Identity(ctx context.Context) (*seclai.Identity, error) {
+	if m.IdentityFunc == nil {
+		panic("seclaimock: AccountAPI.Identity called with a nil IdentityFunc")
+	}
+	return m.IdentityFunc(ctx)
+}
+
+func (m *AccountAPI) GetAPIVersion(ctx context.Context) (*seclai.ApiVersionResponse, error) {
+	if m.GetAPIVersionFunc == nil {
+		panic("seclaimock: AccountAPI.GetAPIVersion called with a nil GetAPIVersionFunc")
+	}
+	return m.GetAPIVersionFunc(ctx)
+}
+
+func (m *AccountAPI) UpdateAPIVersion(ctx context.Context, version *string) (*seclai.ApiVersionResponse, error) {
+	if m.UpdateAPIVersionFunc == nil {
+		panic("seclaimock: AccountAPI.UpdateAPIVersion called with a nil UpdateAPIVersionFunc")
+	}
+	return m.UpdateAPIVersionFunc(ctx, version)
+}
+
+// AgentsAPI is a mock of [seclai.AgentsAPI].
+type AgentsAPI struct {
+	ListAgentsFunc                               func(ctx context.Context, opts seclai.ListOptions) (*seclai.AgentListResponse, error)
+	AllAgentsFunc                                func(ctx context.Context, opts seclai.ListOptions) iter.Seq2[seclai.AgentSummaryResponse, error]
+	CreateAgentFunc                              func(ctx context.Context, body seclai.CreateAgentRequest) (*seclai.AgentSummaryResponse, error)
+	GetAgentFunc                                 func(ctx context.Context, agentID string) (*seclai.AgentSummaryResponse, error)
+	UpdateAgentFunc                              func(ctx context.Context, agentID string, body seclai.UpdateAgentRequest) (*seclai.AgentSummaryResponse, error)
+	DeleteAgentFunc                              func(ctx context.Context, agentID string) error
+	DisableAgentFunc                             func(ctx context.Context, agentID string) (*seclai.AgentSummaryResponse, error)
+	EnableAgentFunc                              func(ctx context.Context, agentID string) (*seclai.AgentSummaryResponse, error)
+	GetAgentCallersFunc                          func(ctx context.Context, agentID string) ([]seclai.AgentCallerApiResponse, error)
+	ExportAgentFunc                              func(ctx context.Context, agentID string, download bool) (*seclai.AgentExportResponse, error)
+	PreviewImportAgentFunc                       func(ctx context.Context, body seclai.AgentImportPreviewRequest) (*seclai.AgentImportPreviewResponse, error)
+	GetAgentDefinitionFunc                       func(ctx context.Context, agentID string) (*seclai.AgentDefinitionResponse, error)
+	UpdateAgentDefinitionFunc                    func(ctx context.Context, agentID string, body seclai.UpdateAgentDefinitionRequest) (*seclai.AgentDefinitionResponse, error)
+	GenerateAgentStepsFunc                       func(ctx context.Context, agentID string, body seclai.GenerateAgentStepsRequest) (*seclai.GenerateAgentStepsResponse, error)
+	GenerateStepConfigFunc                       func(ctx context.Context, agentID string, body seclai.GenerateStepConfigRequest) (*seclai.GenerateStepConfigResponse, error)
+	GetAgentAiConversationHistoryFunc            func(ctx context.Context, agentID string) (*seclai.AiConversationHistoryResponse, error)
+	GetAgentAiConversationHistoryWithOptionsFunc func(ctx context.Context, agentID string, opts seclai.AiConversationHistoryOptions) (*seclai.AiConversationHistoryResponse, error)
+	MarkAgentAiSuggestionFunc                    func(ctx context.Context, agentID, conversationID string, body seclai.MarkAiSuggestionRequest) error
+}
+
+var _ seclai.AgentsAPI = (*AgentsAPI)(nil)
+
+func (m *AgentsAPI) ListAgents(ctx context.Context, opts seclai.ListOptions) (*seclai.AgentListResponse, error) {
+	if m.ListAgentsFunc == nil {
+		panic("seclaimock: AgentsAPI.ListAgents called with a nil ListAgentsFunc")
+	}
+	return m.ListAgentsFunc(ctx, opts)
+}
+
+func (m *AgentsAPI) AllAgents(ctx context.Context, opts seclai.ListOptions) iter.Seq2[seclai.AgentSummaryResponse, error] {
+	if m.AllAgentsFunc == nil {
+		panic("seclaimock: AgentsAPI.AllAgents called with a nil AllAgentsFunc")
+	}
+	return m.AllAgentsFunc(ctx, opts)
+}
+
+func (m *AgentsAPI) CreateAgent(ctx context.Context, body seclai.CreateAgentRequest) (*seclai.AgentSummaryResponse, error) {
+	if m.CreateAgentFunc == nil {
+		panic("seclaimock: AgentsAPI.CreateAgent called with a nil CreateAgentFunc")
+	}
+	return m.CreateAgentFunc(ctx, body)
+}
+
+func (m *AgentsAPI) GetAgent(ctx context.Context, agentID string) (*seclai.AgentSummaryResponse, error) {
+	if m.GetAgentFunc == nil {
+		panic("seclaimock: AgentsAPI.GetAgent called with a nil GetAgentFunc")
+	}
+	return m.GetAgentFunc(ctx, agentID)
+}
+
+func (m *AgentsAPI) UpdateAgent(ctx context.Context, agentID string, body seclai.UpdateAgentRequest) (*seclai.AgentSummaryResponse, error) {
+	if m.UpdateAgentFunc == nil {
+		panic("seclaimock: AgentsAPI.UpdateAgent called with a nil UpdateAgentFunc")
+	}
+	return m.UpdateAgentFunc(ctx, agentID, body)
+}
+
+func (m *AgentsAPI) DeleteAgent(ctx context.Context, agentID string) error {
+	if m.DeleteAgentFunc == nil {
+		panic("seclaimock: AgentsAPI.DeleteAgent called with a nil DeleteAgentFunc")
+	}
+	return m.DeleteAgentFunc(ctx, agentID)
+}
+
+func (m *AgentsAPI) DisableAgent(ctx context.Context, agentID string) (*seclai.AgentSummaryResponse, error) {
+	if m.DisableAgentFunc == nil {
+		panic("seclaimock: AgentsAPI.DisableAgent called with a nil DisableAgentFunc")
+	}
+	return m.DisableAgentFunc(ctx, agentID)
+}
+
+func (m *AgentsAPI) EnableAgent(ctx context.Context, agentID string) (*seclai.AgentSummaryResponse, error) {
+	if m.EnableAgentFunc == nil {
+		panic("seclaimock: AgentsAPI.EnableAgent called with a nil EnableAgentFunc")
+	}
+	return m.EnableAgentFunc(ctx, agentID)
+}
+
+func (m *AgentsAPI) GetAgentCallers(ctx context.Context, agentID string) ([]seclai.AgentCallerApiResponse, error) {
+	if m.GetAgentCallersFunc == nil {
+		panic("seclaimock: AgentsAPI.GetAgentCallers called with a nil GetAgentCallersFunc")
+	}
+	return m.GetAgentCallersFunc(ctx, agentID)
+}
+
+func (m *AgentsAPI) ExportAgent(ctx context.Context, agentID string, download bool) (*seclai.AgentExportResponse, error) {
+	if m.ExportAgentFunc == nil {
+		panic("seclaimock: AgentsAPI.ExportAgent called with a nil ExportAgentFunc")
+	}
+	return m.ExportAgentFunc(ctx, agentID, download)
+}
+
+func (m *AgentsAPI) PreviewImportAgent(ctx context.Context, body seclai.AgentImportPreviewRequest) (*seclai.AgentImportPreviewResponse, error) {
+	if m.PreviewImportAgentFunc == nil {
+		panic("seclaimock: AgentsAPI.PreviewImportAgent called with a nil PreviewImportAgentFunc")
+	}
+	return m.PreviewImportAgentFunc(ctx, body)
+}
+
+func (m *AgentsAPI) GetAgentDefinition(ctx context.Context, agentID string) (*seclai.AgentDefinitionResponse, error) {
+	if m.GetAgentDefinitionFunc == nil {
+		panic("seclaimock: AgentsAPI.GetAgentDefinition called with a nil GetAgentDefinitionFunc")
+	}
+	return m.GetAgentDefinitionFunc(ctx, agentID)
+}
+
+func (m *AgentsAPI) UpdateAgentDefinition(ctx context.Context, agentID string, body seclai.UpdateAgentDefinitionRequest) (*seclai.AgentDefinitionResponse, error) {
+	if m.UpdateAgentDefinitionFunc == nil {
+		panic("seclaimock: AgentsAPI.UpdateAgentDefinition called with a nil UpdateAgentDefinitionFunc")
+	}
+	return m.UpdateAgentDefinitionFunc(ctx, agentID, body)
+}
+
+func (m *AgentsAPI) GenerateAgentSteps(ctx context.Context, agentID string, body seclai.GenerateAgentStepsRequest) (*seclai.GenerateAgentStepsResponse, error) {
+	if m.GenerateAgentStepsFunc == nil {
+		panic("seclaimock: AgentsAPI.GenerateAgentSteps called with a nil GenerateAgentStepsFunc")
+	}
+	return m.GenerateAgentStepsFunc(ctx, agentID, body)
+}
+
+func (m *AgentsAPI) GenerateStepConfig(ctx context.Context, agentID string, body seclai.GenerateStepConfigRequest) (*seclai.GenerateStepConfigResponse, error) {
+	if m.GenerateStepConfigFunc == nil {
+		panic("seclaimock: AgentsAPI.GenerateStepConfig called with a nil GenerateStepConfigFunc")
+	}
+	return m.GenerateStepConfigFunc(ctx, agentID, body)
+}
+
+func (m *AgentsAPI) GetAgentAiConversationHistory(ctx context.Context, agentID string) (*seclai.AiConversationHistoryResponse, error) {
+	if m.GetAgentAiConversationHistoryFunc == nil {
+		panic("seclaimock: AgentsAPI.GetAgentAiConversationHistory called with a nil GetAgentAiConversationHistoryFunc")
+	}
+	return m.GetAgentAiConversationHistoryFunc(ctx, agentID)
+}
+
+func (m *AgentsAPI) GetAgentAiConversationHistoryWithOptions(ctx context.Context, agentID string, opts seclai.AiConversationHistoryOptions) (*seclai.AiConversationHistoryResponse, error) {
+	if m.GetAgentAiConversationHistoryWithOptionsFunc == nil {
+		panic("seclaimock: AgentsAPI.GetAgentAiConversationHistoryWithOptions called with a nil GetAgentAiConversationHistoryWithOptionsFunc")
+	}
+	return m.GetAgentAiConversationHistoryWithOptionsFunc(ctx, agentID, opts)
+}
+
+func (m *AgentsAPI) MarkAgentAiSuggestion(ctx context.Context, agentID, conversationID string, body seclai.MarkAiSuggestionRequest) error {
+	if m.MarkAgentAiSuggestionFunc == nil {
+		panic("seclaimock: AgentsAPI.MarkAgentAiSuggestion called with a nil MarkAgentAiSuggestionFunc")
+	}
+	return m.MarkAgentAiSuggestionFunc(ctx, agentID, conversationID, body)
+}
+
+// RunsAPI is a mock of [seclai.RunsAPI].
+type RunsAPI struct {
+	RunAgentFunc                     func(ctx context.Context, agentID string, body seclai.AgentRunRequest) (*seclai.AgentRunResponse, error)
+	RunAgentAndPollFunc              func(ctx context.Context, agentID string, body seclai.AgentRunRequest, opts *seclai.RunAgentAndPollOptions) (*seclai.AgentRunResponse, error)
+	RunStreamingAgentFunc            func(ctx context.Context, agentID string, body seclai.AgentRunStreamRequest) (<-chan seclai.AgentRunEvent, <-chan error)
+	RunStreamingAgentAndWaitFunc     func(ctx context.Context, agentID string, body seclai.AgentRunStreamRequest) (*seclai.AgentRunResponse, error)
+	RunStreamingAgentWithHandlerFunc func(ctx context.Context, agentID string, body seclai.AgentRunStreamRequest, h seclai.StreamHandler) error
+	RunStreamingAgentEventsFunc      func(ctx context.Context, agentID string, body seclai.AgentRunStreamRequest) iter.Seq2[seclai.AgentRunEvent, error]
+	ListAgentRunsFunc                func(ctx context.Context, agentID string, opts seclai.ListAgentRunsOptions) (*seclai.AgentRunListResponse, error)
+	AllAgentRunsFunc                 func(ctx context.Context, agentID string, opts seclai.ListAgentRunsOptions) iter.Seq2[seclai.AgentRunResponse, error]
+	SearchAgentRunsFunc              func(ctx context.Context, body seclai.AgentTraceSearchRequest) (*seclai.AgentTraceSearchResponse, error)
+	GetAgentRunFunc                  func(ctx context.Context, runID string, opts *seclai.GetAgentRunOptions) (*seclai.AgentRunResponse, error)
+	DeleteAgentRunFunc               func(ctx context.Context, runID string) error
+	CancelAgentRunFunc               func(ctx context.Context, runID string) (*seclai.AgentRunResponse, error)
+	UploadAgentInputFunc             func(ctx context.Context, agentID string, req seclai.UploadFileRequest) (*seclai.UploadAgentInputApiResponse, error)
+	GetAgentInputUploadStatusFunc    func(ctx context.Context, agentID, uploadID string) (*seclai.UploadAgentInputApiResponse, error)
+	GetAgentAttachmentReferencesFunc func(ctx context.Context, agentID string) (*seclai.AgentAttachmentRefsApiResponse, error)
+	DownloadAgentRunAttachmentFunc   func(ctx context.Context, runID, attachmentID, downloadName string) (*http.Response, error)
+}
+
+var _ seclai.RunsAPI = (*RunsAPI)(nil)
+
+func (m *RunsAPI) RunAgent(ctx context.Context, agentID string, body seclai.AgentRunRequest) (*seclai.AgentRunResponse, error) {
+	if m.RunAgentFunc == nil {
+		panic("seclaimock: RunsAPI.RunAgent called with a nil RunAgentFunc")
+	}
+	return m.RunAgentFunc(ctx, agentID, body)
+}
+
+func (m *RunsAPI) RunAgentAndPoll(ctx context.Context, agentID string, body seclai.AgentRunRequest, opts *seclai.RunAgentAndPollOptions) (*seclai.AgentRunResponse, error) {
+	if m.RunAgentAndPollFunc == nil {
+		panic("seclaimock: RunsAPI.RunAgentAndPoll called with a nil RunAgentAndPollFunc")
+	}
+	return m.RunAgentAndPollFunc(ctx, agentID, body, opts)
+}
+
+func (m *RunsAPI) RunStreamingAgent(ctx context.Context, agentID string, body seclai.AgentRunStreamRequest) (<-chan seclai.AgentRunEvent, <-chan error) {
+	if m.RunStreamingAgentFunc == nil {
+		panic("seclaimock: RunsAPI.RunStreamingAgent called with a nil RunStreamingAgentFunc")
+	}
+	return m.RunStreamingAgentFunc(ctx, agentID, body)
+}
+
+func (m *RunsAPI) RunStreamingAgentAndWait(ctx context.Context, agentID string, body seclai.AgentRunStreamRequest) (*seclai.AgentRunResponse, error) {
+	if m.RunStreamingAgentAndWaitFunc == nil {
+		panic("seclaimock: RunsAPI.RunStreamingAgentAndWait called with a nil RunStreamingAgentAndWaitFunc")
+	}
+	return m.RunStreamingAgentAndWaitFunc(ctx, agentID, body)
+}
+
+func (m *RunsAPI) RunStreamingAgentWithHandler(ctx context.Context, agentID string, body seclai.AgentRunStreamRequest, h seclai.StreamHandler) error {
+	if m.RunStreamingAgentWithHandlerFunc == nil {
+		panic("seclaimock: RunsAPI.RunStreamingAgentWithHandler called with a nil RunStreamingAgentWithHandlerFunc")
+	}
+	return m.RunStreamingAgentWithHandlerFunc(ctx, agentID, body, h)
+}
+
+func (m *RunsAPI) RunStreamingAgentEvents(ctx context.Context, agentID string, body seclai.AgentRunStreamRequest) iter.Seq2[seclai.AgentRunEvent, error] {
+	if m.RunStreamingAgentEventsFunc == nil {
+		panic("seclaimock: RunsAPI.RunStreamingAgentEvents called with a nil RunStreamingAgentEventsFunc")
+	}
+	return m.RunStreamingAgentEventsFunc(ctx, agentID, body)
+}
+
+func (m *RunsAPI) ListAgentRuns(ctx context.Context, agentID string, opts seclai.ListAgentRunsOptions) (*seclai.AgentRunListResponse, error) {
+	if m.ListAgentRunsFunc == nil {
+		panic("seclaimock: RunsAPI.ListAgentRuns called with a nil ListAgentRunsFunc")
+	}
+	return m.ListAgentRunsFunc(ctx, agentID, opts)
+}
+
+func (m *RunsAPI) AllAgentRuns(ctx context.Context, agentID string, opts seclai.ListAgentRunsOptions) iter.Seq2[seclai.AgentRunResponse, error] {
+	if m.AllAgentRunsFunc == nil {
+		panic("seclaimock: RunsAPI.AllAgentRuns called with a nil AllAgentRunsFunc")
+	}
+	return m.AllAgentRunsFunc(ctx, agentID, opts)
+}
+
+func (m *RunsAPI) SearchAgentRuns(ctx context.Context, body seclai.AgentTraceSearchRequest) (*seclai.AgentTraceSearchResponse, error) {
+	if m.SearchAgentRunsFunc == nil {
+		panic("seclaimock: RunsAPI.SearchAgentRuns called with a nil SearchAgentRunsFunc")
+	}
+	return m.SearchAgentRunsFunc(ctx, body)
+}
+
+func (m *RunsAPI) GetAgentRun(ctx context.Context, runID string, opts *seclai.GetAgentRunOptions) (*seclai.AgentRunResponse, error) {
+	if m.GetAgentRunFunc == nil {
+		panic("seclaimock: RunsAPI.GetAgentRun called with a nil GetAgentRunFunc")
+	}
+	return m.GetAgentRunFunc(ctx, runID, opts)
+}
+
+func (m *RunsAPI) DeleteAgentRun(ctx context.Context, runID string) error {
+	if m.DeleteAgentRunFunc == nil {
+		panic("seclaimock: RunsAPI.DeleteAgentRun called with a nil DeleteAgentRunFunc")
+	}
+	return m.DeleteAgentRunFunc(ctx, runID)
+}
+
+func (m *RunsAPI) CancelAgentRun(ctx context.Context, runID string) (*seclai.AgentRunResponse, error) {
+	if m.CancelAgentRunFunc == nil {
+		panic("seclaimock: RunsAPI.CancelAgentRun called with a nil CancelAgentRunFunc")
+	}
+	return m.CancelAgentRunFunc(ctx, runID)
+}
+
+func (m *RunsAPI) UploadAgentInput(ctx context.Context, agentID string, req seclai.UploadFileRequest) (*seclai.UploadAgentInputApiResponse, error) {
+	if m.UploadAgentInputFunc == nil {
+		panic("seclaimock: RunsAPI.UploadAgentInput called with a nil UploadAgentInputFunc")
+	}
+	return m.UploadAgentInputFunc(ctx, agentID, req)
+}
+
+func (m *RunsAPI) GetAgentInputUploadStatus(ctx context.Context, agentID, uploadID string) (*seclai.UploadAgentInputApiResponse, error) {
+	if m.GetAgentInputUploadStatusFunc == nil {
+		panic("seclaimock: RunsAPI.GetAgentInputUploadStatus called with a nil GetAgentInputUploadStatusFunc")
+	}
+	return m.GetAgentInputUploadStatusFunc(ctx, agentID, uploadID)
+}
+
+func (m *RunsAPI) GetAgentAttachmentReferences(ctx context.Context, agentID string) (*seclai.AgentAttachmentRefsApiResponse, error) {
+	if m.GetAgentAttachmentReferencesFunc == nil {
+		panic("seclaimock: RunsAPI.GetAgentAttachmentReferences called with a nil GetAgentAttachmentReferencesFunc")
+	}
+	return m.GetAgentAttachmentReferencesFunc(ctx, agentID)
+}
+
+func (m *RunsAPI) DownloadAgentRunAttachment(ctx context.Context, runID, attachmentID, downloadName string) (*http.Response, error) {
+	if m.DownloadAgentRunAttachmentFunc == nil {
+		panic("seclaimock: RunsAPI.DownloadAgentRunAttachment called with a nil DownloadAgentRunAttachmentFunc")
+	}
+	return m.DownloadAgentRunAttachmentFunc(ctx, runID, attachmentID, downloadName)
+}
+
+// EvaluationsAPI is a mock of [seclai.EvaluationsAPI].
+type EvaluationsAPI struct {
+	ListEvaluationCriteriaFunc        func(ctx context.Context, agentID string, opts seclai.ListOptions) ([]seclai.EvaluationCriteriaResponse, error)
+	ListEvaluationCriteriaPageFunc    func(ctx context.Context, agentID string, opts seclai.ListOptions) (*seclai.EvaluationCriteriaListResponse, error)
+	AllEvaluationCriteriaFunc         func(ctx context.Context, agentID string, opts seclai.ListOptions) iter.Seq2[seclai.EvaluationCriteriaResponse, error]
+	CreateEvaluationCriteriaFunc      func(ctx context.Context, agentID string, body seclai.CreateEvaluationCriteriaRequest) (*seclai.EvaluationCriteriaResponse, error)
+	GetEvaluationCriteriaFunc         func(ctx context.Context, criteriaID string) (*seclai.EvaluationCriteriaResponse, error)
+	UpdateEvaluationCriteriaFunc      func(ctx context.Context, criteriaID string, body seclai.UpdateEvaluationCriteriaRequest) (*seclai.EvaluationCriteriaResponse, error)
+	DeleteEvaluationCriteriaFunc      func(ctx context.Context, criteriaID string) error
+	GetEvaluationCriteriaSummaryFunc  func(ctx context.Context, criteriaID string) (*seclai.EvaluationResultSummaryResponse, error)
+	ListEvaluationResultsFunc         func(ctx context.Context, criteriaID string, opts seclai.ListOptions) (*seclai.EvaluationResultListResponse, error)
+	AllEvaluationResultsFunc          func(ctx context.Context, criteriaID string, opts seclai.ListOptions) iter.Seq2[seclai.EvaluationResultResponse, error]
+	CreateEvaluationResultFunc        func(ctx context.Context, criteriaID string, body seclai.CreateEvaluationResultRequest) (*seclai.EvaluationResultResponse, error)
+	ListCompatibleRunsFunc            func(ctx context.Context, criteriaID string, opts seclai.ListOptions) (*seclai.CompatibleRunListResponse, error)
+	AllCompatibleRunsFunc             func(ctx context.Context, criteriaID string, opts seclai.ListOptions) iter.Seq2[seclai.CompatibleRunResponse, error]
+	TestDraftEvaluationFunc           func(ctx context.Context, agentID string, body seclai.TestDraftEvaluationRequest) (*seclai.TestDraftEvaluationResponse, error)
+	ListAgentEvaluationResultsFunc    func(ctx context.Context, agentID string, opts seclai.ListOptions) (*seclai.EvaluationResultWithCriteriaListResponse, error)
+	AllAgentEvaluationResultsFunc     func(ctx context.Context, agentID string, opts seclai.ListOptions) iter.Seq2[seclai.EvaluationResultWithCriteriaResponse, error]
+	ListRunEvaluationResultsFunc      func(ctx context.Context, agentID, runID string, opts seclai.ListOptions) (*seclai.EvaluationResultWithCriteriaListResponse, error)
+	AllRunEvaluationResultsFunc       func(ctx context.Context, agentID, runID string, opts seclai.ListOptions) iter.Seq2[seclai.EvaluationResultWithCriteriaResponse, error]
+	ListEvaluationRunsFunc            func(ctx context.Context, agentID string, opts seclai.ListOptions) (*seclai.EvaluationRunSummaryListResponse, error)
+	AllEvaluationRunsFunc             func(ctx context.Context, agentID string, opts seclai.ListOptions) iter.Seq2[seclai.EvaluationRunSummaryResponse, error]
+	GetNonManualEvaluationSummaryFunc func(ctx context.Context, agentID string) (*seclai.NonManualEvaluationSummaryResponse, error)
+}
+
+var _ seclai.EvaluationsAPI = (*EvaluationsAPI)(nil)
+
+func (m *EvaluationsAPI) ListEvaluationCriteria(ctx context.Context, agentID string, opts seclai.ListOptions) ([]seclai.EvaluationCriteriaResponse, error) {
+	if m.ListEvaluationCriteriaFunc == nil {
+		panic("seclaimock: EvaluationsAPI.ListEvaluationCriteria called with a nil ListEvaluationCriteriaFunc")
+	}
+	return m.ListEvaluationCriteriaFunc(ctx, agentID, opts)
+}
+
+func (m *EvaluationsAPI) ListEvaluationCriteriaPage(ctx context.Context, agentID string, opts seclai.ListOptions) (*seclai.EvaluationCriteriaListResponse, error) {
+	if m.ListEvaluationCriteriaPageFunc == nil {
+		panic("seclaimock: EvaluationsAPI.ListEvaluationCriteriaPage called with a nil ListEvaluationCriteriaPageFunc")
+	}
+	return m.ListEvaluationCriteriaPageFunc(ctx, agentID, opts)
+}
+
+func (m *EvaluationsAPI) AllEvaluationCriteria(ctx context.Context, agentID string, opts seclai.ListOptions) iter.Seq2[seclai.EvaluationCriteriaResponse, error] {
+	if m.AllEvaluationCriteriaFunc == nil {
+		panic("seclaimock: EvaluationsAPI.AllEvaluationCriteria called with a nil AllEvaluationCriteriaFunc")
+	}
+	return m.AllEvaluationCriteriaFunc(ctx, agentID, opts)
+}
+
+func (m *EvaluationsAPI) CreateEvaluationCriteria(ctx context.Context, agentID string, body seclai.CreateEvaluationCriteriaRequest) (*seclai.EvaluationCriteriaResponse, error) {
+	if m.CreateEvaluationCriteriaFunc == nil {
+		panic("seclaimock: EvaluationsAPI.CreateEvaluationCriteria called with a nil CreateEvaluationCriteriaFunc")
+	}
+	return m.CreateEvaluationCriteriaFunc(ctx, agentID, body)
+}
+
+func (m *EvaluationsAPI) GetEvaluationCriteria(ctx context.Context, criteriaID string) (*seclai.EvaluationCriteriaResponse, error) {
+	if m.GetEvaluationCriteriaFunc == nil {
+		panic("seclaimock: EvaluationsAPI.GetEvaluationCriteria called with a nil GetEvaluationCriteriaFunc")
+	}
+	return m.GetEvaluationCriteriaFunc(ctx, criteriaID)
+}
+
+func (m *EvaluationsAPI) UpdateEvaluationCriteria(ctx context.Context, criteriaID string, body seclai.UpdateEvaluationCriteriaRequest) (*seclai.EvaluationCriteriaResponse, error) {
+	if m.UpdateEvaluationCriteriaFunc == nil {
+		panic("seclaimock: EvaluationsAPI.UpdateEvaluationCriteria called with a nil UpdateEvaluationCriteriaFunc")
+	}
+	return m.UpdateEvaluationCriteriaFunc(ctx, criteriaID, body)
+}
+
+func (m *EvaluationsAPI) DeleteEvaluationCriteria(ctx context.Context, criteriaID string) error {
+	if m.DeleteEvaluationCriteriaFunc == nil {
+		panic("seclaimock: EvaluationsAPI.DeleteEvaluationCriteria called with a nil DeleteEvaluationCriteriaFunc")
+	}
+	return m.DeleteEvaluationCriteriaFunc(ctx, criteriaID)
+}
+
+func (m *EvaluationsAPI) GetEvaluationCriteriaSummary(ctx context.Context, criteriaID string) (*seclai.EvaluationResultSummaryResponse, error) {
+	if m.GetEvaluationCriteriaSummaryFunc == nil {
+		panic("seclaimock: EvaluationsAPI.GetEvaluationCriteriaSummary called with a nil GetEvaluationCriteriaSummaryFunc")
+	}
+	return m.GetEvaluationCriteriaSummaryFunc(ctx, criteriaID)
+}
+
+func (m *EvaluationsAPI) ListEvaluationResults(ctx context.Context, criteriaID string, opts seclai.ListOptions) (*seclai.EvaluationResultListResponse, error) {
+	if m.ListEvaluationResultsFunc == nil {
+		panic("seclaimock: EvaluationsAPI.ListEvaluationResults called with a nil ListEvaluationResultsFunc")
+	}
+	return m.ListEvaluationResultsFunc(ctx, criteriaID, opts)
+}
+
+func (m *EvaluationsAPI) AllEvaluationResults(ctx context.Context, criteriaID string, opts seclai.ListOptions) iter.Seq2[seclai.EvaluationResultResponse, error] {
+	if m.AllEvaluationResultsFunc == nil {
+		panic("seclaimock: EvaluationsAPI.AllEvaluationResults called with a nil AllEvaluationResultsFunc")
+	}
+	return m.AllEvaluationResultsFunc(ctx, criteriaID, opts)
+}
+
+func (m *EvaluationsAPI) CreateEvaluationResult(ctx context.Context, criteriaID string, body seclai.CreateEvaluationResultRequest) (*seclai.EvaluationResultResponse, error) {
+	if m.CreateEvaluationResultFunc == nil {
+		panic("seclaimock: EvaluationsAPI.CreateEvaluationResult called with a nil CreateEvaluationResultFunc")
+	}
+	return m.CreateEvaluationResultFunc(ctx, criteriaID, body)
+}
+
+func (m *EvaluationsAPI) ListCompatibleRuns(ctx context.Context, criteriaID string, opts seclai.ListOptions) (*seclai.CompatibleRunListResponse, error) {
+	if m.ListCompatibleRunsFunc == nil {
+		panic("seclaimock: EvaluationsAPI.ListCompatibleRuns called with a nil ListCompatibleRunsFunc")
+	}
+	return m.ListCompatibleRunsFunc(ctx, criteriaID, opts)
+}
+
+func (m *EvaluationsAPI) AllCompatibleRuns(ctx context.Context, criteriaID string, opts seclai.ListOptions) iter.Seq2[seclai.CompatibleRunResponse, error] {
+	if m.AllCompatibleRunsFunc == nil {
+		panic("seclaimock: EvaluationsAPI.AllCompatibleRuns called with a nil AllCompatibleRunsFunc")
+	}
+	return m.AllCompatibleRunsFunc(ctx, criteriaID, opts)
+}
+
+func (m *EvaluationsAPI) TestDraftEvaluation(ctx context.Context, agentID string, body seclai.TestDraftEvaluationRequest) (*seclai.TestDraftEvaluationResponse, error) {
+	if m.TestDraftEvaluationFunc == nil {
+		panic("seclaimock: EvaluationsAPI.TestDraftEvaluation called with a nil TestDraftEvaluationFunc")
+	}
+	return m.TestDraftEvaluationFunc(ctx, agentID, body)
+}
+
+func (m *EvaluationsAPI) ListAgentEvaluationResults(ctx context.Context, agentID string, opts seclai.ListOptions) (*seclai.EvaluationResultWithCriteriaListResponse, error) {
+	if m.ListAgentEvaluationResultsFunc == nil {
+		panic("seclaimock: EvaluationsAPI.ListAgentEvaluationResults called with a nil ListAgentEvaluationResultsFunc")
+	}
+	return m.ListAgentEvaluationResultsFunc(ctx, agentID, opts)
+}
+
+func (m *EvaluationsAPI) AllAgentEvaluationResults(ctx context.Context, agentID string, opts seclai.ListOptions) iter.Seq2[seclai.EvaluationResultWithCriteriaResponse, error] {
+	if m.AllAgentEvaluationResultsFunc == nil {
+		panic("seclaimock: EvaluationsAPI.AllAgentEvaluationResults called with a nil AllAgentEvaluationResultsFunc")
+	}
+	return m.AllAgentEvaluationResultsFunc(ctx, agentID, opts)
+}
+
+func (m *EvaluationsAPI) ListRunEvaluationResults(ctx context.Context, agentID, runID string, opts seclai.ListOptions) (*seclai.EvaluationResultWithCriteriaListResponse, error) {
+	if m.ListRunEvaluationResultsFunc == nil {
+		panic("seclaimock: EvaluationsAPI.ListRunEvaluationResults called with a nil ListRunEvaluationResultsFunc")
+	}
+	return m.ListRunEvaluationResultsFunc(ctx, agentID, runID, opts)
+}
+
+func (m *EvaluationsAPI) AllRunEvaluationResults(ctx context.Context, agentID, runID string, opts seclai.ListOptions) iter.Seq2[seclai.EvaluationResultWithCriteriaResponse, error] {
+	if m.AllRunEvaluationResultsFunc == nil {
+		panic("seclaimock: EvaluationsAPI.AllRunEvaluationResults called with a nil AllRunEvaluationResultsFunc")
+	}
+	return m.AllRunEvaluationResultsFunc(ctx, agentID, runID, opts)
+}
+
+func (m *EvaluationsAPI) ListEvaluationRuns(ctx context.Context, agentID string, opts seclai.ListOptions) (*seclai.EvaluationRunSummaryListResponse, error) {
+	if m.ListEvaluationRunsFunc == nil {
+		panic("seclaimock: EvaluationsAPI.ListEvaluationRuns called with a nil ListEvaluationRunsFunc")
+	}
+	return m.ListEvaluationRunsFunc(ctx, agentID, opts)
+}
+
+func (m *EvaluationsAPI) AllEvaluationRuns(ctx context.Context, agentID string, opts seclai.ListOptions) iter.Seq2[seclai.EvaluationRunSummaryResponse, error] {
+	if m.AllEvaluationRunsFunc == nil {
+		panic("seclaimock: EvaluationsAPI.AllEvaluationRuns called with a nil AllEvaluationRunsFunc")
+	}
+	return m.AllEvaluationRunsFunc(ctx, agentID, opts)
+}
+
+func (m *EvaluationsAPI) GetNonManualEvaluationSummary(ctx context.Context, agentID string) (*seclai.NonManualEvaluationSummaryResponse, error) {
+	if m.GetNonManualEvaluationSummaryFunc == nil {
+		panic("seclaimock: EvaluationsAPI.GetNonManualEvaluationSummary called with a nil GetNonManualEvaluationSummaryFunc")
+	}
+	return m.GetNonManualEvaluationSummaryFunc(ctx, agentID)
+}
+
+// KnowledgeBasesAPI is a mock of [seclai.KnowledgeBasesAPI].
+type KnowledgeBasesAPI struct {
+	ListKnowledgeBasesFunc  func(ctx context.Context, opts seclai.SortableListOptions) (*seclai.KnowledgeBaseListResponse, error)
+	AllKnowledgeBasesFunc   func(ctx context.Context, opts seclai.SortableListOptions) iter.Seq2[seclai.KnowledgeBaseResponse, error]
+	CreateKnowledgeBaseFunc func(ctx context.Context, body seclai.CreateKnowledgeBaseBody) (*seclai.KnowledgeBaseResponse, error)
+	GetKnowledgeBaseFunc    func(ctx context.Context, knowledgeBaseID string) (*seclai.KnowledgeBaseResponse, error)
+	UpdateKnowledgeBaseFunc func(ctx context.Context, knowledgeBaseID string, body seclai.UpdateKnowledgeBaseBody) (*seclai.KnowledgeBaseResponse, error)
+	DeleteKnowledgeBaseFunc func(ctx context.Context, knowledgeBaseID string) error
+}
+
+var _ seclai.KnowledgeBasesAPI = (*KnowledgeBasesAPI)(nil)
+
+func (m *KnowledgeBasesAPI) ListKnowledgeBases(ctx context.Context, opts seclai.SortableListOptions) (*seclai.KnowledgeBaseListResponse, error) {
+	if m.ListKnowledgeBasesFunc == nil {
+		panic("seclaimock: KnowledgeBasesAPI.ListKnowledgeBases called with a nil ListKnowledgeBasesFunc")
+	}
+	return m.ListKnowledgeBasesFunc(ctx, opts)
+}
+
+func (m *KnowledgeBasesAPI) AllKnowledgeBases(ctx context.Context, opts seclai.SortableListOptions) iter.Seq2[seclai.KnowledgeBaseResponse, error] {
+	if m.AllKnowledgeBasesFunc == nil {
+		panic("seclaimock: KnowledgeBasesAPI.AllKnowledgeBases called with a nil AllKnowledgeBasesFunc")
+	}
+	return m.AllKnowledgeBasesFunc(ctx, opts)
+}
+
+func (m *KnowledgeBasesAPI) CreateKnowledgeBase(ctx context.Context, body seclai.CreateKnowledgeBaseBody) (*seclai.KnowledgeBaseResponse, error) {
+	if m.CreateKnowledgeBaseFunc == nil {
+		panic("seclaimock: KnowledgeBasesAPI.CreateKnowledgeBase called with a nil CreateKnowledgeBaseFunc")
+	}
+	return m.CreateKnowledgeBaseFunc(ctx, body)
+}
+
+func (m *KnowledgeBasesAPI) GetKnowledgeBase(ctx context.Context, knowledgeBaseID string) (*seclai.KnowledgeBaseResponse, error) {
+	if m.GetKnowledgeBaseFunc == nil {
+		panic("seclaimock: KnowledgeBasesAPI.GetKnowledgeBase called with a nil GetKnowledgeBaseFunc")
+	}
+	return m.GetKnowledgeBaseFunc(ctx, knowledgeBaseID)
+}
+
+func (m *KnowledgeBasesAPI) UpdateKnowledgeBase(ctx context.Context, knowledgeBaseID string, body seclai.UpdateKnowledgeBaseBody) (*seclai.KnowledgeBaseResponse, error) {
+	if m.UpdateKnowledgeBaseFunc == nil {
+		panic("seclaimock: KnowledgeBasesAPI.UpdateKnowledgeBase called with a nil UpdateKnowledgeBaseFunc")
+	}
+	return m.UpdateKnowledgeBaseFunc(ctx, knowledgeBaseID, body)
+}
+
+func (m *KnowledgeBasesAPI) DeleteKnowledgeBase(ctx context.Context, knowledgeBaseID string) error {
+	if m.DeleteKnowledgeBaseFunc == nil {
+		panic("seclaimock: KnowledgeBasesAPI.DeleteKnowledgeBase called with a nil DeleteKnowledgeBaseFunc")
+	}
+	return m.DeleteKnowledgeBaseFunc(ctx, knowledgeBaseID)
+}
+
+// MemoryBanksAPI is a mock of [seclai.MemoryBanksAPI].
+type MemoryBanksAPI struct {
+	ListMemoryBanksFunc                 func(ctx context.Context, opts seclai.SortableListOptions) (*seclai.MemoryBankListResponse, error)
+	AllMemoryBanksFunc                  func(ctx context.Context, opts seclai.SortableListOptions) iter.Seq2[seclai.MemoryBankResponse, error]
+	CreateMemoryBankFunc                func(ctx context.Context, body seclai.CreateMemoryBankBody) (*seclai.MemoryBankResponse, error)
+	GetMemoryBankFunc                   func(ctx context.Context, memoryBankID string) (*seclai.MemoryBankResponse, error)
+	UpdateMemoryBankFunc                func(ctx context.Context, memoryBankID string, body seclai.UpdateMemoryBankBody) (*seclai.MemoryBankResponse, error)
+	DeleteMemoryBankFunc                func(ctx context.Context, memoryBankID string) error
+	GetAgentsUsingMemoryBankFunc        func(ctx context.Context, memoryBankID string) (json.RawMessage, error)
+	GetMemoryBankStatsFunc              func(ctx context.Context, memoryBankID string) (json.RawMessage, error)
+	CompactMemoryBankFunc               func(ctx context.Context, memoryBankID string) error
+	DeleteMemoryBankSourceFunc          func(ctx context.Context, memoryBankID string) error
+	TestMemoryBankCompactionFunc        func(ctx context.Context, memoryBankID string, body seclai.TestCompactionRequest) (*seclai.CompactionTestResponse, error)
+	TestCompactionPromptStandaloneFunc  func(ctx context.Context, body seclai.StandaloneTestCompactionRequest) (*seclai.CompactionTestResponse, error)
+	ListMemoryBankTemplatesFunc         func(ctx context.Context) (json.RawMessage, error)
+	GenerateMemoryBankConfigFunc        func(ctx context.Context, body seclai.MemoryBankAiAssistantRequest) (*seclai.MemoryBankAiAssistantResponse, error)
+	GetMemoryBankAiLastConversationFunc func(ctx context.Context) (*seclai.MemoryBankLastConversationResponse, error)
+	AcceptMemoryBankAiSuggestionFunc    func(ctx context.Context, conversationID string, body seclai.MemoryBankAcceptRequest) (json.RawMessage, error)
+}
+
+var _ seclai.MemoryBanksAPI = (*MemoryBanksAPI)(nil)
+
+func (m *MemoryBanksAPI) ListMemoryBanks(ctx context.Context, opts seclai.SortableListOptions) (*seclai.MemoryBankListResponse, error) {
+	if m.ListMemoryBanksFunc == nil {
+		panic("seclaimock: MemoryBanksAPI.ListMemoryBanks called with a nil ListMemoryBanksFunc")
+	}
+	return m.ListMemoryBanksFunc(ctx, opts)
+}
+
+func (m *MemoryBanksAPI) AllMemoryBanks(ctx context.Context, opts seclai.SortableListOptions) iter.Seq2[seclai.MemoryBankResponse, error] {
+	if m.AllMemoryBanksFunc == nil {
+		panic("seclaimock: MemoryBanksAPI.AllMemoryBanks called with a nil AllMemoryBanksFunc")
+	}
+	return m.AllMemoryBanksFunc(ctx, opts)
+}
+
+func (m *MemoryBanksAPI) CreateMemoryBank(ctx context.Context, body seclai.CreateMemoryBankBody) (*seclai.MemoryBankResponse, error) {
+	if m.CreateMemoryBankFunc == nil {
+		panic("seclaimock: MemoryBanksAPI.CreateMemoryBank called with a nil CreateMemoryBankFunc")
+	}
+	return m.CreateMemoryBankFunc(ctx, body)
+}
+
+func (m *MemoryBanksAPI) GetMemoryBank(ctx context.Context, memoryBankID string) (*seclai.MemoryBankResponse, error) {
+	if m.GetMemoryBankFunc == nil {
+		panic("seclaimock: MemoryBanksAPI.GetMemoryBank called with a nil GetMemoryBankFunc")
+	}
+	return m.GetMemoryBankFunc(ctx, memoryBankID)
+}
+
+func (m *MemoryBanksAPI) UpdateMemoryBank(ctx context.Context, memoryBankID string, body seclai.UpdateMemoryBankBody) (*seclai.MemoryBankResponse, error) {
+	if m.UpdateMemoryBankFunc == nil {
+		panic("seclaimock: MemoryBanksAPI.UpdateMemoryBank called with a nil UpdateMemoryBankFunc")
+	}
+	return m.UpdateMemoryBankFunc(ctx, memoryBankID, body)
+}
+
+func (m *MemoryBanksAPI) DeleteMemoryBank(ctx context.Context, memoryBankID string) error {
+	if m.DeleteMemoryBankFunc == nil {
+		panic("seclaimock: MemoryBanksAPI.DeleteMemoryBank called with a nil DeleteMemoryBankFunc")
+	}
+	return m.DeleteMemoryBankFunc(ctx, memoryBankID)
+}
+
+func (m *MemoryBanksAPI) GetAgentsUsingMemoryBank(ctx context.Context, memoryBankID string) (json.RawMessage, error) {
+	if m.GetAgentsUsingMemoryBankFunc == nil {
+		panic("seclaimock: MemoryBanksAPI.GetAgentsUsingMemoryBank called with a nil GetAgentsUsingMemoryBankFunc")
+	}
+	return m.GetAgentsUsingMemoryBankFunc(ctx, memoryBankID)
+}
+
+func (m *MemoryBanksAPI) GetMemoryBankStats(ctx context.Context, memoryBankID string) (json.RawMessage, error) {
+	if m.GetMemoryBankStatsFunc == nil {
+		panic("seclaimock: MemoryBanksAPI.GetMemoryBankStats called with a nil GetMemoryBankStatsFunc")
+	}
+	return m.GetMemoryBankStatsFunc(ctx, memoryBankID)
+}
+
+func (m *MemoryBanksAPI) CompactMemoryBank(ctx context.Context, memoryBankID string) error {
+	if m.CompactMemoryBankFunc == nil {
+		panic("seclaimock: MemoryBanksAPI.CompactMemoryBank called with a nil CompactMemoryBankFunc")
+	}
+	return m.CompactMemoryBankFunc(ctx, memoryBankID)
+}
+
+func (m *MemoryBanksAPI) DeleteMemoryBankSource(ctx context.Context, memoryBankID string) error {
+	if m.DeleteMemoryBankSourceFunc == nil {
+		panic("seclaimock: MemoryBanksAPI.DeleteMemoryBankSource called with a nil DeleteMemoryBankSourceFunc")
+	}
+	return m.DeleteMemoryBankSourceFunc(ctx, memoryBankID)
+}
+
+func (m *MemoryBanksAPI) TestMemoryBankCompaction(ctx context.Context, memoryBankID string, body seclai.TestCompactionRequest) (*seclai.CompactionTestResponse, error) {
+	if m.TestMemoryBankCompactionFunc == nil {
+		panic("seclaimock: MemoryBanksAPI.TestMemoryBankCompaction called with a nil TestMemoryBankCompactionFunc")
+	}
+	return m.TestMemoryBankCompactionFunc(ctx, memoryBankID, body)
+}
+
+func (m *MemoryBanksAPI) TestCompactionPromptStandalone(ctx context.Context, body seclai.StandaloneTestCompactionRequest) (*seclai.CompactionTestResponse, error) {
+	if m.TestCompactionPromptStandaloneFunc == nil {
+		panic("seclaimock: MemoryBanksAPI.TestCompactionPromptStandalone called with a nil TestCompactionPromptStandaloneFunc")
+	}
+	return m.TestCompactionPromptStandaloneFunc(ctx, body)
+}
+
+func (m *MemoryBanksAPI) ListMemoryBankTemplates(ctx context.Context) (json.RawMessage, error) {
+	if m.ListMemoryBankTemplatesFunc == nil {
+		panic("seclaimock: MemoryBanksAPI.ListMemoryBankTemplates called with a nil ListMemoryBankTemplatesFunc")
+	}
+	return m.ListMemoryBankTemplatesFunc(ctx)
+}
+
+func (m *MemoryBanksAPI) GenerateMemoryBankConfig(ctx context.Context, body seclai.MemoryBankAiAssistantRequest) (*seclai.MemoryBankAiAssistantResponse, error) {
+	if m.GenerateMemoryBankConfigFunc == nil {
+		panic("seclaimock: MemoryBanksAPI.GenerateMemoryBankConfig called with a nil GenerateMemoryBankConfigFunc")
+	}
+	return m.GenerateMemoryBankConfigFunc(ctx, body)
+}
+
+func (m *MemoryBanksAPI) GetMemoryBankAiLastConversation(ctx context.Context) (*seclai.MemoryBankLastConversationResponse, error) {
+	if m.GetMemoryBankAiLastConversationFunc == nil {
+		panic("seclaimock: MemoryBanksAPI.GetMemoryBankAiLastConversation called with a nil GetMemoryBankAiLastConversationFunc")
+	}
+	return m.GetMemoryBankAiLastConversationFunc(ctx)
+}
+
+func (m *MemoryBanksAPI) AcceptMemoryBankAiSuggestion(ctx context.Context, conversationID string, body seclai.MemoryBankAcceptRequest) (json.RawMessage, error) {
+	if m.AcceptMemoryBankAiSuggestionFunc == nil {
+		panic("seclaimock: MemoryBanksAPI.AcceptMemoryBankAiSuggestion called with a nil AcceptMemoryBankAiSuggestionFunc")
+	}
+	return m.AcceptMemoryBankAiSuggestionFunc(ctx, conversationID, body)
+}
+
+// SourcesAPI is a mock of [seclai.SourcesAPI].
+type SourcesAPI struct {
+	ListSourcesFunc                    func(ctx context.Context, opts seclai.ListSourcesOptions) (*seclai.SourceListResponse, error)
+	AllSourcesFunc                     func(ctx context.Context, opts seclai.ListSourcesOptions) iter.Seq2[seclai.SourceResponse, error]
+	CreateSourceFunc                   func(ctx context.Context, body seclai.CreateSourceBody) (*seclai.SourceResponse, error)
+	GetSourceFunc                      func(ctx context.Context, sourceID string) (*seclai.SourceResponse, error)
+	UpdateSourceFunc                   func(ctx context.Context, sourceID string, body seclai.UpdateSourceBody) (*seclai.SourceResponse, error)
+	DeleteSourceFunc                   func(ctx context.Context, sourceID string) error
+	UploadFileToSourceFunc             func(ctx context.Context, sourceConnectionID string, req seclai.UploadFileRequest) (*seclai.FileUploadResponse, error)
+	UploadInlineTextToSourceFunc       func(ctx context.Context, sourceConnectionID string, body seclai.InlineTextUploadRequest) (*seclai.FileUploadResponse, error)
+	ListSourceExportsFunc              func(ctx context.Context, sourceID string, opts seclai.ListOptions) (*seclai.ExportListResponse, error)
+	AllSourceExportsFunc               func(ctx context.Context, sourceID string, opts seclai.ListOptions) iter.Seq2[seclai.ExportResponse, error]
+	CreateSourceExportFunc             func(ctx context.Context, sourceID string, body seclai.CreateExportRequest) (*seclai.ExportResponse, error)
+	GetSourceExportFunc                func(ctx context.Context, sourceID, exportID string) (*seclai.ExportResponse, error)
+	CancelSourceExportFunc             func(ctx context.Context, sourceID, exportID string) (*seclai.ExportResponse, error)
+	DeleteSourceExportFunc             func(ctx context.Context, sourceID, exportID string) error
+	DownloadSourceExportFunc           func(ctx context.Context, sourceID, exportID string) (*http.Response, error)
+	EstimateSourceExportFunc           func(ctx context.Context, sourceID string, body seclai.EstimateExportRequest) (*seclai.EstimateExportResponse, error)
+	GetSourceEmbeddingMigrationFunc    func(ctx context.Context, sourceID string) (*seclai.SourceEmbeddingMigrationResponse, error)
+	StartSourceEmbeddingMigrationFunc  func(ctx context.Context, sourceID string, body seclai.StartSourceEmbeddingMigrationRequest) (*seclai.SourceEmbeddingMigrationResponse, error)
+	CancelSourceEmbeddingMigrationFunc func(ctx context.Context, sourceID string) (*seclai.SourceEmbeddingMigrationResponse, error)
+}
+
+var _ seclai.SourcesAPI = (*SourcesAPI)(nil)
+
+func (m *SourcesAPI) ListSources(ctx context.Context, opts seclai.ListSourcesOptions) (*seclai.SourceListResponse, error) {
+	if m.ListSourcesFunc == nil {
+		panic("seclaimock: SourcesAPI.ListSources called with a nil ListSourcesFunc")
+	}
+	return m.ListSourcesFunc(ctx, opts)
+}
+
+func (m *SourcesAPI) AllSources(ctx context.Context, opts seclai.ListSourcesOptions) iter.Seq2[seclai.SourceResponse, error] {
+	if m.AllSourcesFunc == nil {
+		panic("seclaimock: SourcesAPI.AllSources called with a nil AllSourcesFunc")
+	}
+	return m.AllSourcesFunc(ctx, opts)
+}
+
+func (m *SourcesAPI) CreateSource(ctx context.Context, body seclai.CreateSourceBody) (*seclai.SourceResponse, error) {
+	if m.CreateSourceFunc == nil {
+		panic("seclaimock: SourcesAPI.CreateSource called with a nil CreateSourceFunc")
+	}
+	return m.CreateSourceFunc(ctx, body)
+}
+
+func (m *SourcesAPI) GetSource(ctx context.Context, sourceID string) (*seclai.SourceResponse, error) {
+	if m.GetSourceFunc == nil {
+		panic("seclaimock: SourcesAPI.GetSource called with a nil GetSourceFunc")
+	}
+	return m.GetSourceFunc(ctx, sourceID)
+}
+
+func (m *SourcesAPI) UpdateSource(ctx context.Context, sourceID string, body seclai.UpdateSourceBody) (*seclai.SourceResponse, error) {
+	if m.UpdateSourceFunc == nil {
+		panic("seclaimock: SourcesAPI.UpdateSource called with a nil UpdateSourceFunc")
+	}
+	return m.UpdateSourceFunc(ctx, sourceID, body)
+}
+
+func (m *SourcesAPI) DeleteSource(ctx context.Context, sourceID string) error {
+	if m.DeleteSourceFunc == nil {
+		panic("seclaimock: SourcesAPI.DeleteSource called with a nil DeleteSourceFunc")
+	}
+	return m.DeleteSourceFunc(ctx, sourceID)
+}
+
+func (m *SourcesAPI) UploadFileToSource(ctx context.Context, sourceConnectionID string, req seclai.UploadFileRequest) (*seclai.FileUploadResponse, error) {
+	if m.UploadFileToSourceFunc == nil {
+		panic("seclaimock: SourcesAPI.UploadFileToSource called with a nil UploadFileToSourceFunc")
+	}
+	return m.UploadFileToSourceFunc(ctx, sourceConnectionID, req)
+}
+
+func (m *SourcesAPI) UploadInlineTextToSource(ctx context.Context, sourceConnectionID string, body seclai.InlineTextUploadRequest) (*seclai.FileUploadResponse, error) {
+	if m.UploadInlineTextToSourceFunc == nil {
+		panic("seclaimock: SourcesAPI.UploadInlineTextToSource called with a nil UploadInlineTextToSourceFunc")
+	}
+	return m.UploadInlineTextToSourceFunc(ctx, sourceConnectionID, body)
+}
+
+func (m *SourcesAPI) ListSourceExports(ctx context.Context, sourceID string, opts seclai.ListOptions) (*seclai.ExportListResponse, error) {
+	if m.ListSourceExportsFunc == nil {
+		panic("seclaimock: SourcesAPI.ListSourceExports called with a nil ListSourceExportsFunc")
+	}
+	return m.ListSourceExportsFunc(ctx, sourceID, opts)
+}
+
+func (m *SourcesAPI) AllSourceExports(ctx context.Context, sourceID string, opts seclai.ListOptions) iter.Seq2[seclai.ExportResponse, error] {
+	if m.AllSourceExportsFunc == nil {
+		panic("seclaimock: SourcesAPI.AllSourceExports called with a nil AllSourceExportsFunc")
+	}
+	return m.AllSourceExportsFunc(ctx, sourceID, opts)
+}
+
+func (m *SourcesAPI) CreateSourceExport(ctx context.Context, sourceID string, body seclai.CreateExportRequest) (*seclai.ExportResponse, error) {
+	if m.CreateSourceExportFunc == nil {
+		panic("seclaimock: SourcesAPI.CreateSourceExport called with a nil CreateSourceExportFunc")
+	}
+	return m.CreateSourceExportFunc(ctx, sourceID, body)
+}
+
+func (m *SourcesAPI) GetSourceExport(ctx context.Context, sourceID, exportID string) (*seclai.ExportResponse, error) {
+	if m.GetSourceExportFunc == nil {
+		panic("seclaimock: SourcesAPI.GetSourceExport called with a nil GetSourceExportFunc")
+	}
+	return m.GetSourceExportFunc(ctx, sourceID, exportID)
+}
+
+func (m *SourcesAPI) CancelSourceExport(ctx context.Context, sourceID, exportID string) (*seclai.ExportResponse, error) {
+	if m.CancelSourceExportFunc == nil {
+		panic("seclaimock: SourcesAPI.CancelSourceExport called with a nil CancelSourceExportFunc")
+	}
+	return m.CancelSourceExportFunc(ctx, sourceID, exportID)
+}
+
+func (m *SourcesAPI) DeleteSourceExport(ctx context.Context, sourceID, exportID string) error {
+	if m.DeleteSourceExportFunc == nil {
+		panic("seclaimock: SourcesAPI.DeleteSourceExport called with a nil DeleteSourceExportFunc")
+	}
+	return m.DeleteSourceExportFunc(ctx, sourceID, exportID)
+}
+
+func (m *SourcesAPI) DownloadSourceExport(ctx context.Context, sourceID, exportID string) (*http.Response, error) {
+	if m.DownloadSourceExportFunc == nil {
+		panic("seclaimock: SourcesAPI.DownloadSourceExport called with a nil DownloadSourceExportFunc")
+	}
+	return m.DownloadSourceExportFunc(ctx, sourceID, exportID)
+}
+
+func (m *SourcesAPI) EstimateSourceExport(ctx context.Context, sourceID string, body seclai.EstimateExportRequest) (*seclai.EstimateExportResponse, error) {
+	if m.EstimateSourceExportFunc == nil {
+		panic("seclaimock: SourcesAPI.EstimateSourceExport called with a nil EstimateSourceExportFunc")
+	}
+	return m.EstimateSourceExportFunc(ctx, sourceID, body)
+}
+
+func (m *SourcesAPI) GetSourceEmbeddingMigration(ctx context.Context, sourceID string) (*seclai.SourceEmbeddingMigrationResponse, error) {
+	if m.GetSourceEmbeddingMigrationFunc == nil {
+		panic("seclaimock: SourcesAPI.GetSourceEmbeddingMigration called with a nil GetSourceEmbeddingMigrationFunc")
+	}
+	return m.GetSourceEmbeddingMigrationFunc(ctx, sourceID)
+}
+
+func (m *SourcesAPI) StartSourceEmbeddingMigration(ctx context.Context, sourceID string, body seclai.StartSourceEmbeddingMigrationRequest) (*seclai.SourceEmbeddingMigrationResponse, error) {
+	if m.StartSourceEmbeddingMigrationFunc == nil {
+		panic("seclaimock: SourcesAPI.StartSourceEmbeddingMigration called with a nil StartSourceEmbeddingMigrationFunc")
+	}
+	return m.StartSourceEmbeddingMigrationFunc(ctx, sourceID, body)
+}
+
+func (m *SourcesAPI) CancelSourceEmbeddingMigration(ctx context.Context, sourceID string) (*seclai.SourceEmbeddingMigrationResponse, error) {
+	if m.CancelSourceEmbeddingMigrationFunc == nil {
+		panic("seclaimock: SourcesAPI.CancelSourceEmbeddingMigration called with a nil CancelSourceEmbeddingMigrationFunc")
+	}
+	return m.CancelSourceEmbeddingMigrationFunc(ctx, sourceID)
+}
+
+// ContentAPI is a mock of [seclai.ContentAPI].
+type ContentAPI struct {
+	GetContentDetailFunc             func(ctx context.Context, contentVersionID string, start, end int) (*seclai.ContentDetailResponse, error)
+	ReplaceContentWithInlineTextFunc func(ctx context.Context, contentVersionID string, body seclai.InlineTextReplaceRequest) (*seclai.ContentFileUploadResponse, error)
+	UploadFileToContentFunc          func(ctx context.Context, contentVersionID string, req seclai.UploadFileRequest) (*seclai.ContentFileUploadResponse, error)
+	DeleteContentFunc                func(ctx context.Context, contentVersionID string) error
+	ListContentEmbeddingsFunc        func(ctx context.Context, contentVersionID string, opts seclai.ListOptions) (*seclai.ContentEmbeddingsListResponse, error)
+	AllContentEmbeddingsFunc         func(ctx context.Context, contentVersionID string, opts seclai.ListOptions) iter.Seq2[seclai.ContentEmbeddingResponse, error]
+}
+
+var _ seclai.ContentAPI = (*ContentAPI)(nil)
+
+func (m *ContentAPI) GetContentDetail(ctx context.Context, contentVersionID string, start, end int) (*seclai.ContentDetailResponse, error) {
+	if m.GetContentDetailFunc == nil {
+		panic("seclaimock: ContentAPI.GetContentDetail called with a nil GetContentDetailFunc")
+	}
+	return m.GetContentDetailFunc(ctx, contentVersionID, start, end)
+}
+
+func (m *ContentAPI) ReplaceContentWithInlineText(ctx context.Context, contentVersionID string, body seclai.InlineTextReplaceRequest) (*seclai.ContentFileUploadResponse, error) {
+	if m.ReplaceContentWithInlineTextFunc == nil {
+		panic("seclaimock: ContentAPI.ReplaceContentWithInlineText called with a nil ReplaceContentWithInlineTextFunc")
+	}
+	return m.ReplaceContentWithInlineTextFunc(ctx, contentVersionID, body)
+}
+
+func (m *ContentAPI) UploadFileToContent(ctx context.Context, contentVersionID string, req seclai.UploadFileRequest) (*seclai.ContentFileUploadResponse, error) {
+	if m.UploadFileToContentFunc == nil {
+		panic("seclaimock: ContentAPI.UploadFileToContent called with a nil UploadFileToContentFunc")
+	}
+	return m.UploadFileToContentFunc(ctx, contentVersionID, req)
+}
+
+func (m *ContentAPI) DeleteContent(ctx context.Context, contentVersionID string) error {
+	if m.DeleteContentFunc == nil {
+		panic("seclaimock: ContentAPI.DeleteContent called with a nil DeleteContentFunc")
+	}
+	return m.DeleteContentFunc(ctx, contentVersionID)
+}
+
+func (m *ContentAPI) ListContentEmbeddings(ctx context.Context, contentVersionID string, opts seclai.ListOptions) (*seclai.ContentEmbeddingsListResponse, error) {
+	if m.ListContentEmbeddingsFunc == nil {
+		panic("seclaimock: ContentAPI.ListContentEmbeddings called with a nil ListContentEmbeddingsFunc")
+	}
+	return m.ListContentEmbeddingsFunc(ctx, contentVersionID, opts)
+}
+
+func (m *ContentAPI) AllContentEmbeddings(ctx context.Context, contentVersionID string, opts seclai.ListOptions) iter.Seq2[seclai.ContentEmbeddingResponse, error] {
+	if m.AllContentEmbeddingsFunc == nil {
+		panic("seclaimock: ContentAPI.AllContentEmbeddings called with a nil AllContentEmbeddingsFunc")
+	}
+	return m.AllContentEmbeddingsFunc(ctx, contentVersionID, opts)
+}
+
+// SolutionsAPI is a mock of [seclai.SolutionsAPI].
+type SolutionsAPI struct {
+	ListSolutionsFunc                       func(ctx context.Context, opts seclai.SortableListOptions) (*seclai.SolutionListResponse, error)
+	AllSolutionsFunc                        func(ctx context.Context, opts seclai.SortableListOptions) iter.Seq2[seclai.SolutionSummaryResponse, error]
+	CreateSolutionFunc                      func(ctx context.Context, body seclai.CreateSolutionRequest) (*seclai.SolutionResponse, error)
+	GetSolutionFunc                         func(ctx context.Context, solutionID string) (*seclai.SolutionResponse, error)
+	UpdateSolutionFunc                      func(ctx context.Context, solutionID string, body seclai.UpdateSolutionRequest) (*seclai.SolutionResponse, error)
+	DeleteSolutionFunc                      func(ctx context.Context, solutionID string) error
+	LinkAgentsToSolutionFunc                func(ctx context.Context, solutionID string, body seclai.LinkResourcesRequest) (*seclai.SolutionResponse, error)
+	UnlinkAgentsFromSolutionFunc            func(ctx context.Context, solutionID string, body seclai.UnlinkResourcesRequest) (*seclai.SolutionResponse, error)
+	LinkKnowledgeBasesToSolutionFunc        func(ctx context.Context, solutionID string, body seclai.LinkResourcesRequest) (*seclai.SolutionResponse, error)
+	UnlinkKnowledgeBasesFromSolutionFunc    func(ctx context.Context, solutionID string, body seclai.UnlinkResourcesRequest) (*seclai.SolutionResponse, error)
+	LinkSourceConnectionsToSolutionFunc     func(ctx context.Context, solutionID string, body seclai.LinkResourcesRequest) (*seclai.SolutionResponse, error)
+	UnlinkSourceConnectionsFromSolutionFunc func(ctx context.Context, solutionID string, body seclai.UnlinkResourcesRequest) (*seclai.SolutionResponse, error)
+	ListSolutionConversationsFunc           func(ctx context.Context, solutionID string) ([]seclai.SolutionConversationResponse, error)
+	AddSolutionConversationTurnFunc         func(ctx context.Context, solutionID string, body seclai.AddConversationTurnRequest) (*seclai.SolutionConversationResponse, error)
+	MarkSolutionConversationTurnFunc        func(ctx context.Context, solutionID, conversationID string, body seclai.MarkConversationTurnRequest) error
+	GenerateSolutionAiPlanFunc              func(ctx context.Context, solutionID string, body seclai.AiAssistantGenerateRequest) (*seclai.AiAssistantGenerateResponse, error)
+	GenerateSolutionAiKnowledgeBaseFunc     func(ctx context.Context, solutionID string, body seclai.AiAssistantGenerateRequest) (*seclai.AiAssistantGenerateResponse, error)
+	GenerateSolutionAiSourceFunc            func(ctx context.Context, solutionID string, body seclai.AiAssistantGenerateRequest) (*seclai.AiAssistantGenerateResponse, error)
+	AcceptSolutionAiPlanFunc                func(ctx context.Context, solutionID, conversationID string, body seclai.AiAssistantAcceptRequest) (*seclai.AiAssistantAcceptResponse, error)
+	DeclineSolutionAiPlanFunc               func(ctx context.Context, solutionID, conversationID string) error
+}
+
+var _ seclai.SolutionsAPI = (*SolutionsAPI)(nil)
+
+func (m *SolutionsAPI) ListSolutions(ctx context.Context, opts seclai.SortableListOptions) (*seclai.SolutionListResponse, error) {
+	if m.ListSolutionsFunc == nil {
+		panic("seclaimock: SolutionsAPI.ListSolutions called with a nil ListSolutionsFunc")
+	}
+	return m.ListSolutionsFunc(ctx, opts)
+}
+
+func (m *SolutionsAPI) AllSolutions(ctx context.Context, opts seclai.SortableListOptions) iter.Seq2[seclai.SolutionSummaryResponse, error] {
+	if m.AllSolutionsFunc == nil {
+		panic("seclaimock: SolutionsAPI.AllSolutions called with a nil AllSolutionsFunc")
+	}
+	return m.AllSolutionsFunc(ctx, opts)
+}
+
+func (m *SolutionsAPI) CreateSolution(ctx context.Context, body seclai.CreateSolutionRequest) (*seclai.SolutionResponse, error) {
+	if m.CreateSolutionFunc == nil {
+		panic("seclaimock: SolutionsAPI.CreateSolution called with a nil CreateSolutionFunc")
+	}
+	return m.CreateSolutionFunc(ctx, body)
+}
+
+func (m *SolutionsAPI) GetSolution(ctx context.Context, solutionID string) (*seclai.SolutionResponse, error) {
+	if m.GetSolutionFunc == nil {
+		panic("seclaimock: SolutionsAPI.GetSolution called with a nil GetSolutionFunc")
+	}
+	return m.GetSolutionFunc(ctx, solutionID)
+}
+
+func (m *SolutionsAPI) UpdateSolution(ctx context.Context, solutionID string, body seclai.UpdateSolutionRequest) (*seclai.SolutionResponse, error) {
+	if m.UpdateSolutionFunc == nil {
+		panic("seclaimock: SolutionsAPI.UpdateSolution called with a nil UpdateSolutionFunc")
+	}
+	return m.UpdateSolutionFunc(ctx, solutionID, body)
+}
+
+func (m *SolutionsAPI) DeleteSolution(ctx context.Context, solutionID string) error {
+	if m.DeleteSolutionFunc == nil {
+		panic("seclaimock: SolutionsAPI.DeleteSolution called with a nil DeleteSolutionFunc")
+	}
+	return m.DeleteSolutionFunc(ctx, solutionID)
+}
+
+func (m *SolutionsAPI) LinkAgentsToSolution(ctx context.Context, solutionID string, body seclai.LinkResourcesRequest) (*seclai.SolutionResponse, error) {
+	if m.LinkAgentsToSolutionFunc == nil {
+		panic("seclaimock: SolutionsAPI.LinkAgentsToSolution called with a nil LinkAgentsToSolutionFunc")
+	}
+	return m.LinkAgentsToSolutionFunc(ctx, solutionID, body)
+}
+
+func (m *SolutionsAPI) UnlinkAgentsFromSolution(ctx context.Context, solutionID string, body seclai.UnlinkResourcesRequest) (*seclai.SolutionResponse, error) {
+	if m.UnlinkAgentsFromSolutionFunc == nil {
+		panic("seclaimock: SolutionsAPI.UnlinkAgentsFromSolution called with a nil UnlinkAgentsFromSolutionFunc")
+	}
+	return m.UnlinkAgentsFromSolutionFunc(ctx, solutionID, body)
+}
+
+func (m *SolutionsAPI) LinkKnowledgeBasesToSolution(ctx context.Context, solutionID string, body seclai.LinkResourcesRequest) (*seclai.SolutionResponse, error) {
+	if m.LinkKnowledgeBasesToSolutionFunc == nil {
+		panic("seclaimock: SolutionsAPI.LinkKnowledgeBasesToSolution called with a nil LinkKnowledgeBasesToSolutionFunc")
+	}
+	return m.LinkKnowledgeBasesToSolutionFunc(ctx, solutionID, body)
+}
+
+func (m *SolutionsAPI) UnlinkKnowledgeBasesFromSolution(ctx context.Context, solutionID string, body seclai.UnlinkResourcesRequest) (*seclai.SolutionResponse, error) {
+	if m.UnlinkKnowledgeBasesFromSolutionFunc == nil {
+		panic("seclaimock: SolutionsAPI.UnlinkKnowledgeBasesFromSolution called with a nil UnlinkKnowledgeBasesFromSolutionFunc")
+	}
+	return m.UnlinkKnowledgeBasesFromSolutionFunc(ctx, solutionID, body)
+}
+
+func (m *SolutionsAPI) LinkSourceConnectionsToSolution(ctx context.Context, solutionID string, body seclai.LinkResourcesRequest) (*seclai.SolutionResponse, error) {
+	if m.LinkSourceConnectionsToSolutionFunc == nil {
+		panic("seclaimock: SolutionsAPI.LinkSourceConnectionsToSolution called with a nil LinkSourceConnectionsToSolutionFunc")
+	}
+	return m.LinkSourceConnectionsToSolutionFunc(ctx, solutionID, body)
+}
+
+func (m *SolutionsAPI) UnlinkSourceConnectionsFromSolution(ctx context.Context, solutionID string, body seclai.UnlinkResourcesRequest) (*seclai.SolutionResponse, error) {
+	if m.UnlinkSourceConnectionsFromSolutionFunc == nil {
+		panic("seclaimock: SolutionsAPI.UnlinkSourceConnectionsFromSolution called with a nil UnlinkSourceConnectionsFromSolutionFunc")
+	}
+	return m.UnlinkSourceConnectionsFromSolutionFunc(ctx, solutionID, body)
+}
+
+func (m *SolutionsAPI) ListSolutionConversations(ctx context.Context, solutionID string) ([]seclai.SolutionConversationResponse, error) {
+	if m.ListSolutionConversationsFunc == nil {
+		panic("seclaimock: SolutionsAPI.ListSolutionConversations called with a nil ListSolutionConversationsFunc")
+	}
+	return m.ListSolutionConversationsFunc(ctx, solutionID)
+}
+
+func (m *SolutionsAPI) AddSolutionConversationTurn(ctx context.Context, solutionID string, body seclai.AddConversationTurnRequest) (*seclai.SolutionConversationResponse, error) {
+	if m.AddSolutionConversationTurnFunc == nil {
+		panic("seclaimock: SolutionsAPI.AddSolutionConversationTurn called with a nil AddSolutionConversationTurnFunc")
+	}
+	return m.AddSolutionConversationTurnFunc(ctx, solutionID, body)
+}
+
+func (m *SolutionsAPI) MarkSolutionConversationTurn(ctx context.Context, solutionID, conversationID string, body seclai.MarkConversationTurnRequest) error {
+	if m.MarkSolutionConversationTurnFunc == nil {
+		panic("seclaimock: SolutionsAPI.MarkSolutionConversationTurn called with a nil MarkSolutionConversationTurnFunc")
+	}
+	return m.MarkSolutionConversationTurnFunc(ctx, solutionID, conversationID, body)
+}
+
+func (m *SolutionsAPI) GenerateSolutionAiPlan(ctx context.Context, solutionID string, body seclai.AiAssistantGenerateRequest) (*seclai.AiAssistantGenerateResponse, error) {
+	if m.GenerateSolutionAiPlanFunc == nil {
+		panic("seclaimock: SolutionsAPI.GenerateSolutionAiPlan called with a nil GenerateSolutionAiPlanFunc")
+	}
+	return m.GenerateSolutionAiPlanFunc(ctx, solutionID, body)
+}
+
+func (m *SolutionsAPI) GenerateSolutionAiKnowledgeBase(ctx context.Context, solutionID string, body seclai.AiAssistantGenerateRequest) (*seclai.AiAssistantGenerateResponse, error) {
+	if m.GenerateSolutionAiKnowledgeBaseFunc == nil {
+		panic("seclaimock: SolutionsAPI.GenerateSolutionAiKnowledgeBase called with a nil GenerateSolutionAiKnowledgeBaseFunc")
+	}
+	return m.GenerateSolutionAiKnowledgeBaseFunc(ctx, solutionID, body)
+}
+
+func (m *SolutionsAPI) GenerateSolutionAiSource(ctx context.Context, solutionID string, body seclai.AiAssistantGenerateRequest) (*seclai.AiAssistantGenerateResponse, error) {
+	if m.GenerateSolutionAiSourceFunc == nil {
+		panic("seclaimock: SolutionsAPI.GenerateSolutionAiSource called with a nil GenerateSolutionAiSourceFunc")
+	}
+	return m.GenerateSolutionAiSourceFunc(ctx, solutionID, body)
+}
+
+func (m *SolutionsAPI) AcceptSolutionAiPlan(ctx context.Context, solutionID, conversationID string, body seclai.AiAssistantAcceptRequest) (*seclai.AiAssistantAcceptResponse, error) {
+	if m.AcceptSolutionAiPlanFunc == nil {
+		panic("seclaimock: SolutionsAPI.AcceptSolutionAiPlan called with a nil AcceptSolutionAiPlanFunc")
+	}
+	return m.AcceptSolutionAiPlanFunc(ctx, solutionID, conversationID, body)
+}
+
+func (m *SolutionsAPI) DeclineSolutionAiPlan(ctx context.Context, solutionID, conversationID string) error {
+	if m.DeclineSolutionAiPlanFunc == nil {
+		panic("seclaimock: SolutionsAPI.DeclineSolutionAiPlan called with a nil DeclineSolutionAiPlanFunc")
+	}
+	return m.DeclineSolutionAiPlanFunc(ctx, solutionID, conversationID)
+}
+
+// GovernanceAPI is a mock of [seclai.GovernanceAPI].
+type GovernanceAPI struct {
+	GenerateGovernanceAiPlanFunc      func(ctx context.Context, body seclai.GovernanceAiAssistantRequest) (*seclai.GovernanceAiAssistantResponse, error)
+	ListGovernanceAiConversationsFunc func(ctx context.Context) ([]seclai.GovernanceConversationResponse, error)
+	AcceptGovernanceAiPlanFunc        func(ctx context.Context, conversationID string) (*seclai.GovernanceAiAcceptResponse, error)
+	DeclineGovernanceAiPlanFunc       func(ctx context.Context, conversationID string) error
+}
+
+var _ seclai.GovernanceAPI = (*GovernanceAPI)(nil)
+
+func (m *GovernanceAPI) GenerateGovernanceAiPlan(ctx context.Context, body seclai.GovernanceAiAssistantRequest) (*seclai.GovernanceAiAssistantResponse, error) {
+	if m.GenerateGovernanceAiPlanFunc == nil {
+		panic("seclaimock: GovernanceAPI.GenerateGovernanceAiPlan called with a nil GenerateGovernanceAiPlanFunc")
+	}
+	return m.GenerateGovernanceAiPlanFunc(ctx, body)
+}
+
+func (m *GovernanceAPI) ListGovernanceAiConversations(ctx context.Context) ([]seclai.GovernanceConversationResponse, error) {
+	if m.ListGovernanceAiConversationsFunc == nil {
+		panic("seclaimock: GovernanceAPI.ListGovernanceAiConversations called with a nil ListGovernanceAiConversationsFunc")
+	}
+	return m.ListGovernanceAiConversationsFunc(ctx)
+}
+
+func (m *GovernanceAPI) AcceptGovernanceAiPlan(ctx context.Context, conversationID string) (*seclai.GovernanceAiAcceptResponse, error) {
+	if m.AcceptGovernanceAiPlanFunc == nil {
+		panic("seclaimock: GovernanceAPI.AcceptGovernanceAiPlan called with a nil AcceptGovernanceAiPlanFunc")
+	}
+	return m.AcceptGovernanceAiPlanFunc(ctx, conversationID)
+}
+
+func (m *GovernanceAPI) DeclineGovernanceAiPlan(ctx context.Context, conversationID string) error {
+	if m.DeclineGovernanceAiPlanFunc == nil {
+		panic("seclaimock: GovernanceAPI.DeclineGovernanceAiPlan called with a nil DeclineGovernanceAiPlanFunc")
+	}
+	return m.DeclineGovernanceAiPlanFunc(ctx, conversationID)
+}
+
+// AlertsAPI is a mock of [seclai.AlertsAPI].
+type AlertsAPI struct {
+	ListAlertsFunc                        func(ctx context.Context, opts seclai.ListAlertsOptions) (json.RawMessage, error)
+	AllAlertsFunc                         func(ctx context.Context, opts seclai.ListAlertsOptions) iter.Seq2[seclai.AlertResponse, error]
+	GetAlertFunc                          func(ctx context.Context, alertID string) (json.RawMessage, error)
+	ChangeAlertStatusFunc                 func(ctx context.Context, alertID string, body seclai.ChangeStatusRequest) (json.RawMessage, error)
+	AddAlertCommentFunc                   func(ctx context.Context, alertID string, body seclai.AddCommentRequest) (json.RawMessage, error)
+	SubscribeToAlertFunc                  func(ctx context.Context, alertID string) (json.RawMessage, error)
+	UnsubscribeFromAlertFunc              func(ctx context.Context, alertID string) (json.RawMessage, error)
+	ListAlertConfigsFunc                  func(ctx context.Context, opts seclai.ListOptions) (json.RawMessage, error)
+	AllAlertConfigsFunc                   func(ctx context.Context, opts seclai.ListOptions) iter.Seq2[seclai.AlertConfigResponse, error]
+	CreateAlertConfigFunc                 func(ctx context.Context, body seclai.CreateAlertConfigRequest) (json.RawMessage, error)
+	GetAlertConfigFunc                    func(ctx context.Context, configID string) (json.RawMessage, error)
+	UpdateAlertConfigFunc                 func(ctx context.Context, configID string, body seclai.UpdateAlertConfigRequest) (json.RawMessage, error)
+	DeleteAlertConfigFunc                 func(ctx context.Context, configID string) error
+	ListOrganizationAlertPreferencesFunc  func(ctx context.Context) (*seclai.OrganizationAlertPreferenceListResponse, error)
+	UpdateOrganizationAlertPreferenceFunc func(ctx context.Context, organizationID, alertType string, body seclai.UpdateOrganizationAlertPreferenceRequest) (json.RawMessage, error)
+}
+
+var _ seclai.AlertsAPI = (*AlertsAPI)(nil)
+
+func (m *AlertsAPI) ListAlerts(ctx context.Context, opts seclai.ListAlertsOptions) (json.RawMessage, error) {
+	if m.ListAlertsFunc == nil {
+		panic("seclaimock: AlertsAPI.ListAlerts called with a nil ListAlertsFunc")
+	}
+	return m.ListAlertsFunc(ctx, opts)
+}
+
+func (m *AlertsAPI) AllAlerts(ctx context.Context, opts seclai.ListAlertsOptions) iter.Seq2[seclai.AlertResponse, error] {
+	if m.AllAlertsFunc == nil {
+		panic("seclaimock: AlertsAPI.AllAlerts called with a nil AllAlertsFunc")
+	}
+	return m.AllAlertsFunc(ctx, opts)
+}
+
+func (m *AlertsAPI) GetAlert(ctx context.Context, alertID string) (json.RawMessage, error) {
+	if m.GetAlertFunc == nil {
+		panic("seclaimock: AlertsAPI.GetAlert called with a nil GetAlertFunc")
+	}
+	return m.GetAlertFunc(ctx, alertID)
+}
+
+func (m *AlertsAPI) ChangeAlertStatus(ctx context.Context, alertID string, body seclai.ChangeStatusRequest) (json.RawMessage, error) {
+	if m.ChangeAlertStatusFunc == nil {
+		panic("seclaimock: AlertsAPI.ChangeAlertStatus called with a nil ChangeAlertStatusFunc")
+	}
+	return m.ChangeAlertStatusFunc(ctx, alertID, body)
+}
+
+func (m *AlertsAPI) AddAlertComment(ctx context.Context, alertID string, body seclai.AddCommentRequest) (json.RawMessage, error) {
+	if m.AddAlertCommentFunc == nil {
+		panic("seclaimock: AlertsAPI.AddAlertComment called with a nil AddAlertCommentFunc")
+	}
+	return m.AddAlertCommentFunc(ctx, alertID, body)
+}
+
+func (m *AlertsAPI) SubscribeToAlert(ctx context.Context, alertID string) (json.RawMessage, error) {
+	if m.SubscribeToAlertFunc == nil {
+		panic("seclaimock: AlertsAPI.SubscribeToAlert called with a nil SubscribeToAlertFunc")
+	}
+	return m.SubscribeToAlertFunc(ctx, alertID)
+}
+
+func (m *AlertsAPI) UnsubscribeFromAlert(ctx context.Context, alertID string) (json.RawMessage, error) {
+	if m.UnsubscribeFromAlertFunc == nil {
+		panic("seclaimock: AlertsAPI.UnsubscribeFromAlert called with a nil UnsubscribeFromAlertFunc")
+	}
+	return m.UnsubscribeFromAlertFunc(ctx, alertID)
+}
+
+func (m *AlertsAPI) ListAlertConfigs(ctx context.Context, opts seclai.ListOptions) (json.RawMessage, error) {
+	if m.ListAlertConfigsFunc == nil {
+		panic("seclaimock: AlertsAPI.ListAlertConfigs called with a nil ListAlertConfigsFunc")
+	}
+	return m.ListAlertConfigsFunc(ctx, opts)
+}
+
+func (m *AlertsAPI) AllAlertConfigs(ctx context.Context, opts seclai.ListOptions) iter.Seq2[seclai.AlertConfigResponse, error] {
+	if m.AllAlertConfigsFunc == nil {
+		panic("seclaimock: AlertsAPI.AllAlertConfigs called with a nil AllAlertConfigsFunc")
+	}
+	return m.AllAlertConfigsFunc(ctx, opts)
+}
+
+func (m *AlertsAPI) CreateAlertConfig(ctx context.Context, body seclai.CreateAlertConfigRequest) (json.RawMessage, error) {
+	if m.CreateAlertConfigFunc == nil {
+		panic("seclaimock: AlertsAPI.CreateAlertConfig called with a nil CreateAlertConfigFunc")
+	}
+	return m.CreateAlertConfigFunc(ctx, body)
+}
+
+func (m *AlertsAPI) GetAlertConfig(ctx context.Context, configID string) (json.RawMessage, error) {
+	if m.GetAlertConfigFunc == nil {
+		panic("seclaimock: AlertsAPI.GetAlertConfig called with a nil GetAlertConfigFunc")
+	}
+	return m.GetAlertConfigFunc(ctx, configID)
+}
+
+func (m *AlertsAPI) UpdateAlertConfig(ctx context.Context, configID string, body seclai.UpdateAlertConfigRequest) (json.RawMessage, error) {
+	if m.UpdateAlertConfigFunc == nil {
+		panic("seclaimock: AlertsAPI.UpdateAlertConfig called with a nil UpdateAlertConfigFunc")
+	}
+	return m.UpdateAlertConfigFunc(ctx, configID, body)
+}
+
+func (m *AlertsAPI) DeleteAlertConfig(ctx context.Context, configID string) error {
+	if m.DeleteAlertConfigFunc == nil {
+		panic("seclaimock: AlertsAPI.DeleteAlertConfig called with a nil DeleteAlertConfigFunc")
+	}
+	return m.DeleteAlertConfigFunc(ctx, configID)
+}
+
+func (m *AlertsAPI) ListOrganizationAlertPreferences(ctx context.Context) (*seclai.OrganizationAlertPreferenceListResponse, error) {
+	if m.ListOrganizationAlertPreferencesFunc == nil {
+		panic("seclaimock: AlertsAPI.ListOrganizationAlertPreferences called with a nil ListOrganizationAlertPreferencesFunc")
+	}
+	return m.ListOrganizationAlertPreferencesFunc(ctx)
+}
+
+func (m *AlertsAPI) UpdateOrganizationAlertPreference(ctx context.Context, organizationID, alertType string, body seclai.UpdateOrganizationAlertPreferenceRequest) (json.RawMessage, error) {
+	if m.UpdateOrganizationAlertPreferenceFunc == nil {
+		panic("seclaimock: AlertsAPI.UpdateOrganizationAlertPreference called with a nil UpdateOrganizationAlertPreferenceFunc")
+	}
+	return m.UpdateOrganizationAlertPreferenceFunc(ctx, organizationID, alertType, body)
+}
+
+// ModelsAPI is a mock of [seclai.ModelsAPI].
+type ModelsAPI struct {
+	ListModelsFunc               func(ctx context.Context, opts seclai.ListModelsOptions) ([]seclai.ProviderGroupResponse, error)
+	GetModelFunc                 func(ctx context.Context, modelID string) (*seclai.PromptModelResponse, error)
+	GetGenerationTiersFunc       func(ctx context.Context) (json.RawMessage, error)
+	GetModelRecommendationsFunc  func(ctx context.Context, modelID string) (json.RawMessage, error)
+	ListModelAlertsFunc          func(ctx context.Context, opts seclai.ListOptions) (json.RawMessage, error)
+	AllModelAlertsFunc           func(ctx context.Context, opts seclai.ListOptions) iter.Seq2[seclai.ModelAlertResponse, error]
+	MarkModelAlertReadFunc       func(ctx context.Context, alertID string) error
+	MarkAllModelAlertsReadFunc   func(ctx context.Context) error
+	GetUnreadModelAlertCountFunc func(ctx context.Context) (json.RawMessage, error)
+	ListExperimentsFunc          func(ctx context.Context, opts seclai.ListExperimentsOptions) (json.RawMessage, error)
+	AllExperimentsFunc           func(ctx context.Context, opts seclai.ListExperimentsOptions) iter.Seq2[seclai.ExperimentSummaryResponse, error]
+	CreateExperimentFunc         func(ctx context.Context, body seclai.PlaygroundCreateRequest) (json.RawMessage, error)
+	GetExperimentFunc            func(ctx context.Context, experimentID string) (json.RawMessage, error)
+	CancelExperimentFunc         func(ctx context.Context, experimentID string) (json.RawMessage, error)
+	DeleteExperimentFunc         func(ctx context.Context, experimentID string) error
+}
+
+var _ seclai.ModelsAPI = (*ModelsAPI)(nil)
+
+func (m *ModelsAPI) ListModels(ctx context.Context, opts seclai.ListModelsOptions) ([]seclai.ProviderGroupResponse, error) {
+	if m.ListModelsFunc == nil {
+		panic("seclaimock: ModelsAPI.ListModels called with a nil ListModelsFunc")
+	}
+	return m.ListModelsFunc(ctx, opts)
+}
+
+func (m *ModelsAPI) GetModel(ctx context.Context, modelID string) (*seclai.PromptModelResponse, error) {
+	if m.GetModelFunc == nil {
+		panic("seclaimock: ModelsAPI.GetModel called with a nil GetModelFunc")
+	}
+	return m.GetModelFunc(ctx, modelID)
+}
+
+func (m *ModelsAPI) GetGenerationTiers(ctx context.Context) (json.RawMessage, error) {
+	if m.GetGenerationTiersFunc == nil {
+		panic("seclaimock: ModelsAPI.GetGenerationTiers called with a nil GetGenerationTiersFunc")
+	}
+	return m.GetGenerationTiersFunc(ctx)
+}
+
+func (m *ModelsAPI) GetModelRecommendations(ctx context.Context, modelID string) (json.RawMessage, error) {
+	if m.GetModelRecommendationsFunc == nil {
+		panic("seclaimock: ModelsAPI.GetModelRecommendations called with a nil GetModelRecommendationsFunc")
+	}
+	return m.GetModelRecommendationsFunc(ctx, modelID)
+}
+
+func (m *ModelsAPI) ListModelAlerts(ctx context.Context, opts seclai.ListOptions) (json.RawMessage, error) {
+	if m.ListModelAlertsFunc == nil {
+		panic("seclaimock: ModelsAPI.ListModelAlerts called with a nil ListModelAlertsFunc")
+	}
+	return m.ListModelAlertsFunc(ctx, opts)
+}
+
+func (m *ModelsAPI) AllModelAlerts(ctx context.Context, opts seclai.ListOptions) iter.Seq2[seclai.ModelAlertResponse, error] {
+	if m.AllModelAlertsFunc == nil {
+		panic("seclaimock: ModelsAPI.AllModelAlerts called with a nil AllModelAlertsFunc")
+	}
+	return m.AllModelAlertsFunc(ctx, opts)
+}
+
+func (m *ModelsAPI) MarkModelAlertRead(ctx context.Context, alertID string) error {
+	if m.MarkModelAlertReadFunc == nil {
+		panic("seclaimock: ModelsAPI.MarkModelAlertRead called with a nil MarkModelAlertReadFunc")
+	}
+	return m.MarkModelAlertReadFunc(ctx, alertID)
+}
+
+func (m *ModelsAPI) MarkAllModelAlertsRead(ctx context.Context) error {
+	if m.MarkAllModelAlertsReadFunc == nil {
+		panic("seclaimock: ModelsAPI.MarkAllModelAlertsRead called with a nil MarkAllModelAlertsReadFunc")
+	}
+	return m.MarkAllModelAlertsReadFunc(ctx)
+}
+
+func (m *ModelsAPI) GetUnreadModelAlertCount(ctx context.Context) (json.RawMessage, error) {
+	if m.GetUnreadModelAlertCountFunc == nil {
+		panic("seclaimock: ModelsAPI.GetUnreadModelAlertCount called with a nil GetUnreadModelAlertCountFunc")
+	}
+	return m.GetUnreadModelAlertCountFunc(ctx)
+}
+
+func (m *ModelsAPI) ListExperiments(ctx context.Context, opts seclai.ListExperimentsOptions) (json.RawMessage, error) {
+	if m.ListExperimentsFunc == nil {
+		panic("seclaimock: ModelsAPI.ListExperiments called with a nil ListExperimentsFunc")
+	}
+	return m.ListExperimentsFunc(ctx, opts)
+}
+
+func (m *ModelsAPI) AllExperiments(ctx context.Context, opts seclai.ListExperimentsOptions) iter.Seq2[seclai.ExperimentSummaryResponse, error] {
+	if m.AllExperimentsFunc == nil {
+		panic("seclaimock: ModelsAPI.AllExperiments called with a nil AllExperimentsFunc")
+	}
+	return m.AllExperimentsFunc(ctx, opts)
+}
+
+func (m *ModelsAPI) CreateExperiment(ctx context.Context, body seclai.PlaygroundCreateRequest) (json.RawMessage, error) {
+	if m.CreateExperimentFunc == nil {
+		panic("seclaimock: ModelsAPI.CreateExperiment called with a nil CreateExperimentFunc")
+	}
+	return m.CreateExperimentFunc(ctx, body)
+}
+
+func (m *ModelsAPI) GetExperiment(ctx context.Context, experimentID string) (json.RawMessage, error) {
+	if m.GetExperimentFunc == nil {
+		panic("seclaimock: ModelsAPI.GetExperiment called with a nil GetExperimentFunc")
+	}
+	return m.GetExperimentFunc(ctx, experimentID)
+}
+
+func (m *ModelsAPI) CancelExperiment(ctx context.Context, experimentID string) (json.RawMessage, error) {
+	if m.CancelExperimentFunc == nil {
+		panic("seclaimock: ModelsAPI.CancelExperiment called with a nil CancelExperimentFunc")
+	}
+	return m.CancelExperimentFunc(ctx, experimentID)
+}
+
+func (m *ModelsAPI) DeleteExperiment(ctx context.Context, experimentID string) error {
+	if m.DeleteExperimentFunc == nil {
+		panic("seclaimock: ModelsAPI.DeleteExperiment called with a nil DeleteExperimentFunc")
+	}
+	return m.DeleteExperimentFunc(ctx, experimentID)
+}
+
+// EmailAPI is a mock of [seclai.EmailAPI].
+type EmailAPI struct {
+	SetEmailTriggerConfigFunc      func(ctx context.Context, agentID string, triggerID string, body seclai.RoutersApiAgentsSetEmailTriggerConfigRequest) (*seclai.EmailTriggerConfigResponse, error)
+	ListAgentEmailOptOutsFunc      func(ctx context.Context, opts seclai.AgentEmailOptOutOptions) (*seclai.AgentEmailOptOutListResponse, error)
+	AllAgentEmailOptOutsFunc       func(ctx context.Context, opts seclai.AgentEmailOptOutOptions) iter.Seq2[seclai.AgentEmailOptOutResponse, error]
+	RemoveAgentEmailOptOutFunc     func(ctx context.Context, optoutID string) error
+	ListBlockedEmailSendersFunc    func(ctx context.Context, opts seclai.BlockedEmailSenderOptions) (*seclai.BlockedEmailSenderListResponse, error)
+	AllBlockedEmailSendersFunc     func(ctx context.Context, opts seclai.BlockedEmailSenderOptions) iter.Seq2[seclai.BlockedEmailSenderResponse, error]
+	BlockEmailSenderFunc           func(ctx context.Context, body seclai.BlockEmailSenderRequest) (*seclai.BlockedEmailSenderResponse, error)
+	UnblockEmailSenderFunc         func(ctx context.Context, blockedID string) error
+	SetAutoBlockModeFunc           func(ctx context.Context, body seclai.SetAutoBlockModeRequest) (*seclai.BlockedEmailSenderListResponse, error)
+	ListInboundEmailRejectionsFunc func(ctx context.Context, opts seclai.InboundEmailRejectionOptions) ([]seclai.InboundEmailRejectionResponse, error)
+	GetInboundEmailStatusFunc      func(ctx context.Context) (*seclai.InboundEmailStatusResponse, error)
+	CancelQueuedEmailRunsFunc      func(ctx context.Context) (*seclai.CancelQueuedRunsResponse, error)
+	ResumeInboundEmailFunc         func(ctx context.Context) (*seclai.ResumeInboundResponse, error)
+	ListEmailDomainsFunc           func(ctx context.Context) (*seclai.EmailDomainsListResponse, error)
+	AddEmailDomainFunc             func(ctx context.Context, body seclai.AddEmailDomainRequest) (*seclai.EmailDomainResponse, error)
+	RemoveEmailDomainFunc          func(ctx context.Context, domainID string) (*seclai.RemoveEmailDomainResponse, error)
+	VerifyEmailDomainFunc          func(ctx context.Context, domainID string) (*seclai.EmailDomainResponse, error)
+	SetPrimaryEmailDomainFunc      func(ctx context.Context, domainID string) (*seclai.EmailDomainResponse, error)
+	UseSharedEmailDomainFunc       func(ctx context.Context) error
+	SendEmailDomainTestEmailFunc   func(ctx context.Context, domainID string) (*seclai.SendTestEmailResponse, error)
+	GetDmarcSummaryFunc            func(ctx context.Context, domainID string, opts seclai.DmarcOptions) (*seclai.DmarcSummaryResponse, error)
+}
+
+var _ seclai.EmailAPI = (*EmailAPI)(nil)
+
+func (m *EmailAPI) SetEmailTriggerConfig(ctx context.Context, agentID string, triggerID string, body seclai.RoutersApiAgentsSetEmailTriggerConfigRequest) (*seclai.EmailTriggerConfigResponse, error) {
+	if m.SetEmailTriggerConfigFunc == nil {
+		panic("seclaimock: EmailAPI.SetEmailTriggerConfig called with a nil SetEmailTriggerConfigFunc")
+	}
+	return m.SetEmailTriggerConfigFunc(ctx, agentID, triggerID, body)
+}
+
+func (m *EmailAPI) ListAgentEmailOptOuts(ctx context.Context, opts seclai.AgentEmailOptOutOptions) (*seclai.AgentEmailOptOutListResponse, error) {
+	if m.ListAgentEmailOptOutsFunc == nil {
+		panic("seclaimock: EmailAPI.ListAgentEmailOptOuts called with a nil ListAgentEmailOptOutsFunc")
+	}
+	return m.ListAgentEmailOptOutsFunc(ctx, opts)
+}
+
+func (m *EmailAPI) AllAgentEmailOptOuts(ctx context.Context, opts seclai.AgentEmailOptOutOptions) iter.Seq2[seclai.AgentEmailOptOutResponse, error] {
+	if m.AllAgentEmailOptOutsFunc == nil {
+		panic("seclaimock: EmailAPI.AllAgentEmailOptOuts called with a nil AllAgentEmailOptOutsFunc")
+	}
+	return m.AllAgentEmailOptOutsFunc(ctx, opts)
+}
+
+func (m *EmailAPI) RemoveAgentEmailOptOut(ctx context.Context, optoutID string) error {
+	if m.RemoveAgentEmailOptOutFunc == nil {
+		panic("seclaimock: EmailAPI.RemoveAgentEmailOptOut called with a nil RemoveAgentEmailOptOutFunc")
+	}
+	return m.RemoveAgentEmailOptOutFunc(ctx, optoutID)
+}
+
+func (m *EmailAPI) ListBlockedEmailSenders(ctx context.Context, opts seclai.BlockedEmailSenderOptions) (*seclai.BlockedEmailSenderListResponse, error) {
+	if m.ListBlockedEmailSendersFunc == nil {
+		panic("seclaimock: EmailAPI.ListBlockedEmailSenders called with a nil ListBlockedEmailSendersFunc")
+	}
+	return m.ListBlockedEmailSendersFunc(ctx, opts)
+}
+
+func (m *EmailAPI) AllBlockedEmailSenders(ctx context.Context, opts seclai.BlockedEmailSenderOptions) iter.Seq2[seclai.BlockedEmailSenderResponse, error] {
+	if m.AllBlockedEmailSendersFunc == nil {
+		panic("seclaimock: EmailAPI.AllBlockedEmailSenders called with a nil AllBlockedEmailSendersFunc")
+	}
+	return m.AllBlockedEmailSendersFunc(ctx, opts)
+}
+
+func (m *EmailAPI) BlockEmailSender(ctx context.Context, body seclai.BlockEmailSenderRequest) (*seclai.BlockedEmailSenderResponse, error) {
+	if m.BlockEmailSenderFunc == nil {
+		panic("seclaimock: EmailAPI.BlockEmailSender called with a nil BlockEmailSenderFunc")
+	}
+	return m.BlockEmailSenderFunc(ctx, body)
+}
+
+func (m *EmailAPI) UnblockEmailSender(ctx context.Context, blockedID string) error {
+	if m.UnblockEmailSenderFunc == nil {
+		panic("seclaimock: EmailAPI.UnblockEmailSender called with a nil UnblockEmailSenderFunc")
+	}
+	return m.UnblockEmailSenderFunc(ctx, blockedID)
+}
+
+func (m *EmailAPI) SetAutoBlockMode(ctx context.Context, body seclai.SetAutoBlockModeRequest) (*seclai.BlockedEmailSenderListResponse, error) {
+	if m.SetAutoBlockModeFunc == nil {
+		panic("seclaimock: EmailAPI.SetAutoBlockMode called with a nil SetAutoBlockModeFunc")
+	}
+	return m.SetAutoBlockModeFunc(ctx, body)
+}
+
+func (m *EmailAPI) ListInboundEmailRejections(ctx context.Context, opts seclai.InboundEmailRejectionOptions) ([]seclai.InboundEmailRejectionResponse, error) {
+	if m.ListInboundEmailRejectionsFunc == nil {
+		panic("seclaimock: EmailAPI.ListInboundEmailRejections called with a nil ListInboundEmailRejectionsFunc")
+	}
+	return m.ListInboundEmailRejectionsFunc(ctx, opts)
+}
+
+func (m *EmailAPI) GetInboundEmailStatus(ctx context.Context) (*seclai.InboundEmailStatusResponse, error) {
+	if m.GetInboundEmailStatusFunc == nil {
+		panic("seclaimock: EmailAPI.GetInboundEmailStatus called with a nil GetInboundEmailStatusFunc")
+	}
+	return m.GetInboundEmailStatusFunc(ctx)
+}
+
+func (m *EmailAPI) CancelQueuedEmailRuns(ctx context.Context) (*seclai.CancelQueuedRunsResponse, error) {
+	if m.CancelQueuedEmailRunsFunc == nil {
+		panic("seclaimock: EmailAPI.CancelQueuedEmailRuns called with a nil CancelQueuedEmailRunsFunc")
+	}
+	return m.CancelQueuedEmailRunsFunc(ctx)
+}
+
+func (m *EmailAPI) ResumeInboundEmail(ctx context.Context) (*seclai.ResumeInboundResponse, error) {
+	if m.ResumeInboundEmailFunc == nil {
+		panic("seclaimock: EmailAPI.ResumeInboundEmail called with a nil ResumeInboundEmailFunc")
+	}
+	return m.ResumeInboundEmailFunc(ctx)
+}
+
+func (m *EmailAPI) ListEmailDomains(ctx context.Context) (*seclai.EmailDomainsListResponse, error) {
+	if m.ListEmailDomainsFunc == nil {
+		panic("seclaimock: EmailAPI.ListEmailDomains called with a nil ListEmailDomainsFunc")
+	}
+	return m.ListEmailDomainsFunc(ctx)
+}
+
+func (m *EmailAPI) AddEmailDomain(ctx context.Context, body seclai.AddEmailDomainRequest) (*seclai.EmailDomainResponse, error) {
+	if m.AddEmailDomainFunc == nil {
+		panic("seclaimock: EmailAPI.AddEmailDomain called with a nil AddEmailDomainFunc")
+	}
+	return m.AddEmailDomainFunc(ctx, body)
+}
+
+func (m *EmailAPI) RemoveEmailDomain(ctx context.Context, domainID string) (*seclai.RemoveEmailDomainResponse, error) {
+	if m.RemoveEmailDomainFunc == nil {
+		panic("seclaimock: EmailAPI.RemoveEmailDomain called with a nil RemoveEmailDomainFunc")
+	}
+	return m.RemoveEmailDomainFunc(ctx, domainID)
+}
+
+func (m *EmailAPI) VerifyEmailDomain(ctx context.Context, domainID string) (*seclai.EmailDomainResponse, error) {
+	if m.VerifyEmailDomainFunc == nil {
+		panic("seclaimock: EmailAPI.VerifyEmailDomain called with a nil VerifyEmailDomainFunc")
+	}
+	return m.VerifyEmailDomainFunc(ctx, domainID)
+}
+
+func (m *EmailAPI) SetPrimaryEmailDomain(ctx context.Context, domainID string) (*seclai.EmailDomainResponse, error) {
+	if m.SetPrimaryEmailDomainFunc == nil {
+		panic("seclaimock: EmailAPI.SetPrimaryEmailDomain called with a nil SetPrimaryEmailDomainFunc")
+	}
+	return m.SetPrimaryEmailDomainFunc(ctx, domainID)
+}
+
+func (m *EmailAPI) UseSharedEmailDomain(ctx context.Context) error {
+	if m.UseSharedEmailDomainFunc == nil {
+		panic("seclaimock: EmailAPI.UseSharedEmailDomain called with a nil UseSharedEmailDomainFunc")
+	}
+	return m.UseSharedEmailDomainFunc(ctx)
+}
+
+func (m *EmailAPI) SendEmailDomainTestEmail(ctx context.Context, domainID string) (*seclai.SendTestEmailResponse, error) {
+	if m.SendEmailDomainTestEmailFunc == nil {
+		panic("seclaimock: EmailAPI.SendEmailDomainTestEmail called with a nil SendEmailDomainTestEmailFunc")
+	}
+	return m.SendEmailDomainTestEmailFunc(ctx, domainID)
+}
+
+func (m *EmailAPI) GetDmarcSummary(ctx context.Context, domainID string, opts seclai.DmarcOptions) (*seclai.DmarcSummaryResponse, error) {
+	if m.GetDmarcSummaryFunc == nil {
+		panic("seclaimock: EmailAPI.GetDmarcSummary called with a nil GetDmarcSummaryFunc")
+	}
+	return m.GetDmarcSummaryFunc(ctx, domainID, opts)
+}
+
+// SearchAPI is a mock of [seclai.SearchAPI].
+type SearchAPI struct {
+	SearchFunc     func(ctx context.Context, opts seclai.SearchOptions) (json.RawMessage, error)
+	SearchDocsFunc func(ctx context.Context, opts seclai.DocsSearchOptions) (json.RawMessage, error)
+}
+
+var _ seclai.SearchAPI = (*SearchAPI)(nil)
+
+func (m *SearchAPI) Search(ctx context.Context, opts seclai.SearchOptions) (json.RawMessage, error) {
+	if m.SearchFunc == nil {
+		panic("seclaimock: SearchAPI.Search called with a nil SearchFunc")
+	}
+	return m.SearchFunc(ctx, opts)
+}
+
+func (m *SearchAPI) SearchDocs(ctx context.Context, opts seclai.DocsSearchOptions) (json.RawMessage, error) {
+	if m.SearchDocsFunc == nil {
+		panic("seclaimock: SearchAPI.SearchDocs called with a nil SearchDocsFunc")
+	}
+	return m.SearchDocsFunc(ctx, opts)
+}
+
+// AIAssistantAPI is a mock of [seclai.AIAssistantAPI].
+type AIAssistantAPI struct {
+	AiAssistantKnowledgeBaseFunc        func(ctx context.Context, body seclai.AiAssistantGenerateRequest) (*seclai.AiAssistantGenerateResponse, error)
+	AiAssistantSourceFunc               func(ctx context.Context, body seclai.AiAssistantGenerateRequest) (*seclai.AiAssistantGenerateResponse, error)
+	AiAssistantSolutionFunc             func(ctx context.Context, body seclai.AiAssistantGenerateRequest) (*seclai.AiAssistantGenerateResponse, error)
+	AiAssistantMemoryBankFunc           func(ctx context.Context, body seclai.MemoryBankAiAssistantRequest) (*seclai.MemoryBankAiAssistantResponse, error)
+	GetAiAssistantMemoryBankHistoryFunc func(ctx context.Context) (*seclai.MemoryBankLastConversationResponse, error)
+	AcceptAiAssistantPlanFunc           func(ctx context.Context, conversationID string, body seclai.AiAssistantAcceptRequest) (*seclai.AiAssistantAcceptResponse, error)
+	DeclineAiAssistantPlanFunc          func(ctx context.Context, conversationID string) error
+	AcceptAiMemoryBankSuggestionFunc    func(ctx context.Context, conversationID string, body seclai.MemoryBankAcceptRequest) (json.RawMessage, error)
+	SubmitAiFeedbackFunc                func(ctx context.Context, body seclai.AiAssistantFeedbackRequest) (*seclai.AiAssistantFeedbackResponse, error)
+}
+
+var _ seclai.AIAssistantAPI = (*AIAssistantAPI)(nil)
+
+func (m *AIAssistantAPI) AiAssistantKnowledgeBase(ctx context.Context, body seclai.AiAssistantGenerateRequest) (*seclai.AiAssistantGenerateResponse, error) {
+	if m.AiAssistantKnowledgeBaseFunc == nil {
+		panic("seclaimock: AIAssistantAPI.AiAssistantKnowledgeBase called with a nil AiAssistantKnowledgeBaseFunc")
+	}
+	return m.AiAssistantKnowledgeBaseFunc(ctx, body)
+}
+
+func (m *AIAssistantAPI) AiAssistantSource(ctx context.Context, body seclai.AiAssistantGenerateRequest) (*seclai.AiAssistantGenerateResponse, error) {
+	if m.AiAssistantSourceFunc == nil {
+		panic("seclaimock: AIAssistantAPI.AiAssistantSource called with a nil AiAssistantSourceFunc")
+	}
+	return m.AiAssistantSourceFunc(ctx, body)
+}
+
+func (m *AIAssistantAPI) AiAssistantSolution(ctx context.Context, body seclai.AiAssistantGenerateRequest) (*seclai.AiAssistantGenerateResponse, error) {
+	if m.AiAssistantSolutionFunc == nil {
+		panic("seclaimock: AIAssistantAPI.AiAssistantSolution called with a nil AiAssistantSolutionFunc")
+	}
+	return m.AiAssistantSolutionFunc(ctx, body)
+}
+
+func (m *AIAssistantAPI) AiAssistantMemoryBank(ctx context.Context, body seclai.MemoryBankAiAssistantRequest) (*seclai.MemoryBankAiAssistantResponse, error) {
+	if m.AiAssistantMemoryBankFunc == nil {
+		panic("seclaimock: AIAssistantAPI.AiAssistantMemoryBank called with a nil AiAssistantMemoryBankFunc")
+	}
+	return m.AiAssistantMemoryBankFunc(ctx, body)
+}
+
+func (m *AIAssistantAPI) GetAiAssistantMemoryBankHistory(ctx context.Context) (*seclai.MemoryBankLastConversationResponse, error) {
+	if m.GetAiAssistantMemoryBankHistoryFunc == nil {
+		panic("seclaimock: AIAssistantAPI.GetAiAssistantMemoryBankHistory called with a nil GetAiAssistantMemoryBankHistoryFunc")
+	}
+	return m.GetAiAssistantMemoryBankHistoryFunc(ctx)
+}
+
+func (m *AIAssistantAPI) AcceptAiAssistantPlan(ctx context.Context, conversationID string, body seclai.AiAssistantAcceptRequest) (*seclai.AiAssistantAcceptResponse, error) {
+	if m.AcceptAiAssistantPlanFunc == nil {
+		panic("seclaimock: AIAssistantAPI.AcceptAiAssistantPlan called with a nil AcceptAiAssistantPlanFunc")
+	}
+	return m.AcceptAiAssistantPlanFunc(ctx, conversationID, body)
+}
+
+func (m *AIAssistantAPI) DeclineAiAssistantPlan(ctx context.Context, conversationID string) error {
+	if m.DeclineAiAssistantPlanFunc == nil {
+		panic("seclaimock: AIAssistantAPI.DeclineAiAssistantPlan called with a nil DeclineAiAssistantPlanFunc")
+	}
+	return m.DeclineAiAssistantPlanFunc(ctx, conversationID)
+}
+
+func (m *AIAssistantAPI) AcceptAiMemoryBankSuggestion(ctx context.Context, conversationID string, body seclai.MemoryBankAcceptRequest) (json.RawMessage, error) {
+	if m.AcceptAiMemoryBankSuggestionFunc == nil {
+		panic("seclaimock: AIAssistantAPI.AcceptAiMemoryBankSuggestion called with a nil AcceptAiMemoryBankSuggestionFunc")
+	}
+	return m.AcceptAiMemoryBankSuggestionFunc(ctx, conversationID, body)
+}
+
+func (m *AIAssistantAPI) SubmitAiFeedback(ctx context.Context, body seclai.AiAssistantFeedbackRequest) (*seclai.AiAssistantFeedbackResponse, error) {
+	if m.SubmitAiFeedbackFunc == nil {
+		panic("seclaimock: AIAssistantAPI.SubmitAiFeedback called with a nil SubmitAiFeedbackFunc")
+	}
+	return m.SubmitAiFeedbackFunc(ctx, body)
+}
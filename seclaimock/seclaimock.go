@@ -0,0 +1,25 @@
+// Package seclaimock provides function-field mocks of the seclai package's
+// interfaces, for unit tests of code that accepts a [seclai.API] or one of
+// its grouped interfaces such as [seclai.AgentsAPI].
+//
+// Set the field for each method the code under test calls; calling a method
+// whose field is nil panics, naming it:
+//
+//	runs := &seclaimock.RunsAPI{
+//		RunAgentAndPollFunc: func(ctx context.Context, agentID string, body seclai.AgentRunRequest, opts *seclai.RunAgentAndPollOptions) (*seclai.AgentRunResponse, error) {
+//			return &seclai.AgentRunResponse{Status: "completed"}, nil
+//		},
+//	}
+//
+// [Client] mocks the whole of [seclai.API] and embeds one mock per group, so
+// its fields are set through them:
+//
+//	c := &seclaimock.Client{}
+//	c.GetMeFunc = func(context.Context) (*seclai.MeResponse, error) { return &seclai.MeResponse{}, nil }
+//
+// For tests that should exercise HTTP, retries and decoding as well, use the
+// seclaitest package instead. The mocks are generated by cmd/mockgen from the
+// interfaces in api.go; run go generate after changing them.
+package seclaimock
+
+//go:generate go run ../cmd/mockgen -dir .. -out mock_gen.go
@@ -0,0 +1,50 @@
+package seclaimock
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	seclai "github.com/seclai/seclai-go"
+)
+
+// summarize stands in for consumer code that depends on a narrow interface.
+func summarize(ctx context.Context, runs seclai.RunsAPI, agentID string) (string, error) {
+	run, err := runs.RunAgentAndPoll(ctx, agentID, seclai.AgentRunRequest{}, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(run.Status), nil
+}
+
+func TestRunsAPI_CallsTheFuncField(t *testing.T) {
+	var gotAgent string
+	runs := &RunsAPI{
+		RunAgentAndPollFunc: func(ctx context.Context, agentID string, body seclai.AgentRunRequest, opts *seclai.RunAgentAndPollOptions) (*seclai.AgentRunResponse, error) {
+			gotAgent = agentID
+			return &seclai.AgentRunResponse{Status: "completed"}, nil
+		},
+	}
+	status, err := summarize(context.Background(), runs, "a_1")
+	if err != nil || status != "completed" || gotAgent != "a_1" {
+		t.Fatalf("summarize = %q, %v (agent %q)", status, err, gotAgent)
+	}
+}
+
+func TestClient_EmbedsEveryGroup(t *testing.T) {
+	c := &Client{}
+	c.GetMeFunc = func(context.Context) (*seclai.MeResponse, error) { return &seclai.MeResponse{}, nil }
+
+	var api seclai.API = c
+	if _, err := api.GetMe(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		msg, _ := recover().(string)
+		if !strings.Contains(msg, "AgentsAPI.GetAgent") || !strings.Contains(msg, "GetAgentFunc") {
+			t.Fatalf("a nil field should panic naming it, got %q", msg)
+		}
+	}()
+	_, _ = api.GetAgent(context.Background(), "a_1")
+}
@@ -0,0 +1,463 @@
+package seclaitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	seclai "github.com/seclai/seclai-go"
+	"github.com/seclai/seclai-go/generated"
+)
+
+type agent struct {
+	resp       seclai.AgentSummaryResponse
+	definition map[string]any
+	changeID   string
+	script     *RunScript
+}
+
+type run struct {
+	agentID  string
+	resp     seclai.AgentRunResponse
+	statuses []string
+	step     int
+	output   string
+	interval time.Duration
+}
+
+type inputUpload struct {
+	agentID string
+	resp    seclai.UploadAgentInputApiResponse
+}
+
+// advance moves the run one step along its script and reports whether it
+// moved. Reaching the last status settles the output.
+func (r *run) advance() bool {
+	if r.step >= len(r.statuses)-1 {
+		return false
+	}
+	r.step++
+	r.resp.Status = generated.PendingProcessingCompletedFailedStatus(r.statuses[r.step])
+	if r.step == len(r.statuses)-1 {
+		switch r.resp.Status {
+		case "completed":
+			out := r.output
+			r.resp.Output = &out
+		case "failed":
+			r.resp.ErrorCount = 1
+		}
+	}
+	return true
+}
+
+func (r *run) terminal() bool {
+	return r.step >= len(r.statuses)-1
+}
+
+func (s *Server) agentOr404(c *call) *agent {
+	a := s.agents.get(c.params["agent_id"])
+	if a == nil {
+		writeDetail(c.w, http.StatusNotFound, "Agent not found")
+	}
+	return a
+}
+
+func (s *Server) listAgents(c *call) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items, p := page(c.r, s.agents.list(nil))
+	out := make([]seclai.AgentSummaryResponse, len(items))
+	for i, a := range items {
+		out[i] = a.resp
+	}
+	writeJSON(c.w, http.StatusOK, seclai.AgentListResponse{Data: out, Pagination: p})
+}
+
+func (s *Server) createAgent(c *call) {
+	var body seclai.CreateAgentRequest
+	if !c.decode(&body) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := timestamp()
+	a := &agent{
+		resp: seclai.AgentSummaryResponse{
+			Id:          s.newID(),
+			Name:        body.Name,
+			Description: body.Description,
+			TriggerType: body.TriggerType,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		},
+		definition: map[string]any{"steps": []any{}},
+		changeID:   s.newID(),
+	}
+	if body.AgentDefinition != nil {
+		a.definition = *body.AgentDefinition
+	}
+	s.agents.put(a.resp.Id, a)
+	writeJSON(c.w, http.StatusCreated, a.resp)
+}
+
+func (s *Server) getAgent(c *call) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if a := s.agentOr404(c); a != nil {
+		writeJSON(c.w, http.StatusOK, a.resp)
+	}
+}
+
+func (s *Server) updateAgent(c *call) {
+	var body seclai.UpdateAgentRequest
+	if !c.decode(&body) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a := s.agentOr404(c)
+	if a == nil {
+		return
+	}
+	if body.Name != nil {
+		a.resp.Name = *body.Name
+	}
+	if body.Description != nil {
+		a.resp.Description = body.Description
+	}
+	if body.EvaluationMode != nil {
+		a.resp.EvaluationMode = body.EvaluationMode
+	}
+	if body.MaxRetries != nil {
+		a.resp.MaxRetries = body.MaxRetries
+	}
+	if body.RetryOnFailure != nil {
+		a.resp.RetryOnFailure = body.RetryOnFailure
+	}
+	if body.AgentDefinition != nil {
+		a.definition, a.changeID = *body.AgentDefinition, s.newID()
+	}
+	a.resp.UpdatedAt = timestamp()
+	writeJSON(c.w, http.StatusOK, a.resp)
+}
+
+func (s *Server) deleteAgent(c *call) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.agents.delete(c.params["agent_id"]) {
+		writeDetail(c.w, http.StatusNotFound, "Agent not found")
+		return
+	}
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) setAgentDisabled(disabled bool) handlerFunc {
+	return func(c *call) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		a := s.agentOr404(c)
+		if a == nil {
+			return
+		}
+		a.resp.Disabled = &disabled
+		a.resp.DisabledAt = nil
+		if disabled {
+			now := timestamp()
+			a.resp.DisabledAt = &now
+		}
+		writeJSON(c.w, http.StatusOK, a.resp)
+	}
+}
+
+func (s *Server) getAgentDefinition(c *call) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if a := s.agentOr404(c); a != nil {
+		writeJSON(c.w, http.StatusOK, a.definitionResponse())
+	}
+}
+
+func (s *Server) updateAgentDefinition(c *call) {
+	var body seclai.UpdateAgentDefinitionRequest
+	if !c.decode(&body) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a := s.agentOr404(c)
+	if a == nil {
+		return
+	}
+	if body.ExpectedChangeId != a.changeID {
+		writeDetail(c.w, http.StatusConflict, "Definition was changed by another update; reload it and retry")
+		return
+	}
+	a.definition, a.changeID = body.Definition, s.newID()
+	writeJSON(c.w, http.StatusOK, a.definitionResponse())
+}
+
+func (a *agent) definitionResponse() seclai.AgentDefinitionResponse {
+	return seclai.AgentDefinitionResponse{
+		ChangeId:      a.changeID,
+		Definition:    a.definition,
+		SchemaVersion: "1",
+	}
+}
+
+// startRun creates a run for the agent in c. Callers hold s.mu.
+func (s *Server) startRun(c *call, a *agent, input *string, uploadIDs []string, priority bool) *run {
+	for _, id := range uploadIDs {
+		if u := s.inputs.get(id); u == nil || u.agentID != a.resp.Id {
+			writeDetail(c.w, http.StatusBadRequest, fmt.Sprintf("Input upload %s not found for this agent", id))
+			return nil
+		}
+	}
+	script := a.script
+	if script == nil {
+		script = s.opts.RunScript
+	}
+	statuses := script.statuses()
+	r := &run{
+		agentID:  a.resp.Id,
+		statuses: statuses,
+		resp: seclai.AgentRunResponse{
+			RunId:    s.newID(),
+			Status:   generated.PendingProcessingCompletedFailedStatus(statuses[0]),
+			Input:    input,
+			Priority: priority,
+			Attempts: []seclai.AgentRunAttemptResponse{},
+		},
+	}
+	if script != nil {
+		r.output, r.interval = script.Output, script.Interval
+	}
+	if r.output == "" && input != nil {
+		r.output = *input
+	}
+	// A one-status script starts settled.
+	r.step = -1
+	r.advance()
+	s.runs.put(r.resp.RunId, r)
+	return r
+}
+
+func uploadIDs(one *openapi_types.UUID, many *[]openapi_types.UUID) []string {
+	var ids []string
+	if one != nil {
+		ids = append(ids, one.String())
+	}
+	if many != nil {
+		for _, id := range *many {
+			ids = append(ids, id.String())
+		}
+	}
+	return ids
+}
+
+func (s *Server) runAgent(c *call) {
+	var body seclai.AgentRunRequest
+	if !c.decode(&body) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a := s.agentOr404(c)
+	if a == nil {
+		return
+	}
+	if r := s.startRun(c, a, body.Input, uploadIDs(body.InputUploadId, body.InputUploadIds), body.Priority != nil && *body.Priority); r != nil {
+		writeJSON(c.w, http.StatusOK, r.resp)
+	}
+}
+
+// streamRun starts a run and streams it as server-sent events: init with the
+// first status, update for each status in between, and done with the last.
+func (s *Server) streamRun(c *call) {
+	var body seclai.AgentRunStreamRequest
+	if !c.decode(&body) {
+		return
+	}
+	s.mu.Lock()
+	a := s.agentOr404(c)
+	var r *run
+	if a != nil {
+		r = s.startRun(c, a, body.Input, uploadIDs(body.InputUploadId, body.InputUploadIds), true)
+	}
+	var first seclai.AgentRunResponse
+	if r != nil {
+		first = r.resp
+	}
+	s.mu.Unlock()
+	if r == nil {
+		return
+	}
+
+	c.w.Header().Set("Content-Type", "text/event-stream")
+	c.w.Header().Set("Cache-Control", "no-cache")
+	c.w.WriteHeader(http.StatusOK)
+	flusher, _ := c.w.(http.Flusher)
+	emit := func(event string, resp seclai.AgentRunResponse) {
+		data, _ := json.Marshal(resp)
+		fmt.Fprintf(c.w, "event: %s\ndata: %s\n\n", event, data)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	emit("init", first)
+	for {
+		if !sleep(c.r, r.interval) {
+			return
+		}
+		s.mu.Lock()
+		r.advance()
+		resp, done := r.resp, r.terminal()
+		s.mu.Unlock()
+		if done {
+			emit("done", resp)
+			return
+		}
+		emit("update", resp)
+	}
+}
+
+func (s *Server) listAgentRuns(c *call) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.agentOr404(c) == nil {
+		return
+	}
+	status := c.r.URL.Query().Get("status")
+	rows := s.runs.list(func(r *run) bool {
+		return r.agentID == c.params["agent_id"] && (status == "" || string(r.resp.Status) == status)
+	})
+	slices.Reverse(rows)
+	items, p := page(c.r, rows)
+	out := make([]seclai.AgentRunResponse, len(items))
+	for i, r := range items {
+		out[i] = r.resp
+	}
+	writeJSON(c.w, http.StatusOK, seclai.AgentRunListResponse{Data: out, Pagination: p})
+}
+
+// getRun moves the run one step along its script and answers with it, so
+// successive polls walk through every status.
+func (s *Server) getRun(c *call) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.runs.get(c.params["run_id"])
+	if r == nil {
+		writeDetail(c.w, http.StatusNotFound, "Run not found")
+		return
+	}
+	r.advance()
+	writeJSON(c.w, http.StatusOK, r.resp)
+}
+
+// cancelRun fails a run that has not finished; a finished run is returned as is.
+func (s *Server) cancelRun(c *call) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.runs.get(c.params["run_id"])
+	if r == nil {
+		writeDetail(c.w, http.StatusNotFound, "Run not found")
+		return
+	}
+	if !r.terminal() {
+		r.statuses = append(r.statuses[:r.step+1:r.step+1], "failed")
+		r.advance()
+	}
+	writeJSON(c.w, http.StatusOK, r.resp)
+}
+
+func (s *Server) uploadAgentInput(c *call) {
+	file, _, err := readForm(c)
+	if err != nil {
+		writeDetail(c.w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a := s.agentOr404(c)
+	if a == nil {
+		return
+	}
+	u := &inputUpload{agentID: a.resp.Id, resp: seclai.UploadAgentInputApiResponse{
+		Id:          s.newID(),
+		Status:      "ready",
+		Filename:    file.Filename,
+		ContentType: file.ContentType,
+		FileSize:    len(file.Data),
+	}}
+	s.inputs.put(u.resp.Id, u)
+	writeJSON(c.w, http.StatusAccepted, u.resp)
+}
+
+func (s *Server) getAgentInputUpload(c *call) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u := s.inputs.get(c.params["upload_id"])
+	if u == nil || u.agentID != c.params["agent_id"] {
+		writeDetail(c.w, http.StatusNotFound, "Upload not found")
+		return
+	}
+	writeJSON(c.w, http.StatusOK, u.resp)
+}
+
+// readForm parses a multipart upload into its file part and other fields.
+func readForm(c *call) (Upload, map[string]string, error) {
+	var file Upload
+	_, params, err := mime.ParseMediaType(c.r.Header.Get("Content-Type"))
+	if err != nil {
+		return file, nil, fmt.Errorf("expected a multipart/form-data body: %w", err)
+	}
+	fields := map[string]string{}
+	mr := multipart.NewReader(strings.NewReader(string(c.body)), params["boundary"])
+	found := false
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return file, nil, fmt.Errorf("malformed multipart body: %w", err)
+		}
+		data, err := io.ReadAll(part)
+		if err != nil {
+			return file, nil, err
+		}
+		if part.FormName() == "file" {
+			found = true
+			file.Filename = part.FileName()
+			file.ContentType = part.Header.Get("Content-Type")
+			file.Data = data
+			continue
+		}
+		fields[part.FormName()] = string(data)
+	}
+	if !found {
+		return file, nil, fmt.Errorf("missing file part")
+	}
+	if file.ContentType == "" || file.ContentType == "application/octet-stream" {
+		if ct := mime.TypeByExtension(fileExt(file.Filename)); ct != "" {
+			file.ContentType = ct
+		}
+	}
+	file.Title, file.Metadata = fields["title"], fields["metadata"]
+	return file, fields, nil
+}
+
+func fileExt(name string) string {
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		return name[i:]
+	}
+	return ""
+}
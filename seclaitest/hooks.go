@@ -0,0 +1,104 @@
+package seclaitest
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// A Hook sees every request before the server handles it, along with the
+// OpenAPI path template it matched ("" if none). Returning nil lets the
+// request through; returning a [Fault] applies it.
+//
+// Hooks run in the order they were added, and may run concurrently for
+// concurrent requests.
+type Hook func(r *http.Request, route string) *Fault
+
+// Fault is a failure or delay injected by a [Hook].
+type Fault struct {
+	// Delay holds the request before it is answered. With no Status and no
+	// Disconnect, the request is then handled normally.
+	Delay time.Duration
+
+	// Status answers the request with this status instead of handling it.
+	Status int
+
+	// Body is the response body. Defaults to {"detail": "<status text>"}.
+	Body string
+
+	// Header is added to the response.
+	Header http.Header
+
+	// Disconnect drops the connection without answering.
+	Disconnect bool
+}
+
+func (f *Fault) write(w http.ResponseWriter) {
+	if f.Disconnect {
+		if hj, ok := w.(http.Hijacker); ok {
+			if conn, _, err := hj.Hijack(); err == nil {
+				conn.Close()
+				return
+			}
+		}
+		panic(http.ErrAbortHandler)
+	}
+	for k, vs := range f.Header {
+		for _, v := range vs {
+			w.Header().Add(k, v)
+		}
+	}
+	if f.Body == "" {
+		writeDetail(w, f.Status, http.StatusText(f.Status))
+		return
+	}
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(f.Status)
+	_, _ = w.Write([]byte(f.Body))
+}
+
+// AddHook installs h for every later request.
+func (s *Server) AddHook(h Hook) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hooks = append(s.hooks, h)
+}
+
+// FailNext answers the next n requests for method and route with status.
+// route is an OpenAPI path template such as "/agents/{agent_id}"; an empty
+// method or route matches any.
+func (s *Server) FailNext(method, route string, status, n int) {
+	s.Inject(method, route, n, Fault{Status: status})
+}
+
+// Delay holds every request for method and route for d before handling it.
+// An empty method or route matches any.
+func (s *Server) Delay(method, route string, d time.Duration) {
+	s.Inject(method, route, 0, Fault{Delay: d})
+}
+
+// Inject applies f to the next n requests for method and route, or to all of
+// them when n is 0 or less. An empty method or route matches any.
+func (s *Server) Inject(method, route string, n int, f Fault) {
+	var (
+		mu        sync.Mutex
+		remaining = n
+	)
+	s.AddHook(func(r *http.Request, rt string) *Fault {
+		if (method != "" && r.Method != method) || (route != "" && rt != route) {
+			return nil
+		}
+		if n > 0 {
+			mu.Lock()
+			defer mu.Unlock()
+			if remaining == 0 {
+				return nil
+			}
+			remaining--
+		}
+		fault := f
+		return &fault
+	})
+}
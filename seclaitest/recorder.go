@@ -0,0 +1,392 @@
+package seclaitest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Mode selects whether a [Recorder] talks to the network.
+type Mode int
+
+const (
+	// ModeReplay serves every request from the cassette and never touches
+	// the network. A request with no recorded match fails.
+	ModeReplay Mode = iota
+
+	// ModeRecord sends every request upstream and records it, replacing the
+	// cassette on [Recorder.Stop].
+	ModeRecord
+
+	// ModeReplayOrRecord replays when the cassette exists and records it
+	// when it does not, so the first run against the real API creates it.
+	ModeReplayOrRecord
+)
+
+// RecorderOptions configure a [Recorder]. The zero value is ready to use.
+type RecorderOptions struct {
+	// Transport sends requests upstream while recording. Defaults to
+	// [http.DefaultTransport].
+	Transport http.RoundTripper
+
+	// ScrubHeaders names additional headers to redact, on requests and
+	// responses, beyond the credentials always redacted: Authorization,
+	// Proxy-Authorization, X-Api-Key, Cookie and Set-Cookie.
+	ScrubHeaders []string
+
+	// Scrub runs on every interaction before it is saved, to remove secrets
+	// from URLs or bodies.
+	Scrub func(*Interaction)
+}
+
+// Cassette is the file a [Recorder] saves: the interactions in the order
+// they happened.
+type Cassette struct {
+	Version      int            `json:"version"`
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded request and its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the request half of an [Interaction].
+type RecordedRequest struct {
+	Method string `json:"method"`
+	// URL is the full request URL.
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body,omitempty"`
+}
+
+// RecordedResponse is the response half of an [Interaction]. A streamed
+// response, such as a run's server-sent events, is recorded whole.
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       Body        `json:"body,omitempty"`
+}
+
+// Body is a recorded body. It is saved as text when it is valid UTF-8 and as
+// base64 otherwise, so cassettes of JSON and SSE traffic stay readable.
+type Body []byte
+
+// MarshalJSON implements [json.Marshaler].
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(string(b))
+	}
+	return json.Marshal(map[string]string{"base64": base64.StdEncoding.EncodeToString(b)})
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var s string
+	if json.Unmarshal(data, &s) == nil {
+		*b = Body(s)
+		return nil
+	}
+	var enc struct{ Base64 string }
+	if err := json.Unmarshal(data, &enc); err != nil {
+		return err
+	}
+	raw, err := base64.StdEncoding.DecodeString(enc.Base64)
+	*b = raw
+	return err
+}
+
+// ErrNoInteraction is returned, wrapped, when a replaying [Recorder] has no
+// recorded response left for a request.
+var ErrNoInteraction = errors.New("seclaitest: no recorded interaction matches the request")
+
+// Recorder is an [http.RoundTripper] that records traffic to a cassette file
+// and replays it. Give it to a client through [seclai.Options.HTTPClient]:
+//
+//	rec, err := seclaitest.NewRecorder("testdata/run.json", seclaitest.ModeReplayOrRecord, nil)
+//	defer rec.Stop()
+//	client, err := seclai.NewClient(seclai.Options{HTTPClient: rec.HTTPClient()})
+//
+// Replay matches requests on method, path, query and body, ignoring the
+// host, so a cassette recorded against one base URL replays against any.
+// JSON bodies match regardless of key order and whitespace, form and
+// multipart bodies regardless of field order and boundary. Each recorded
+// interaction is served once, in recorded order, so a run polled until it
+// completes replays its statuses in sequence.
+type Recorder struct {
+	path  string
+	mode  Mode
+	opts  RecorderOptions
+	scrub map[string]bool
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	used         []bool
+}
+
+// NewRecorder opens the cassette at path. In [ModeReplay] the cassette must
+// exist. opts may be nil.
+func NewRecorder(path string, mode Mode, opts *RecorderOptions) (*Recorder, error) {
+	r := &Recorder{path: path, mode: mode, scrub: map[string]bool{}}
+	if opts != nil {
+		r.opts = *opts
+	}
+	for _, h := range append([]string{"Authorization", "Proxy-Authorization", "X-Api-Key", "Cookie", "Set-Cookie"}, r.opts.ScrubHeaders...) {
+		r.scrub[http.CanonicalHeaderKey(h)] = true
+	}
+
+	raw, err := os.ReadFile(path)
+	switch {
+	case err == nil && mode != ModeRecord:
+		var c Cassette
+		if err := json.Unmarshal(raw, &c); err != nil {
+			return nil, fmt.Errorf("seclaitest: read cassette %s: %w", path, err)
+		}
+		r.mode = ModeReplay
+		r.interactions = c.Interactions
+		r.used = make([]bool, len(c.Interactions))
+	case errors.Is(err, os.ErrNotExist) && mode == ModeReplayOrRecord:
+		r.mode = ModeRecord
+	case err != nil && mode == ModeReplay:
+		return nil, fmt.Errorf("seclaitest: open cassette: %w", err)
+	case mode == ModeReplayOrRecord:
+		return nil, fmt.Errorf("seclaitest: open cassette: %w", err)
+	}
+	return r, nil
+}
+
+// Recording reports whether the recorder is sending requests upstream.
+func (r *Recorder) Recording() bool {
+	return r.mode == ModeRecord
+}
+
+// HTTPClient returns an [http.Client] that sends through the recorder.
+func (r *Recorder) HTTPClient() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip implements [http.RoundTripper].
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+	}
+	if r.mode == ModeRecord {
+		return r.record(req, body)
+	}
+	return r.replay(req, body)
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	key := matchKey(req.Method, req.URL, req.Header.Get("Content-Type"), body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, in := range r.interactions {
+		if r.used[i] {
+			continue
+		}
+		u, err := url.Parse(in.Request.URL)
+		if err != nil {
+			continue
+		}
+		if matchKey(in.Request.Method, u, in.Request.Header.Get("Content-Type"), in.Request.Body) != key {
+			continue
+		}
+		r.used[i] = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(in.Response.Body)),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, req.Method, req.URL.RequestURI())
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	in := &Interaction{Request: RecordedRequest{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Header: r.scrubbed(req.Header),
+		Body:   body,
+	}}
+	// Reserve the slot now so interactions keep the order requests were
+	// sent in, even when a streamed body finishes later.
+	r.mu.Lock()
+	r.interactions = append(r.interactions, in)
+	r.mu.Unlock()
+
+	out := req.Clone(req.Context())
+	if body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	transport := r.opts.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(out)
+	if err != nil {
+		r.mu.Lock()
+		r.interactions = slicesDelete(r.interactions, in)
+		r.mu.Unlock()
+		return nil, err
+	}
+	r.mu.Lock()
+	in.Response = RecordedResponse{StatusCode: resp.StatusCode, Header: r.scrubbed(resp.Header)}
+	r.mu.Unlock()
+	resp.Body = &recordingBody{ReadCloser: resp.Body, r: r, in: in}
+	return resp, nil
+}
+
+func slicesDelete(ins []*Interaction, in *Interaction) []*Interaction {
+	for i, x := range ins {
+		if x == in {
+			return append(ins[:i], ins[i+1:]...)
+		}
+	}
+	return ins
+}
+
+// recordingBody copies a response body into its interaction as the caller
+// reads it, so streams are recorded without being buffered ahead.
+type recordingBody struct {
+	io.ReadCloser
+	r  *Recorder
+	in *Interaction
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		b.r.mu.Lock()
+		b.in.Response.Body = append(b.in.Response.Body, p[:n]...)
+		b.r.mu.Unlock()
+	}
+	return n, err
+}
+
+func (r *Recorder) scrubbed(h http.Header) http.Header {
+	out := h.Clone()
+	for k := range out {
+		if r.scrub[http.CanonicalHeaderKey(k)] {
+			out[k] = []string{"[REDACTED]"}
+		}
+	}
+	return out
+}
+
+// Stop saves the cassette when recording and reports an error if any
+// replayed interaction went unused, which usually means the code under test
+// has stopped making a request it used to. Recording writes the file
+// atomically, creating its directory.
+func (r *Recorder) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.mode != ModeRecord {
+		var unused []string
+		for i, in := range r.interactions {
+			if !r.used[i] {
+				unused = append(unused, in.Request.Method+" "+in.Request.URL)
+			}
+		}
+		if len(unused) > 0 {
+			return fmt.Errorf("seclaitest: %d recorded interactions were not replayed: %s", len(unused), strings.Join(unused, ", "))
+		}
+		return nil
+	}
+
+	c := Cassette{Version: 1, Interactions: r.interactions}
+	if r.opts.Scrub != nil {
+		for _, in := range c.Interactions {
+			r.opts.Scrub(in)
+		}
+	}
+	raw, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(r.path), filepath.Base(r.path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(append(raw, '\n')); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), r.path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// matchKey reduces a request to what replay matches on.
+func matchKey(method string, u *url.URL, contentType string, body []byte) string {
+	return method + " " + u.EscapedPath() + "?" + u.Query().Encode() + "\n" + normalizeBody(contentType, body)
+}
+
+// normalizeBody renders a body so that encodings of the same content compare
+// equal: JSON without regard to key order or whitespace, forms without
+// regard to field order, multipart without regard to boundary or part order.
+func normalizeBody(contentType string, body []byte) string {
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		var v any
+		if json.Unmarshal(body, &v) == nil {
+			out, _ := json.Marshal(v)
+			return string(out)
+		}
+	case mediaType == "application/x-www-form-urlencoded":
+		if q, err := url.ParseQuery(string(body)); err == nil {
+			return q.Encode()
+		}
+	case strings.HasPrefix(mediaType, "multipart/"):
+		var parts []string
+		mr := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+		for {
+			p, err := mr.NextPart()
+			if err == io.EOF {
+				sort.Strings(parts)
+				return strings.Join(parts, "\n")
+			}
+			if err != nil {
+				break
+			}
+			data, _ := io.ReadAll(p)
+			parts = append(parts, fmt.Sprintf("%s;%s;%s;%s", p.FormName(), p.FileName(), p.Header.Get("Content-Type"), normalizeBody(p.Header.Get("Content-Type"), data)))
+		}
+	}
+	return string(body)
+}
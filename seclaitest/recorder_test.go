@@ -0,0 +1,128 @@
+package seclaitest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	seclai "github.com/seclai/seclai-go"
+)
+
+// exercise makes the calls a typical integration test would: a polled run, a
+// streamed run and a multipart upload.
+func exercise(t *testing.T, c *seclai.Client) {
+	t.Helper()
+	ctx := context.Background()
+	agent, err := c.CreateAgent(ctx, seclai.CreateAgentRequest{Name: "triage"})
+	if err != nil {
+		t.Fatalf("CreateAgent: %v", err)
+	}
+	run, err := c.RunAgentAndPoll(ctx, agent.Id, seclai.AgentRunRequest{Input: ptr("hi")}, &seclai.RunAgentAndPollOptions{PollInterval: time.Millisecond})
+	if err != nil || run.Status != "completed" || run.Output == nil || *run.Output != "hi" {
+		t.Fatalf("RunAgentAndPoll = %+v, %v", run, err)
+	}
+
+	events, errs := c.RunStreamingAgent(ctx, agent.Id, seclai.AgentRunStreamRequest{Input: ptr("q")})
+	var names []string
+	for evt := range events {
+		names = append(names, evt.Event)
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	if got := strings.Join(names, ","); got != "init,update,done" {
+		t.Fatalf("events %s", got)
+	}
+
+	src, err := c.CreateSource(ctx, seclai.CreateSourceBody{Name: "docs", SourceType: "file_upload"})
+	if err != nil {
+		t.Fatalf("CreateSource: %v", err)
+	}
+	if _, err := c.UploadFileToSource(ctx, src.Id, seclai.UploadFileRequest{File: []byte("# Hi"), FileName: "a.md", Title: "A", Metadata: map[string]any{"k": "v"}}); err != nil {
+		t.Fatalf("UploadFileToSource: %v", err)
+	}
+}
+
+func TestRecorder_RecordThenReplayOffline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassettes", "run.json")
+
+	srv := NewServer(t, nil)
+	rec, err := NewRecorder(path, ModeReplayOrRecord, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rec.Recording() {
+		t.Fatal("a missing cassette should be recorded")
+	}
+	exercise(t, srv.Client(seclai.Options{APIKey: "sk-live-secret", HTTPClient: rec.HTTPClient()}))
+	if err := rec.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	recorded := len(srv.Requests())
+	srv.Close()
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(raw), "sk-live-secret") || !strings.Contains(string(raw), "[REDACTED]") {
+		t.Fatal("the cassette should not contain the API key")
+	}
+	if !strings.Contains(string(raw), "event: done") {
+		t.Fatal("the cassette should contain the streamed events")
+	}
+
+	rec, err = NewRecorder(path, ModeReplayOrRecord, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Recording() {
+		t.Fatal("an existing cassette should be replayed")
+	}
+	c, err := seclai.NewClient(seclai.Options{APIKey: "other", BaseURL: "https://seclai.invalid", HTTPClient: rec.HTTPClient()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exercise(t, c)
+	if err := rec.Stop(); err != nil {
+		t.Fatalf("every one of the %d recorded interactions should replay: %v", recorded, err)
+	}
+}
+
+func TestRecorder_ReplayMatchesNormalizedBodies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "c.json")
+	cassette := `{"version":1,"interactions":[{
+		"request":{"method":"POST","url":"https://api.seclai.com/agents?b=2&a=1",
+			"header":{"Content-Type":["application/json"]},"body":"{\"name\": \"x\", \"description\": \"d\"}"},
+		"response":{"status_code":201,"header":{"Content-Type":["application/json"]},"body":{"base64":"/w=="}}}]}`
+	if err := os.WriteFile(path, []byte(cassette), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	rec, err := NewRecorder(path, ModeReplay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, _ := seclai.NewClient(seclai.Options{APIKey: "k", BaseURL: "https://elsewhere.invalid", HTTPClient: rec.HTTPClient()})
+
+	ctx := context.Background()
+	if err := c.Do(ctx, "POST", "/agents", map[string]string{"a": "1", "b": "2"}, map[string]string{"description": "d", "name": "y"}, nil, nil); !errors.Is(err, ErrNoInteraction) {
+		t.Fatalf("a different body should not match, got %v", err)
+	}
+	var out []byte
+	if err := c.Do(ctx, "POST", "/agents", map[string]string{"a": "1", "b": "2"}, map[string]string{"description": "d", "name": "x"}, nil, &out); err == nil {
+		t.Fatal("the recorded body is not JSON, so decoding should fail")
+	} else if errors.Is(err, ErrNoInteraction) {
+		t.Fatalf("the same JSON in a different key order should match: %v", err)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	if _, err := NewRecorder(filepath.Join(t.TempDir(), "missing.json"), ModeReplay, nil); err == nil {
+		t.Fatal("replaying a missing cassette should fail")
+	}
+}
@@ -0,0 +1,411 @@
+package seclaitest
+
+import (
+	"net/http"
+
+	seclai "github.com/seclai/seclai-go"
+)
+
+// routes maps each "METHOD template" the fake implements to its handler.
+// Templates are the OpenAPI paths, so a typo here cannot go unnoticed: the
+// routes test checks every key against the spec.
+func (s *Server) routes() map[string]handlerFunc {
+	return map[string]handlerFunc{
+		"GET /agents":                                      s.listAgents,
+		"POST /agents":                                     s.createAgent,
+		"GET /agents/{agent_id}":                           s.getAgent,
+		"PUT /agents/{agent_id}":                           s.updateAgent,
+		"DELETE /agents/{agent_id}":                        s.deleteAgent,
+		"POST /agents/{agent_id}/disable":                  s.setAgentDisabled(true),
+		"POST /agents/{agent_id}/enable":                   s.setAgentDisabled(false),
+		"GET /agents/{agent_id}/definition":                s.getAgentDefinition,
+		"PUT /agents/{agent_id}/definition":                s.updateAgentDefinition,
+		"GET /agents/{agent_id}/runs":                      s.listAgentRuns,
+		"POST /agents/{agent_id}/runs":                     s.runAgent,
+		"POST /agents/{agent_id}/runs/stream":              s.streamRun,
+		"GET /agents/runs/{run_id}":                        s.getRun,
+		"DELETE /agents/runs/{run_id}":                     s.cancelRun,
+		"POST /agents/{agent_id}/upload-input":             s.uploadAgentInput,
+		"GET /agents/{agent_id}/input-uploads/{upload_id}": s.getAgentInputUpload,
+
+		"GET /sources":                                                     s.listSources,
+		"POST /sources":                                                    s.createSource,
+		"GET /sources/{source_connection_id}":                              s.getSource,
+		"PUT /sources/{source_connection_id}":                              s.updateSource,
+		"DELETE /sources/{source_connection_id}":                           s.deleteSource,
+		"POST /sources/{source_connection_id}":                             s.uploadInlineText,
+		"POST /sources/{source_connection_id}/upload":                      s.uploadToSource,
+		"GET /sources/{source_connection_id}/exports":                      s.listExports,
+		"POST /sources/{source_connection_id}/exports":                     s.createExport,
+		"GET /sources/{source_connection_id}/exports/{export_id}":          s.getExport,
+		"DELETE /sources/{source_connection_id}/exports/{export_id}":       s.deleteExport,
+		"POST /sources/{source_connection_id}/exports/{export_id}/cancel":  s.cancelExport,
+		"GET /sources/{source_connection_id}/exports/{export_id}/download": s.downloadExport,
+
+		"GET /knowledge_bases":                        s.listKnowledgeBases,
+		"POST /knowledge_bases":                       s.createKnowledgeBase,
+		"GET /knowledge_bases/{knowledge_base_id}":    s.getKnowledgeBase,
+		"PUT /knowledge_bases/{knowledge_base_id}":    s.updateKnowledgeBase,
+		"DELETE /knowledge_bases/{knowledge_base_id}": s.deleteKnowledgeBase,
+
+		"GET /memory_banks":                     s.listMemoryBanks,
+		"POST /memory_banks":                    s.createMemoryBank,
+		"GET /memory_banks/{memory_bank_id}":    s.getMemoryBank,
+		"PUT /memory_banks/{memory_bank_id}":    s.updateMemoryBank,
+		"DELETE /memory_banks/{memory_bank_id}": s.deleteMemoryBank,
+
+		"GET /alerts":                         s.listAlerts,
+		"GET /alerts/{alert_id}":              s.getAlert,
+		"POST /alerts/{alert_id}/status":      s.changeAlertStatus,
+		"POST /alerts/{alert_id}/comments":    s.addAlertComment,
+		"POST /alerts/{alert_id}/subscribe":   s.setAlertSubscribed(true),
+		"POST /alerts/{alert_id}/unsubscribe": s.setAlertSubscribed(false),
+	}
+}
+
+// ── Knowledge bases ─────────────────────────────────────────────────────────
+
+func (s *Server) knowledgeBaseOr404(c *call) *seclai.KnowledgeBaseResponse {
+	kb := s.kbs.get(c.params["knowledge_base_id"])
+	if kb == nil {
+		writeDetail(c.w, http.StatusNotFound, "Knowledge base not found")
+	}
+	return kb
+}
+
+// listKnowledgeBases answers with the legacy {knowledge_bases, total, page,
+// limit} shape, or the canonical envelope for callers on 2026-07-27 or later.
+func (s *Server) listKnowledgeBases(c *call) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items, p := page(c.r, values(s.kbs.list(nil)))
+	if canonicalEnvelope(c.r) {
+		writeJSON(c.w, http.StatusOK, map[string]any{"data": items, "pagination": p})
+		return
+	}
+	writeJSON(c.w, http.StatusOK, seclai.KnowledgeBaseListResponse{KnowledgeBases: items, Total: p.Total, Page: p.Page, Limit: p.Limit})
+}
+
+// sourceRefs resolves source ids for a knowledge base. Callers hold s.mu.
+func (s *Server) sourceRefs(c *call, ids []string) (*[]seclai.SourceConnectionResponse, bool) {
+	refs := make([]seclai.SourceConnectionResponse, 0, len(ids))
+	for _, id := range ids {
+		src := s.sources.get(id)
+		if src == nil {
+			writeDetail(c.w, http.StatusBadRequest, "Source "+id+" not found")
+			return nil, false
+		}
+		ref := seclai.SourceConnectionResponse{Id: src.resp.Id, Name: src.resp.Name, SourceType: src.resp.SourceType, Polling: src.resp.Polling}
+		if src.resp.Url != nil {
+			ref.Url = *src.resp.Url
+		}
+		refs = append(refs, ref)
+	}
+	return &refs, true
+}
+
+func (s *Server) createKnowledgeBase(c *call) {
+	var body seclai.CreateKnowledgeBaseBody
+	if !c.decode(&body) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	refs, ok := s.sourceRefs(c, body.SourceIds)
+	if !ok {
+		return
+	}
+	now := timestamp()
+	kb := &seclai.KnowledgeBaseResponse{
+		Id:                    s.newID(),
+		Name:                  body.Name,
+		Description:           body.Description,
+		DefaultScoreThreshold: body.DefaultScoreThreshold,
+		DefaultTopK:           body.DefaultTopK,
+		DefaultTopN:           body.DefaultTopN,
+		RerankerModel:         body.RerankerModel,
+		Sources:               refs,
+		CreatedAt:             now,
+		UpdatedAt:             now,
+	}
+	s.kbs.put(kb.Id, kb)
+	writeJSON(c.w, http.StatusCreated, kb)
+}
+
+func (s *Server) getKnowledgeBase(c *call) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if kb := s.knowledgeBaseOr404(c); kb != nil {
+		writeJSON(c.w, http.StatusOK, kb)
+	}
+}
+
+func (s *Server) updateKnowledgeBase(c *call) {
+	var body seclai.UpdateKnowledgeBaseBody
+	if !c.decode(&body) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	kb := s.knowledgeBaseOr404(c)
+	if kb == nil {
+		return
+	}
+	if body.SourceIds != nil {
+		refs, ok := s.sourceRefs(c, *body.SourceIds)
+		if !ok {
+			return
+		}
+		kb.Sources = refs
+	}
+	if body.Name != nil {
+		kb.Name = *body.Name
+	}
+	if body.Description != nil {
+		kb.Description = body.Description
+	}
+	if body.DefaultScoreThreshold != nil {
+		kb.DefaultScoreThreshold = body.DefaultScoreThreshold
+	}
+	if body.DefaultTopK != nil {
+		kb.DefaultTopK = body.DefaultTopK
+	}
+	if body.DefaultTopN != nil {
+		kb.DefaultTopN = body.DefaultTopN
+	}
+	if body.RerankerModel != nil {
+		kb.RerankerModel = body.RerankerModel
+	}
+	kb.UpdatedAt = timestamp()
+	writeJSON(c.w, http.StatusOK, kb)
+}
+
+func (s *Server) deleteKnowledgeBase(c *call) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.kbs.delete(c.params["knowledge_base_id"]) {
+		writeDetail(c.w, http.StatusNotFound, "Knowledge base not found")
+		return
+	}
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+// ── Memory banks ────────────────────────────────────────────────────────────
+
+func (s *Server) memoryBankOr404(c *call) *seclai.MemoryBankResponse {
+	mb := s.mbs.get(c.params["memory_bank_id"])
+	if mb == nil {
+		writeDetail(c.w, http.StatusNotFound, "Memory bank not found")
+	}
+	return mb
+}
+
+// listMemoryBanks answers with the legacy {memory_banks, total, page, limit}
+// shape, or the canonical envelope for callers on 2026-07-27 or later.
+func (s *Server) listMemoryBanks(c *call) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items, p := page(c.r, values(s.mbs.list(nil)))
+	if canonicalEnvelope(c.r) {
+		writeJSON(c.w, http.StatusOK, map[string]any{"data": items, "pagination": p})
+		return
+	}
+	writeJSON(c.w, http.StatusOK, seclai.MemoryBankListResponse{MemoryBanks: items, Total: p.Total, Page: p.Page, Limit: p.Limit})
+}
+
+func (s *Server) createMemoryBank(c *call) {
+	var body seclai.CreateMemoryBankBody
+	if !c.decode(&body) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := timestamp()
+	mb := &seclai.MemoryBankResponse{
+		Id:               s.newID(),
+		Name:             body.Name,
+		Description:      body.Description,
+		Mode:             "fast_and_cheap",
+		Type:             "conversation",
+		ChunkSize:        body.ChunkSize,
+		ChunkOverlap:     body.ChunkOverlap,
+		CompactionPrompt: body.CompactionPrompt,
+		Dimensions:       body.Dimensions,
+		EmbeddingModel:   body.EmbeddingModel,
+		MaxAgeDays:       body.MaxAgeDays,
+		MaxSizeTokens:    body.MaxSizeTokens,
+		MaxTurns:         body.MaxTurns,
+		RetentionDays:    body.RetentionDays,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if body.Mode != nil {
+		mb.Mode = *body.Mode
+	}
+	if body.Type != nil {
+		mb.Type = *body.Type
+	}
+	s.mbs.put(mb.Id, mb)
+	writeJSON(c.w, http.StatusCreated, mb)
+}
+
+func (s *Server) getMemoryBank(c *call) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if mb := s.memoryBankOr404(c); mb != nil {
+		writeJSON(c.w, http.StatusOK, mb)
+	}
+}
+
+func (s *Server) updateMemoryBank(c *call) {
+	var body seclai.UpdateMemoryBankBody
+	if !c.decode(&body) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	mb := s.memoryBankOr404(c)
+	if mb == nil {
+		return
+	}
+	if body.Name != nil {
+		mb.Name = *body.Name
+	}
+	if body.Description != nil {
+		mb.Description = body.Description
+	}
+	if body.CompactionPrompt != nil {
+		mb.CompactionPrompt = body.CompactionPrompt
+	}
+	if body.MaxAgeDays != nil {
+		mb.MaxAgeDays = body.MaxAgeDays
+	}
+	if body.MaxSizeTokens != nil {
+		mb.MaxSizeTokens = body.MaxSizeTokens
+	}
+	if body.MaxTurns != nil {
+		mb.MaxTurns = body.MaxTurns
+	}
+	if body.RetentionDays != nil {
+		mb.RetentionDays = body.RetentionDays
+	}
+	mb.UpdatedAt = timestamp()
+	writeJSON(c.w, http.StatusOK, mb)
+}
+
+func (s *Server) deleteMemoryBank(c *call) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.mbs.delete(c.params["memory_bank_id"]) {
+		writeDetail(c.w, http.StatusNotFound, "Memory bank not found")
+		return
+	}
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+// ── Alerts ──────────────────────────────────────────────────────────────────
+
+// testUserID is the user the fake acts as for comments and subscriptions.
+const testUserID = "00000000-0000-4000-8000-0000000000ff"
+
+func (s *Server) alertOr404(c *call) *seclai.AlertDetailResponse {
+	a := s.alerts.get(c.params["alert_id"])
+	if a == nil {
+		writeDetail(c.w, http.StatusNotFound, "Alert not found")
+	}
+	return a
+}
+
+func (s *Server) listAlerts(c *call) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	q := c.r.URL.Query()
+	match := func(want string, got *string) bool {
+		return want == "" || (got != nil && *got == want)
+	}
+	rows := s.alerts.list(func(a *seclai.AlertDetailResponse) bool {
+		return (q.Get("status") == "" || a.Alert.Status == q.Get("status")) &&
+			match(q.Get("agent_id"), a.Alert.AgentId) &&
+			match(q.Get("source_connection_id"), a.Alert.SourceConnectionId)
+	})
+	items, p := page(c.r, rows)
+	out := make([]seclai.AlertResponse, len(items))
+	for i, a := range items {
+		out[i] = a.Alert
+	}
+	writeJSON(c.w, http.StatusOK, seclai.AlertListResponse{Data: out, Pagination: p})
+}
+
+func (s *Server) getAlert(c *call) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if a := s.alertOr404(c); a != nil {
+		writeJSON(c.w, http.StatusOK, a)
+	}
+}
+
+func (s *Server) changeAlertStatus(c *call) {
+	var body seclai.ChangeStatusRequest
+	if !c.decode(&body) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a := s.alertOr404(c)
+	if a == nil {
+		return
+	}
+	now := timestamp()
+	prev, user := a.Alert.Status, testUserID
+	a.History = append(a.History, seclai.AlertHistoryEntryResponse{
+		Id:              s.newID(),
+		NewStatus:       body.Status,
+		PreviousStatus:  &prev,
+		Note:            body.Note,
+		ChangedByUserId: &user,
+		CreatedAt:       &now,
+	})
+	a.Alert.Status, a.Alert.UpdatedAt = body.Status, &now
+	writeJSON(c.w, http.StatusOK, a)
+}
+
+func (s *Server) addAlertComment(c *call) {
+	var body seclai.AddCommentRequest
+	if !c.decode(&body) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a := s.alertOr404(c)
+	if a == nil {
+		return
+	}
+	now := timestamp()
+	a.Comments = append(a.Comments, seclai.AlertCommentResponse{Id: s.newID(), Body: body.Body, UserId: testUserID, CreatedAt: &now})
+	a.Alert.CommentCount = len(a.Comments)
+	writeJSON(c.w, http.StatusOK, a)
+}
+
+func (s *Server) setAlertSubscribed(subscribed bool) handlerFunc {
+	return func(c *call) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		a := s.alertOr404(c)
+		if a == nil {
+			return
+		}
+		subs := a.Subscribers[:0]
+		for _, sub := range a.Subscribers {
+			if sub.UserId != testUserID {
+				subs = append(subs, sub)
+			}
+		}
+		if subscribed {
+			now := timestamp()
+			subs = append(subs, seclai.AlertSubscriberResponse{Id: s.newID(), UserId: testUserID, CreatedAt: &now})
+		}
+		a.Subscribers = subs
+		a.Alert.IsSubscribed, a.Alert.SubscriberCount = subscribed, len(subs)
+		writeJSON(c.w, http.StatusOK, a)
+	}
+}
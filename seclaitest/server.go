@@ -0,0 +1,476 @@
+// Package seclaitest provides an in-memory fake of the Seclai API for tests.
+//
+// A [Server] keeps agents, agent definitions, runs, input uploads, sources,
+// source uploads, exports, knowledge bases, memory banks and alerts in memory,
+// so a test can drive the real [seclai.Client] through a whole workflow
+// without hand-writing httptest handlers:
+//
+//	srv := seclaitest.NewServer(t, nil)
+//	client := srv.Client(seclai.Options{})
+//
+//	agent, _ := client.CreateAgent(ctx, seclai.CreateAgentRequest{Name: "triage"})
+//	run, _ := client.RunStreamingAgentAndWait(ctx, agent.Id, seclai.AgentRunStreamRequest{Input: &input})
+//
+// Every request is checked against the OpenAPI document bundled with the SDK
+// before it reaches the fake. A request the API would reject — an unknown
+// route, a missing required field, a value of the wrong type — is answered
+// with the API's own 422 shape and fails the test, so the fake cannot drift
+// from the contract the client was built for.
+//
+// Runs move through a [RunScript] of statuses, one step per read or stream
+// event. [Server.AddHook], [Server.FailNext] and [Server.Delay] inject
+// failures and latency.
+package seclaitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	seclai "github.com/seclai/seclai-go"
+	"github.com/seclai/seclai-go/internal/routes"
+)
+
+// Options configure a [Server]. The zero value is ready to use.
+type Options struct {
+	// Latency delays every response. Use [Server.Delay] to slow down only
+	// some routes.
+	Latency time.Duration
+
+	// RunScript is the status sequence for runs of agents without their own
+	// script. Defaults to pending, processing, completed.
+	RunScript *RunScript
+
+	// AllowInvalidRequests answers requests that break the OpenAPI contract
+	// with a 422 without failing the test. Set it when the test is about how
+	// the caller handles that 422.
+	AllowInvalidRequests bool
+}
+
+// RunScript scripts how a run progresses.
+type RunScript struct {
+	// Statuses are the statuses the run reports, in order. A new run starts at
+	// the first; each later read of the run, and each stream event, moves it
+	// one step on until it reaches the last. Defaults to pending, processing,
+	// completed.
+	Statuses []string
+
+	// Output is the output of a run that ends completed. Defaults to the
+	// run's input.
+	Output string
+
+	// Interval paces a streamed run's events. Defaults to no delay.
+	Interval time.Duration
+}
+
+var defaultStatuses = []string{"pending", "processing", "completed"}
+
+func (rs *RunScript) statuses() []string {
+	if rs == nil || len(rs.Statuses) == 0 {
+		return defaultStatuses
+	}
+	return rs.Statuses
+}
+
+// Request is a request the server received, recorded for assertions.
+type Request struct {
+	Method string
+	// Path is the request path, without the query.
+	Path string
+	// Route is the OpenAPI path template the path matched, or "" if none did.
+	Route  string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// Upload is a file uploaded to a source.
+type Upload struct {
+	ContentVersionID string
+	Filename         string
+	ContentType      string
+	Title            string
+	// Metadata is the metadata form field, as sent.
+	Metadata string
+	Data     []byte
+}
+
+// Server is a stateful in-memory fake of the Seclai API.
+type Server struct {
+	// URL is the server's base URL, for [seclai.Options.BaseURL].
+	URL string
+
+	t    testing.TB
+	opts Options
+	srv  *httptest.Server
+	spec *spec
+
+	// handlers maps "METHOD template" to the fake's implementation.
+	handlers map[string]handlerFunc
+
+	mu         sync.Mutex
+	seq        int
+	hooks      []Hook
+	requests   []Request
+	violations []Violation
+	agents     table[agent]
+	runs       table[run]
+	inputs     table[inputUpload]
+	sources    table[source]
+	exports    table[export]
+	kbs        table[seclai.KnowledgeBaseResponse]
+	mbs        table[seclai.MemoryBankResponse]
+	alerts     table[seclai.AlertDetailResponse]
+}
+
+// AccountID is the account every resource on a [Server] belongs to.
+const AccountID = "00000000-0000-4000-8000-000000000000"
+
+// NewServer starts a server and closes it when the test ends. opts may be nil.
+func NewServer(t testing.TB, opts *Options) *Server {
+	t.Helper()
+	sp, err := loadSpec()
+	if err != nil {
+		t.Fatalf("seclaitest: load OpenAPI spec: %v", err)
+	}
+	s := &Server{t: t, spec: sp}
+	if opts != nil {
+		s.opts = *opts
+	}
+	s.handlers = s.routes()
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Close shuts the server down. It is safe to call more than once.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Client returns a client for the server. BaseURL and, when no credential is
+// set, APIKey are filled in; everything else in opts is used as given.
+func (s *Server) Client(opts seclai.Options) *seclai.Client {
+	s.t.Helper()
+	opts.BaseURL = s.URL
+	if opts.APIKey == "" && opts.AccessToken == "" && opts.AccessTokenProvider == nil {
+		opts.APIKey = "seclaitest"
+	}
+	c, err := seclai.NewClient(opts)
+	if err != nil {
+		s.t.Fatalf("seclaitest: NewClient: %v", err)
+	}
+	return c
+}
+
+// SetRunScript scripts the runs of one agent, replacing [Options.RunScript]
+// for it. It applies to runs started afterwards.
+func (s *Server) SetRunScript(agentID string, script RunScript) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if a := s.agents.get(agentID); a != nil {
+		a.script = &script
+		return
+	}
+	s.t.Errorf("seclaitest: SetRunScript: no agent %q", agentID)
+}
+
+// AddAlert stores an alert, as the platform would on raising one, and returns
+// it with any missing id, account and timestamps filled in.
+func (s *Server) AddAlert(alert seclai.AlertResponse) seclai.AlertResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if alert.Id == "" {
+		alert.Id = s.newID()
+	}
+	if alert.AccountId == "" {
+		alert.AccountId = AccountID
+	}
+	if alert.Status == "" {
+		alert.Status = "open"
+	}
+	if alert.CreatedAt == nil {
+		now := timestamp()
+		alert.CreatedAt, alert.UpdatedAt = &now, &now
+	}
+	s.alerts.put(alert.Id, &seclai.AlertDetailResponse{
+		Alert:       alert,
+		Comments:    []seclai.AlertCommentResponse{},
+		History:     []seclai.AlertHistoryEntryResponse{},
+		Subscribers: []seclai.AlertSubscriberResponse{},
+	})
+	return alert
+}
+
+// Run returns the current state of a run.
+func (s *Server) Run(runID string) (seclai.AgentRunResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r := s.runs.get(runID); r != nil {
+		return r.resp, true
+	}
+	return seclai.AgentRunResponse{}, false
+}
+
+// Uploads returns the files uploaded to a source, oldest first.
+func (s *Server) Uploads(sourceID string) []Upload {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if src := s.sources.get(sourceID); src != nil {
+		return append([]Upload(nil), src.uploads...)
+	}
+	return nil
+}
+
+// Requests returns every request received so far, oldest first.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Violations returns every contract violation seen so far, oldest first.
+func (s *Server) Violations() []Violation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Violation(nil), s.violations...)
+}
+
+// call is one request as the handlers see it.
+type call struct {
+	w      http.ResponseWriter
+	r      *http.Request
+	params map[string]string
+	body   []byte
+}
+
+type handlerFunc func(*call)
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeDetail(w, http.StatusBadRequest, "seclaitest: read body: "+err.Error())
+		return
+	}
+	route, matched := routes.Match(r.URL.Path)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Route:  route.Template,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	hooks := append([]Hook(nil), s.hooks...)
+	s.mu.Unlock()
+
+	delay := s.opts.Latency
+	for _, h := range hooks {
+		f := h(r, route.Template)
+		if f == nil {
+			continue
+		}
+		delay += f.Delay
+		if f.Status == 0 && !f.Disconnect {
+			continue
+		}
+		if !sleep(r, delay) {
+			return
+		}
+		f.write(w)
+		return
+	}
+	if !sleep(r, delay) {
+		return
+	}
+
+	if !matched {
+		writeDetail(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	op := s.spec.operations[r.Method+" "+route.Template]
+	if op == nil {
+		w.Header().Set("Allow", strings.Join(s.spec.methods[route.Template], ", "))
+		writeDetail(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		return
+	}
+	if r.Header.Get("X-API-Key") == "" && r.Header.Get("Authorization") == "" {
+		writeDetail(w, http.StatusUnauthorized, "Not authenticated")
+		return
+	}
+	if vs := s.spec.validate(op, r, route.Params, body); len(vs) > 0 {
+		s.mu.Lock()
+		s.violations = append(s.violations, vs...)
+		s.mu.Unlock()
+		if !s.opts.AllowInvalidRequests {
+			msgs := make([]string, len(vs))
+			for i, v := range vs {
+				msgs[i] = v.String()
+			}
+			s.t.Errorf("seclaitest: %s %s does not match the OpenAPI spec:\n\t%s", r.Method, r.URL.Path, strings.Join(msgs, "\n\t"))
+		}
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"detail": vs})
+		return
+	}
+
+	h := s.handlers[r.Method+" "+route.Template]
+	if h == nil {
+		writeDetail(w, http.StatusNotImplemented, fmt.Sprintf("seclaitest: %s %s is not implemented", r.Method, route.Template))
+		return
+	}
+	h(&call{w: w, r: r, params: route.Params, body: body})
+}
+
+// sleep waits d, reporting false if the client went away first.
+func sleep(r *http.Request, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-r.Context().Done():
+		return false
+	}
+}
+
+// newID returns the next id. IDs are valid UUIDs, since many routes declare
+// their id parameters as such. Callers hold s.mu.
+func (s *Server) newID() string {
+	s.seq++
+	return fmt.Sprintf("00000000-0000-4000-8000-%012x", s.seq)
+}
+
+func timestamp() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	var buf bytes.Buffer
+	_ = json.NewEncoder(&buf).Encode(v)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// writeDetail answers with the API's {"detail": "..."} error shape.
+func writeDetail(w http.ResponseWriter, status int, detail string) {
+	writeJSON(w, status, map[string]string{"detail": detail})
+}
+
+// decode unmarshals the request body into v, answering 400 on failure. The
+// body has already passed validation, so a failure means the fake's own
+// types disagree with the spec.
+func (c *call) decode(v any) bool {
+	if err := json.Unmarshal(c.body, v); err != nil {
+		writeDetail(c.w, http.StatusBadRequest, "seclaitest: decode body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// table is an insertion-ordered map of resources by id.
+type table[T any] struct {
+	order []string
+	rows  map[string]*T
+}
+
+func (t *table[T]) put(id string, v *T) {
+	if t.rows == nil {
+		t.rows = make(map[string]*T)
+	}
+	if _, ok := t.rows[id]; !ok {
+		t.order = append(t.order, id)
+	}
+	t.rows[id] = v
+}
+
+func (t *table[T]) get(id string) *T {
+	return t.rows[id]
+}
+
+func (t *table[T]) delete(id string) bool {
+	if _, ok := t.rows[id]; !ok {
+		return false
+	}
+	delete(t.rows, id)
+	for i, o := range t.order {
+		if o == id {
+			t.order = append(t.order[:i], t.order[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// list returns the rows, oldest first, that keep accepts; nil keeps all.
+func (t *table[T]) list(keep func(*T) bool) []*T {
+	out := make([]*T, 0, len(t.order))
+	for _, id := range t.order {
+		if v := t.rows[id]; keep == nil || keep(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// page slices items per the request's page and limit query parameters.
+func page[T any](r *http.Request, items []T) ([]T, seclai.PaginationResponse) {
+	q := r.URL.Query()
+	p, limit := max(atoi(q.Get("page"), 1), 1), atoi(q.Get("limit"), 20)
+	if limit < 1 {
+		limit = 20
+	}
+	if off := q.Get("offset"); off != "" {
+		p = atoi(off, 0)/limit + 1
+	}
+	total := len(items)
+	pages := (total + limit - 1) / limit
+	start := min((p-1)*limit, total)
+	end := min(start+limit, total)
+	return items[start:end], seclai.PaginationResponse{
+		Page:    p,
+		Limit:   limit,
+		Total:   total,
+		Pages:   pages,
+		HasNext: p < pages,
+		HasPrev: p > 1,
+	}
+}
+
+func atoi(s string, def int) int {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return def
+	}
+	return n
+}
+
+// canonicalEnvelope reports whether the request opted into the API version
+// that answers every list with {data, pagination}.
+func canonicalEnvelope(r *http.Request) bool {
+	return r.Header.Get("Seclai-Version") >= seclai.APIVersion20260727
+}
+
+// values dereferences rows for encoding.
+func values[T any](rows []*T) []T {
+	out := make([]T, len(rows))
+	for i, r := range rows {
+		out[i] = *r
+	}
+	return out
+}
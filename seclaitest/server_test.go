@@ -0,0 +1,378 @@
+package seclaitest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	seclai "github.com/seclai/seclai-go"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestRoutes_AreDeclaredBySpec(t *testing.T) {
+	sp, err := loadSpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key := range (&Server{}).routes() {
+		if sp.operations[key] == nil {
+			t.Errorf("%s is not an operation in the OpenAPI spec", key)
+		}
+	}
+}
+
+func TestAgents_CRUDAndDefinition(t *testing.T) {
+	ctx := context.Background()
+	c := NewServer(t, nil).Client(seclai.Options{})
+
+	agent, err := c.CreateAgent(ctx, seclai.CreateAgentRequest{Name: "triage", Description: ptr("sorts mail")})
+	if err != nil {
+		t.Fatalf("CreateAgent: %v", err)
+	}
+	if _, err := c.UpdateAgent(ctx, agent.Id, seclai.UpdateAgentRequest{Name: ptr("triage v2")}); err != nil {
+		t.Fatalf("UpdateAgent: %v", err)
+	}
+	got, err := c.GetAgent(ctx, agent.Id)
+	if err != nil || got.Name != "triage v2" {
+		t.Fatalf("GetAgent = %+v, %v", got, err)
+	}
+
+	def, err := c.GetAgentDefinition(ctx, agent.Id)
+	if err != nil {
+		t.Fatalf("GetAgentDefinition: %v", err)
+	}
+	steps := map[string]any{"steps": []any{map[string]any{"id": "s1"}}}
+	if _, err := c.UpdateAgentDefinition(ctx, agent.Id, seclai.UpdateAgentDefinitionRequest{Definition: steps, ExpectedChangeId: def.ChangeId}); err != nil {
+		t.Fatalf("UpdateAgentDefinition: %v", err)
+	}
+	_, err = c.UpdateAgentDefinition(ctx, agent.Id, seclai.UpdateAgentDefinitionRequest{Definition: steps, ExpectedChangeId: def.ChangeId})
+	var apiErr *seclai.APIStatusError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusConflict {
+		t.Fatalf("expected a 409 for a stale change id, got %v", err)
+	}
+
+	list, err := c.ListAgents(ctx, seclai.ListOptions{})
+	if err != nil || len(list.Data) != 1 {
+		t.Fatalf("ListAgents = %+v, %v", list, err)
+	}
+	if err := c.DeleteAgent(ctx, agent.Id); err != nil {
+		t.Fatalf("DeleteAgent: %v", err)
+	}
+	if _, err := c.GetAgent(ctx, agent.Id); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected a 404 after delete, got %v", err)
+	}
+}
+
+func TestRuns_PollingWalksTheScript(t *testing.T) {
+	ctx := context.Background()
+	srv := NewServer(t, nil)
+	c := srv.Client(seclai.Options{})
+	agent, _ := c.CreateAgent(ctx, seclai.CreateAgentRequest{Name: "a"})
+	srv.SetRunScript(agent.Id, RunScript{Statuses: []string{"queued", "processing", "failed"}})
+
+	run, err := c.RunAgent(ctx, agent.Id, seclai.AgentRunRequest{Input: ptr("hi")})
+	if err != nil {
+		t.Fatalf("RunAgent: %v", err)
+	}
+	var seen []string
+	for _, want := range []string{"processing", "failed", "failed"} {
+		got, err := c.GetAgentRun(ctx, run.RunId, nil)
+		if err != nil {
+			t.Fatalf("GetAgentRun: %v", err)
+		}
+		seen = append(seen, string(got.Status))
+		if string(got.Status) != want {
+			t.Fatalf("statuses %v, want %s next", seen, want)
+		}
+	}
+	if final, _ := srv.Run(run.RunId); final.ErrorCount != 1 {
+		t.Fatalf("a failed run should report an error, got %+v", final)
+	}
+}
+
+func TestRuns_StreamEmitsInitUpdatesAndDone(t *testing.T) {
+	ctx := context.Background()
+	srv := NewServer(t, &Options{RunScript: &RunScript{Output: "42"}})
+	c := srv.Client(seclai.Options{})
+	agent, _ := c.CreateAgent(ctx, seclai.CreateAgentRequest{Name: "a"})
+
+	events, errs := c.RunStreamingAgent(ctx, agent.Id, seclai.AgentRunStreamRequest{Input: ptr("q")})
+	var names []string
+	var last seclai.AgentRunEvent
+	for evt := range events {
+		names = append(names, evt.Event)
+		last = evt
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	if got := strings.Join(names, ","); got != "init,update,done" {
+		t.Fatalf("events %s", got)
+	}
+	if last.Run == nil || last.Run.Output == nil || *last.Run.Output != "42" {
+		t.Fatalf("done event should carry the scripted output, got %+v", last.Run)
+	}
+
+	run, err := c.RunStreamingAgentAndWait(ctx, agent.Id, seclai.AgentRunStreamRequest{Input: ptr("q")})
+	if err != nil || run.Status != "completed" {
+		t.Fatalf("RunStreamingAgentAndWait = %+v, %v", run, err)
+	}
+}
+
+func TestRuns_InputUploadsAndCancel(t *testing.T) {
+	ctx := context.Background()
+	c := NewServer(t, nil).Client(seclai.Options{})
+	agent, _ := c.CreateAgent(ctx, seclai.CreateAgentRequest{Name: "a"})
+
+	up, err := c.UploadAgentInput(ctx, agent.Id, seclai.UploadFileRequest{File: []byte("hello"), FileName: "in.txt"})
+	if err != nil {
+		t.Fatalf("UploadAgentInput: %v", err)
+	}
+	if up.Status != "ready" || up.FileSize != 5 || !strings.HasPrefix(up.ContentType, "text/plain") {
+		t.Fatalf("unexpected upload %+v", up)
+	}
+	if got, err := c.GetAgentInputUploadStatus(ctx, agent.Id, up.Id); err != nil || got.Id != up.Id {
+		t.Fatalf("GetAgentInputUploadStatus = %+v, %v", got, err)
+	}
+
+	var uploadID openapi_types.UUID
+	if err := uploadID.UnmarshalText([]byte(up.Id)); err != nil {
+		t.Fatal(err)
+	}
+	run, err := c.RunAgent(ctx, agent.Id, seclai.AgentRunRequest{InputUploadId: &uploadID})
+	if err != nil {
+		t.Fatalf("RunAgent: %v", err)
+	}
+	var apiErr *seclai.APIStatusError
+	other, _ := c.CreateAgent(ctx, seclai.CreateAgentRequest{Name: "b"})
+	if _, err := c.RunAgent(ctx, other.Id, seclai.AgentRunRequest{InputUploadId: &uploadID}); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("an upload belongs to its agent, got %v", err)
+	}
+	cancelled, err := c.CancelAgentRun(ctx, run.RunId)
+	if err != nil || cancelled.Status != "failed" {
+		t.Fatalf("CancelAgentRun = %+v, %v", cancelled, err)
+	}
+}
+
+func TestSources_UploadsAndExports(t *testing.T) {
+	ctx := context.Background()
+	srv := NewServer(t, nil)
+	c := srv.Client(seclai.Options{})
+
+	src, err := c.CreateSource(ctx, seclai.CreateSourceBody{Name: "docs", SourceType: "file_upload"})
+	if err != nil {
+		t.Fatalf("CreateSource: %v", err)
+	}
+	if _, err := c.UploadFileToSource(ctx, src.Id, seclai.UploadFileRequest{File: []byte("# Hi"), FileName: "a.md", Title: "A", Metadata: map[string]any{"k": "v"}}); err != nil {
+		t.Fatalf("UploadFileToSource: %v", err)
+	}
+	if _, err := c.UploadInlineTextToSource(ctx, src.Id, seclai.InlineTextUploadRequest{Text: "inline", Title: ptr("B")}); err != nil {
+		t.Fatalf("UploadInlineTextToSource: %v", err)
+	}
+	uploads := srv.Uploads(src.Id)
+	if len(uploads) != 2 || uploads[0].Title != "A" || uploads[0].Metadata != `{"k":"v"}` || string(uploads[1].Data) != "inline" {
+		t.Fatalf("unexpected uploads %+v", uploads)
+	}
+
+	exp, err := c.CreateSourceExport(ctx, src.Id, seclai.CreateExportRequest{Format: "csv"})
+	if err != nil {
+		t.Fatalf("CreateSourceExport: %v", err)
+	}
+	for exp.Status != "completed" {
+		if exp, err = c.GetSourceExport(ctx, src.Id, exp.Id); err != nil {
+			t.Fatalf("GetSourceExport: %v", err)
+		}
+	}
+	if exp.ItemCount == nil || *exp.ItemCount != 2 {
+		t.Fatalf("unexpected export %+v", exp)
+	}
+	resp, err := c.DownloadSourceExport(ctx, src.Id, exp.Id)
+	if err != nil {
+		t.Fatalf("DownloadSourceExport: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if lines := strings.Split(strings.TrimSpace(string(body)), "\n"); len(lines) != 3 || !strings.Contains(lines[1], "a.md") {
+		t.Fatalf("unexpected export body %q", body)
+	}
+
+	var n int
+	for _, err := range c.AllSourceExports(ctx, src.Id, seclai.ListOptions{}) {
+		if err != nil {
+			t.Fatalf("AllSourceExports: %v", err)
+		}
+		n++
+	}
+	if n != 1 {
+		t.Fatalf("expected one export, got %d", n)
+	}
+}
+
+func TestKnowledgeBasesAndMemoryBanks(t *testing.T) {
+	ctx := context.Background()
+	c := NewServer(t, nil).Client(seclai.Options{})
+	src, _ := c.CreateSource(ctx, seclai.CreateSourceBody{Name: "docs", SourceType: "file_upload"})
+	kb, err := c.CreateKnowledgeBase(ctx, seclai.CreateKnowledgeBaseBody{Name: "kb", SourceIds: []string{src.Id}})
+	if err != nil || kb.Sources == nil || len(*kb.Sources) != 1 {
+		t.Fatalf("CreateKnowledgeBase = %+v, %v", kb, err)
+	}
+	if _, err := c.UpdateKnowledgeBase(ctx, kb.Id, seclai.UpdateKnowledgeBaseBody{Name: ptr("kb2")}); err != nil {
+		t.Fatalf("UpdateKnowledgeBase: %v", err)
+	}
+	mb, err := c.CreateMemoryBank(ctx, seclai.CreateMemoryBankBody{Name: "mb"})
+	if err != nil || mb.Mode != "fast_and_cheap" || mb.Type != "conversation" {
+		t.Fatalf("CreateMemoryBank = %+v, %v", mb, err)
+	}
+
+	var kbs, mbs []string
+	for kb, err := range c.AllKnowledgeBases(ctx, seclai.SortableListOptions{}) {
+		if err != nil {
+			t.Fatalf("AllKnowledgeBases: %v", err)
+		}
+		kbs = append(kbs, kb.Name)
+	}
+	for mb, err := range c.AllMemoryBanks(ctx, seclai.SortableListOptions{}) {
+		if err != nil {
+			t.Fatalf("AllMemoryBanks: %v", err)
+		}
+		mbs = append(mbs, mb.Name)
+	}
+	if strings.Join(kbs, ",") != "kb2" || strings.Join(mbs, ",") != "mb" {
+		t.Fatalf("knowledge bases %v, memory banks %v", kbs, mbs)
+	}
+	if err := c.DeleteMemoryBank(ctx, mb.Id); err != nil {
+		t.Fatalf("DeleteMemoryBank: %v", err)
+	}
+}
+
+func TestAlerts(t *testing.T) {
+	ctx := context.Background()
+	srv := NewServer(t, nil)
+	c := srv.Client(seclai.Options{})
+	alert := srv.AddAlert(seclai.AlertResponse{AlertType: "run_failed", Title: "Run failed", AgentId: ptr("a_1")})
+	srv.AddAlert(seclai.AlertResponse{AlertType: "run_failed", Title: "Other"})
+
+	if _, err := c.ChangeAlertStatus(ctx, alert.Id, seclai.ChangeStatusRequest{Status: "resolved", Note: ptr("fixed")}); err != nil {
+		t.Fatalf("ChangeAlertStatus: %v", err)
+	}
+	if _, err := c.AddAlertComment(ctx, alert.Id, seclai.AddCommentRequest{Body: "looking"}); err != nil {
+		t.Fatalf("AddAlertComment: %v", err)
+	}
+	if _, err := c.SubscribeToAlert(ctx, alert.Id); err != nil {
+		t.Fatalf("SubscribeToAlert: %v", err)
+	}
+	detail, err := c.Typed().GetAlert(ctx, alert.Id)
+	if err != nil {
+		t.Fatalf("GetAlert: %v", err)
+	}
+	if detail.Alert.Status != "resolved" || len(detail.History) != 1 || detail.Alert.CommentCount != 1 || !detail.Alert.IsSubscribed {
+		t.Fatalf("unexpected alert %+v", detail)
+	}
+
+	var titles []string
+	for a, err := range c.AllAlerts(ctx, seclai.ListAlertsOptions{Status: "resolved"}) {
+		if err != nil {
+			t.Fatalf("AllAlerts: %v", err)
+		}
+		titles = append(titles, a.Title)
+	}
+	if strings.Join(titles, ",") != "Run failed" {
+		t.Fatalf("unexpected alerts %v", titles)
+	}
+}
+
+func TestFailNextAndDelay(t *testing.T) {
+	ctx := context.Background()
+	srv := NewServer(t, nil)
+	srv.FailNext(http.MethodPost, "/agents", http.StatusServiceUnavailable, 1)
+	c := srv.Client(seclai.Options{})
+
+	_, err := c.CreateAgent(ctx, seclai.CreateAgentRequest{Name: "a"})
+	var apiErr *seclai.APIStatusError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected the injected 503, got %v", err)
+	}
+	if _, err := c.CreateAgent(ctx, seclai.CreateAgentRequest{Name: "a"}); err != nil {
+		t.Fatalf("the fault should apply once: %v", err)
+	}
+
+	srv.Inject("", "/agents/{agent_id}", 0, Fault{Status: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"7"}}})
+	_, err = c.GetAgent(ctx, "x")
+	var rl *seclai.RateLimitError
+	if !errors.As(err, &rl) || rl.RetryAfter != 7*time.Second {
+		t.Fatalf("expected a RateLimitError, got %v", err)
+	}
+
+	srv.Delay(http.MethodGet, "/agents", time.Second)
+	short, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := c.ListAgents(short, seclai.ListOptions{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the delay to outlast the deadline, got %v", err)
+	}
+}
+
+func TestDisconnectFault(t *testing.T) {
+	srv := NewServer(t, nil)
+	srv.Inject("", "", 1, Fault{Disconnect: true})
+	c := srv.Client(seclai.Options{})
+	if _, err := c.ListAgents(context.Background(), seclai.ListOptions{}); err == nil {
+		t.Fatal("expected a transport error")
+	}
+}
+
+func TestSpecViolations(t *testing.T) {
+	ctx := context.Background()
+	srv := NewServer(t, &Options{AllowInvalidRequests: true})
+	c := srv.Client(seclai.Options{APIVersion: seclai.APIVersion20260727})
+
+	err := c.Do(ctx, http.MethodPost, "/agents", nil, map[string]any{"description": 5}, nil, nil)
+	var ve *seclai.APIValidationError
+	if !errors.As(err, &ve) || ve.ValidationError == nil || ve.ValidationError.Detail == nil {
+		t.Fatalf("expected a 422 with details, got %v", err)
+	}
+	err = c.Do(ctx, http.MethodGet, "/agents", map[string]string{"limit": "500", "bogus": "1"}, nil, nil, nil)
+	if !errors.As(err, &ve) {
+		t.Fatalf("expected a 422, got %v", err)
+	}
+
+	var got []string
+	for _, v := range srv.Violations() {
+		got = append(got, v.String())
+	}
+	want := []string{
+		"body.name: Field required",
+		"body.description: Input should be a valid string",
+		"query.limit: Input should be less than or equal to 100",
+		"query.bogus: Extra inputs are not permitted",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("violations:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	var apiErr *seclai.APIStatusError
+	if err := c.Do(ctx, http.MethodGet, "/nope", nil, nil, nil, nil); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected a 404 for an unknown route, got %v", err)
+	}
+	if err := c.Do(ctx, http.MethodPatch, "/agents", nil, nil, nil, nil); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatalf("expected a 405 for an undeclared method, got %v", err)
+	}
+}
+
+func TestRequiresCredentials(t *testing.T) {
+	srv := NewServer(t, nil)
+	resp, err := http.Get(srv.URL + "/agents")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,331 @@
+package seclaitest
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	seclai "github.com/seclai/seclai-go"
+)
+
+type source struct {
+	resp    seclai.SourceResponse
+	uploads []Upload
+}
+
+type export struct {
+	resp seclai.ExportResponse
+	// step indexes exportStatuses.
+	step int
+}
+
+var exportStatuses = []string{"pending", "processing", "completed"}
+
+func (s *Server) sourceOr404(c *call) *source {
+	src := s.sources.get(c.params["source_connection_id"])
+	if src == nil {
+		writeDetail(c.w, http.StatusNotFound, "Source not found")
+	}
+	return src
+}
+
+func (s *Server) listSources(c *call) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items, p := page(c.r, s.sources.list(nil))
+	out := make([]seclai.SourceResponse, len(items))
+	for i, src := range items {
+		out[i] = src.resp
+	}
+	writeJSON(c.w, http.StatusOK, seclai.SourceListResponse{Data: out, Pagination: p})
+}
+
+func (s *Server) createSource(c *call) {
+	var body seclai.CreateSourceBody
+	if !c.decode(&body) {
+		return
+	}
+	var account openapi_types.UUID
+	_ = account.UnmarshalText([]byte(AccountID))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := timestamp()
+	src := &source{resp: seclai.SourceResponse{
+		Id:              s.newID(),
+		AccountId:       account,
+		Name:            body.Name,
+		SourceType:      body.SourceType,
+		ContentFilter:   "all",
+		ChunkSize:       body.ChunkSize,
+		ChunkOverlap:    body.ChunkOverlap,
+		Dimensions:      body.Dimensions,
+		EmbeddingModel:  body.EmbeddingModel,
+		IndexMode:       body.IndexMode,
+		MediaTypes:      body.MediaTypes,
+		Polling:         body.Polling,
+		PollingAction:   body.PollingAction,
+		PollingMaxItems: body.PollingMaxItems,
+		Retention:       body.Retention,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}}
+	if body.ContentFilter != nil {
+		src.resp.ContentFilter = *body.ContentFilter
+	}
+	s.sources.put(src.resp.Id, src)
+	writeJSON(c.w, http.StatusCreated, src.resp)
+}
+
+func (s *Server) getSource(c *call) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if src := s.sourceOr404(c); src != nil {
+		writeJSON(c.w, http.StatusOK, src.resp)
+	}
+}
+
+func (s *Server) updateSource(c *call) {
+	var body seclai.UpdateSourceBody
+	if !c.decode(&body) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	src := s.sourceOr404(c)
+	if src == nil {
+		return
+	}
+	if body.Name != nil {
+		src.resp.Name = *body.Name
+	}
+	if body.MediaTypes != nil {
+		src.resp.MediaTypes = body.MediaTypes
+	}
+	if body.Polling != nil {
+		src.resp.Polling = body.Polling
+	}
+	if body.RetentionDays != nil {
+		src.resp.Retention = body.RetentionDays
+	}
+	src.resp.UpdatedAt = timestamp()
+	writeJSON(c.w, http.StatusOK, src.resp)
+}
+
+func (s *Server) deleteSource(c *call) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.sources.delete(c.params["source_connection_id"]) {
+		writeDetail(c.w, http.StatusNotFound, "Source not found")
+		return
+	}
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+// addUpload stores u on src and answers as the upload endpoints do. Callers
+// hold s.mu.
+func (s *Server) addUpload(c *call, src *source, u Upload) {
+	u.ContentVersionID = s.newID()
+	src.uploads = append(src.uploads, u)
+	count := len(src.uploads)
+	src.resp.ContentCount = &count
+	writeJSON(c.w, http.StatusOK, seclai.FileUploadResponse{
+		Filename:         u.Filename,
+		Status:           "processing",
+		ContentVersionId: &u.ContentVersionID,
+	})
+}
+
+func (s *Server) uploadToSource(c *call) {
+	file, _, err := readForm(c)
+	if err != nil {
+		writeDetail(c.w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if src := s.sourceOr404(c); src != nil {
+		s.addUpload(c, src, file)
+	}
+}
+
+func (s *Server) uploadInlineText(c *call) {
+	var body seclai.InlineTextUploadRequest
+	if !c.decode(&body) {
+		return
+	}
+	u := Upload{Filename: "inline.txt", ContentType: "text/plain", Data: []byte(body.Text)}
+	if body.ContentType != nil {
+		u.ContentType = *body.ContentType
+	}
+	if body.Title != nil {
+		u.Title = *body.Title
+	}
+	if body.Metadata != nil {
+		b, _ := json.Marshal(*body.Metadata)
+		u.Metadata = string(b)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if src := s.sourceOr404(c); src != nil {
+		s.addUpload(c, src, u)
+	}
+}
+
+func (s *Server) exportOr404(c *call) *export {
+	e := s.exports.get(c.params["export_id"])
+	if e == nil || e.resp.SourceConnectionId != c.params["source_connection_id"] {
+		writeDetail(c.w, http.StatusNotFound, "Export not found")
+		return nil
+	}
+	return e
+}
+
+func (s *Server) listExports(c *call) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sourceOr404(c) == nil {
+		return
+	}
+	items, p := page(c.r, s.exports.list(func(e *export) bool {
+		return e.resp.SourceConnectionId == c.params["source_connection_id"]
+	}))
+	out := make([]any, len(items))
+	for i, e := range items {
+		out[i] = e.resp
+	}
+	writeJSON(c.w, http.StatusOK, seclai.ExportListResponse{Data: out, Pagination: p})
+}
+
+func (s *Server) createExport(c *call) {
+	var body seclai.CreateExportRequest
+	if !c.decode(&body) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	src := s.sourceOr404(c)
+	if src == nil {
+		return
+	}
+	now := timestamp()
+	e := &export{resp: seclai.ExportResponse{
+		Id:                 s.newID(),
+		AccountId:          AccountID,
+		SourceConnectionId: src.resp.Id,
+		Format:             string(body.Format),
+		Destination:        "download",
+		Status:             exportStatuses[0],
+		MetadataFilter:     body.MetadataFilter,
+		QueryFilter:        body.QueryFilter,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}}
+	s.exports.put(e.resp.Id, e)
+	writeJSON(c.w, http.StatusAccepted, e.resp)
+}
+
+// getExport moves the export one step towards completed and answers with it.
+// A cancelled export stays cancelled.
+func (s *Server) getExport(c *call) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.exportOr404(c)
+	if e == nil {
+		return
+	}
+	if e.resp.Status == exportStatuses[e.step] && e.step < len(exportStatuses)-1 {
+		e.step++
+		e.resp.Status = exportStatuses[e.step]
+		if e.resp.Status == "completed" {
+			s.completeExport(e)
+		}
+	}
+	writeJSON(c.w, http.StatusOK, e.resp)
+}
+
+// completeExport fills in the results of a finished export. Callers hold s.mu.
+func (s *Server) completeExport(e *export) {
+	now := timestamp()
+	var items int
+	if src := s.sources.get(e.resp.SourceConnectionId); src != nil {
+		items = len(src.uploads)
+	}
+	size := len(s.exportData(e))
+	e.resp.CompletedAt, e.resp.UpdatedAt = &now, now
+	e.resp.ItemCount, e.resp.FileSizeBytes = &items, &size
+}
+
+// exportData renders a source's uploads in the export's format: CSV for
+// "csv", JSON lines otherwise. Callers hold s.mu.
+func (s *Server) exportData(e *export) []byte {
+	var uploads []Upload
+	if src := s.sources.get(e.resp.SourceConnectionId); src != nil {
+		uploads = src.uploads
+	}
+	var buf bytes.Buffer
+	if e.resp.Format == "csv" {
+		w := csv.NewWriter(&buf)
+		_ = w.Write([]string{"content_version_id", "filename", "title", "content_type"})
+		for _, u := range uploads {
+			_ = w.Write([]string{u.ContentVersionID, u.Filename, u.Title, u.ContentType})
+		}
+		w.Flush()
+		return buf.Bytes()
+	}
+	enc := json.NewEncoder(&buf)
+	for _, u := range uploads {
+		_ = enc.Encode(map[string]string{
+			"content_version_id": u.ContentVersionID,
+			"filename":           u.Filename,
+			"title":              u.Title,
+			"content_type":       u.ContentType,
+		})
+	}
+	return buf.Bytes()
+}
+
+func (s *Server) cancelExport(c *call) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.exportOr404(c)
+	if e == nil {
+		return
+	}
+	if e.resp.Status != "completed" {
+		e.resp.Status, e.resp.UpdatedAt = "cancelled", timestamp()
+	}
+	writeJSON(c.w, http.StatusOK, e.resp)
+}
+
+func (s *Server) deleteExport(c *call) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.exportOr404(c) == nil {
+		return
+	}
+	s.exports.delete(c.params["export_id"])
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) downloadExport(c *call) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.exportOr404(c)
+	if e == nil {
+		return
+	}
+	if e.resp.Status != "completed" {
+		writeDetail(c.w, http.StatusConflict, "Export is not ready for download")
+		return
+	}
+	ct := "application/x-ndjson"
+	if e.resp.Format == "csv" {
+		ct = "text/csv"
+	}
+	c.w.Header().Set("Content-Type", ct)
+	c.w.Header().Set("Content-Disposition", `attachment; filename="export-`+e.resp.Id+"."+e.resp.Format+`"`)
+	_, _ = c.w.Write(s.exportData(e))
+}
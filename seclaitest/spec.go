@@ -0,0 +1,442 @@
+package seclaitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/seclai/seclai-go/openapi"
+)
+
+// Violation is one way a request broke the OpenAPI contract. The fields
+// mirror the entries of the API's own 422 validation errors.
+type Violation struct {
+	// Loc locates the offending value, e.g. ["query", "limit"] or ["body", "name"].
+	Loc []string `json:"loc"`
+	// Msg describes the problem.
+	Msg string `json:"msg"`
+	// Type is a short machine-readable category, e.g. "missing".
+	Type string `json:"type"`
+}
+
+func (v Violation) String() string {
+	return strings.Join(v.Loc, ".") + ": " + v.Msg
+}
+
+type spec struct {
+	// operations maps "METHOD template" to the operation.
+	operations map[string]*operation
+	// methods maps a template to the methods it declares.
+	methods    map[string][]string
+	schemas    map[string]*schema
+	parameters map[string]*parameter
+}
+
+type operation struct {
+	Parameters  []*parameter `json:"parameters"`
+	RequestBody *struct {
+		Required bool                                `json:"required"`
+		Content  map[string]struct{ Schema *schema } `json:"content"`
+	} `json:"requestBody"`
+}
+
+type parameter struct {
+	Ref      string  `json:"$ref"`
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *schema `json:"schema"`
+}
+
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Format               string             `json:"format"`
+	Enum                 []any              `json:"enum"`
+	Pattern              string             `json:"pattern"`
+	Properties           map[string]*schema `json:"properties"`
+	Required             []string           `json:"required"`
+	Items                *schema            `json:"items"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+	AnyOf                []*schema          `json:"anyOf"`
+	OneOf                []*schema          `json:"oneOf"`
+	AllOf                []*schema          `json:"allOf"`
+	Minimum              *float64           `json:"minimum"`
+	Maximum              *float64           `json:"maximum"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum"`
+	ExclusiveMaximum     *float64           `json:"exclusiveMaximum"`
+	MinLength            *int               `json:"minLength"`
+	MaxLength            *int               `json:"maxLength"`
+	MinItems             *int               `json:"minItems"`
+	MaxItems             *int               `json:"maxItems"`
+}
+
+var (
+	loadOnce   sync.Once
+	loadedSpec *spec
+	loadErr    error
+)
+
+// loadSpec parses the embedded spec once per process.
+func loadSpec() (*spec, error) {
+	loadOnce.Do(func() {
+		var doc struct {
+			Paths      map[string]map[string]json.RawMessage `json:"paths"`
+			Components struct {
+				Schemas    map[string]*schema    `json:"schemas"`
+				Parameters map[string]*parameter `json:"parameters"`
+			} `json:"components"`
+		}
+		if loadErr = json.Unmarshal(openapi.Spec, &doc); loadErr != nil {
+			return
+		}
+		s := &spec{
+			operations: make(map[string]*operation),
+			methods:    make(map[string][]string),
+			schemas:    doc.Components.Schemas,
+			parameters: doc.Components.Parameters,
+		}
+		for tmpl, ops := range doc.Paths {
+			for method, raw := range ops {
+				var op operation
+				if loadErr = json.Unmarshal(raw, &op); loadErr != nil {
+					return
+				}
+				m := strings.ToUpper(method)
+				s.operations[m+" "+tmpl] = &op
+				s.methods[tmpl] = append(s.methods[tmpl], m)
+			}
+			sort.Strings(s.methods[tmpl])
+		}
+		loadedSpec = s
+	})
+	return loadedSpec, loadErr
+}
+
+// strictQueryVersion is the first API version that rejects undeclared query
+// parameters.
+const strictQueryVersion = "2026-07-27"
+
+// validate checks r, whose path matched tmpl with the given parameters,
+// against the operation the spec declares for it. body is the request body,
+// already read.
+func (s *spec) validate(op *operation, r *http.Request, params map[string]string, body []byte) []Violation {
+	var out []Violation
+	declared := map[string]bool{}
+	for _, p := range op.Parameters {
+		p = s.param(p)
+		if p == nil {
+			continue
+		}
+		var (
+			value   string
+			present bool
+		)
+		switch p.In {
+		case "path":
+			value, present = params[p.Name]
+		case "query":
+			declared[p.Name] = true
+			if vs, ok := r.URL.Query()[p.Name]; ok {
+				value, present = vs[0], true
+			}
+		case "header":
+			if vs := r.Header.Values(p.Name); len(vs) > 0 {
+				value, present = vs[0], true
+			}
+		default:
+			continue
+		}
+		loc := []string{p.In, p.Name}
+		if !present {
+			if p.Required {
+				out = append(out, Violation{Loc: loc, Msg: "Field required", Type: "missing"})
+			}
+			continue
+		}
+		out = append(out, s.check(p.Schema, coerce(s, p.Schema, value), loc)...)
+	}
+
+	if version := r.Header.Get("Seclai-Version"); version >= strictQueryVersion {
+		var names []string
+		for name := range r.URL.Query() {
+			if !declared[name] {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			out = append(out, Violation{Loc: []string{"query", name}, Msg: "Extra inputs are not permitted", Type: "extra_forbidden"})
+		}
+	}
+
+	if op.RequestBody == nil {
+		return out
+	}
+	mediaType, mtParams, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if len(bytes.TrimSpace(body)) == 0 {
+		if op.RequestBody.Required {
+			out = append(out, Violation{Loc: []string{"body"}, Msg: "Field required", Type: "missing"})
+		}
+		return out
+	}
+	content, ok := op.RequestBody.Content[mediaType]
+	if !ok {
+		var want []string
+		for ct := range op.RequestBody.Content {
+			want = append(want, ct)
+		}
+		sort.Strings(want)
+		return append(out, Violation{Loc: []string{"body"}, Msg: fmt.Sprintf("unsupported content type %q; expected %s", mediaType, strings.Join(want, " or ")), Type: "content_type"})
+	}
+	switch mediaType {
+	case "application/json":
+		var v any
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		if err := dec.Decode(&v); err != nil {
+			return append(out, Violation{Loc: []string{"body"}, Msg: "JSON decode error: " + err.Error(), Type: "json_invalid"})
+		}
+		out = append(out, s.check(content.Schema, v, []string{"body"})...)
+	case "multipart/form-data":
+		out = append(out, s.checkForm(content.Schema, body, mtParams["boundary"])...)
+	}
+	return out
+}
+
+// checkForm checks that a multipart body carries the fields the schema requires.
+func (s *spec) checkForm(sc *schema, body []byte, boundary string) []Violation {
+	sc = s.resolve(sc)
+	if sc == nil {
+		return nil
+	}
+	fields := map[string]bool{}
+	mr := multipart.NewReader(bytes.NewReader(body), boundary)
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return []Violation{{Loc: []string{"body"}, Msg: "malformed multipart body: " + err.Error(), Type: "value_error"}}
+		}
+		fields[part.FormName()] = true
+		part.Close()
+	}
+	var out []Violation
+	for _, name := range sc.Required {
+		if !fields[name] {
+			out = append(out, Violation{Loc: []string{"body", name}, Msg: "Field required", Type: "missing"})
+		}
+	}
+	return out
+}
+
+func (s *spec) param(p *parameter) *parameter {
+	if p.Ref == "" {
+		return p
+	}
+	return s.parameters[strings.TrimPrefix(p.Ref, "#/components/parameters/")]
+}
+
+func (s *spec) resolve(sc *schema) *schema {
+	for sc != nil && sc.Ref != "" {
+		sc = s.schemas[strings.TrimPrefix(sc.Ref, "#/components/schemas/")]
+	}
+	return sc
+}
+
+// coerce converts a query, path or header string to the JSON type its schema
+// expects, leaving it a string when it does not parse so check reports it.
+func coerce(s *spec, sc *schema, raw string) any {
+	sc = s.resolve(sc)
+	if sc == nil {
+		return raw
+	}
+	types := []string{sc.Type}
+	for _, alt := range append(append([]*schema{}, sc.AnyOf...), sc.OneOf...) {
+		if alt = s.resolve(alt); alt != nil {
+			types = append(types, alt.Type)
+		}
+	}
+	for _, t := range types {
+		switch t {
+		case "integer", "number":
+			if _, err := strconv.ParseFloat(raw, 64); err == nil {
+				return json.Number(raw)
+			}
+		case "boolean":
+			if b, err := strconv.ParseBool(raw); err == nil {
+				return b
+			}
+		}
+	}
+	return raw
+}
+
+var uuidRe = regexp.MustCompile(`^[0-9a-fA-F]{8}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{12}$`)
+
+// check validates v against the subset of JSON Schema the spec uses.
+func (s *spec) check(sc *schema, v any, loc []string) []Violation {
+	sc = s.resolve(sc)
+	if sc == nil {
+		return nil
+	}
+	bad := func(typ, format string, args ...any) []Violation {
+		return []Violation{{Loc: append([]string(nil), loc...), Msg: fmt.Sprintf(format, args...), Type: typ}}
+	}
+
+	if alts := append(append([]*schema{}, sc.AnyOf...), sc.OneOf...); len(alts) > 0 {
+		var first []Violation
+		for _, alt := range alts {
+			errs := s.check(alt, v, loc)
+			if len(errs) == 0 {
+				return nil
+			}
+			if first == nil {
+				first = errs
+			}
+		}
+		return first
+	}
+	var out []Violation
+	for _, sub := range sc.AllOf {
+		out = append(out, s.check(sub, v, loc)...)
+	}
+
+	if len(sc.Enum) > 0 {
+		for _, e := range sc.Enum {
+			if fmt.Sprint(e) == fmt.Sprint(v) {
+				return out
+			}
+		}
+		return append(out, bad("enum", "Input should be one of %v", sc.Enum)...)
+	}
+
+	switch sc.Type {
+	case "":
+		return out
+	case "null":
+		if v != nil {
+			return append(out, bad("null_type", "Input should be null")...)
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return append(out, bad("string_type", "Input should be a valid string")...)
+		}
+		if sc.MinLength != nil && len([]rune(str)) < *sc.MinLength {
+			out = append(out, bad("string_too_short", "String should have at least %d characters", *sc.MinLength)...)
+		}
+		if sc.MaxLength != nil && len([]rune(str)) > *sc.MaxLength {
+			out = append(out, bad("string_too_long", "String should have at most %d characters", *sc.MaxLength)...)
+		}
+		if sc.Pattern != "" {
+			if re, err := regexp.Compile(sc.Pattern); err == nil && !re.MatchString(str) {
+				out = append(out, bad("string_pattern_mismatch", "String should match pattern '%s'", sc.Pattern)...)
+			}
+		}
+		switch sc.Format {
+		case "uuid":
+			if !uuidRe.MatchString(str) {
+				out = append(out, bad("uuid_parsing", "Input should be a valid UUID")...)
+			}
+		case "date-time":
+			if _, err := time.Parse(time.RFC3339, str); err != nil {
+				out = append(out, bad("datetime_parsing", "Input should be a valid datetime")...)
+			}
+		case "date":
+			if _, err := time.Parse(time.DateOnly, str); err != nil {
+				out = append(out, bad("date_parsing", "Input should be a valid date")...)
+			}
+		}
+	case "integer", "number":
+		n, ok := v.(json.Number)
+		if !ok {
+			return append(out, bad(sc.Type+"_type", "Input should be a valid %s", sc.Type)...)
+		}
+		f, err := n.Float64()
+		if err != nil || (sc.Type == "integer" && f != math.Trunc(f)) {
+			return append(out, bad(sc.Type+"_parsing", "Input should be a valid %s", sc.Type)...)
+		}
+		if sc.Minimum != nil && f < *sc.Minimum {
+			out = append(out, bad("greater_than_equal", "Input should be greater than or equal to %v", *sc.Minimum)...)
+		}
+		if sc.Maximum != nil && f > *sc.Maximum {
+			out = append(out, bad("less_than_equal", "Input should be less than or equal to %v", *sc.Maximum)...)
+		}
+		if sc.ExclusiveMinimum != nil && f <= *sc.ExclusiveMinimum {
+			out = append(out, bad("greater_than", "Input should be greater than %v", *sc.ExclusiveMinimum)...)
+		}
+		if sc.ExclusiveMaximum != nil && f >= *sc.ExclusiveMaximum {
+			out = append(out, bad("less_than", "Input should be less than %v", *sc.ExclusiveMaximum)...)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return append(out, bad("bool_type", "Input should be a valid boolean")...)
+		}
+	case "array":
+		items, ok := v.([]any)
+		if !ok {
+			return append(out, bad("list_type", "Input should be a valid list")...)
+		}
+		if sc.MinItems != nil && len(items) < *sc.MinItems {
+			out = append(out, bad("too_short", "List should have at least %d items", *sc.MinItems)...)
+		}
+		if sc.MaxItems != nil && len(items) > *sc.MaxItems {
+			out = append(out, bad("too_long", "List should have at most %d items", *sc.MaxItems)...)
+		}
+		for i, item := range items {
+			out = append(out, s.check(sc.Items, item, append(loc, strconv.Itoa(i)))...)
+		}
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return append(out, bad("model_type", "Input should be a valid dictionary or object")...)
+		}
+		for _, name := range sc.Required {
+			if _, ok := obj[name]; !ok {
+				out = append(out, Violation{Loc: append(append([]string(nil), loc...), name), Msg: "Field required", Type: "missing"})
+			}
+		}
+		var extra *schema
+		forbid := false
+		if len(sc.AdditionalProperties) > 0 {
+			var b bool
+			if json.Unmarshal(sc.AdditionalProperties, &b) == nil {
+				forbid = !b
+			} else {
+				extra = &schema{}
+				_ = json.Unmarshal(sc.AdditionalProperties, extra)
+			}
+		}
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			sub := append(append([]string(nil), loc...), k)
+			switch prop, ok := sc.Properties[k]; {
+			case ok:
+				out = append(out, s.check(prop, obj[k], sub)...)
+			case extra != nil:
+				out = append(out, s.check(extra, obj[k], sub)...)
+			case forbid:
+				out = append(out, Violation{Loc: sub, Msg: "Extra inputs are not permitted", Type: "extra_forbidden"})
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,371 @@
+package seclai
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultTimeout is the request timeout of the client NewClient builds when
+// neither Options.HTTPClient nor the profile sets one.
+const defaultTimeout = 30 * time.Second
+
+// SettingSource names the configuration layer that supplied a [Setting].
+type SettingSource string
+
+const (
+	// SourceOptions is a field of [Options].
+	SourceOptions SettingSource = "options"
+	// SourceEnv is an environment variable.
+	SourceEnv SettingSource = "env"
+	// SourceProfile is a key of the selected profile in the config file.
+	SourceProfile SettingSource = "profile"
+	// SourceDefaultProfile is a key the selected profile inherited from
+	// [default].
+	SourceDefaultProfile SettingSource = "default-profile"
+	// SourceBuiltIn is the SDK's own default.
+	SourceBuiltIn SettingSource = "built-in"
+)
+
+// Setting is one effective client setting and where it came from, as
+// reported by [Client.Settings].
+type Setting struct {
+	// Name is the setting's profile key: "profile", "base_url",
+	// "api_version", "api_key_header", "credentials", "timeout", or
+	// "default_headers.<Header-Name>" for each default header.
+	Name string
+	// Value is the effective value. For "credentials" it is the kind of
+	// credential ("api_key", "access_token", "access_token_provider",
+	// "credential_process" or "sso"), never the secret, and a default
+	// header that carries a credential is redacted.
+	Value string
+	// Source is the layer that supplied Value.
+	Source SettingSource
+	// Detail names the exact origin: the Options field, the environment
+	// variable, or the key, section and path of the config file.
+	Detail string
+}
+
+const (
+	settingProfile      = "profile"
+	settingBaseURL      = "base_url"
+	settingAPIVersion   = "api_version"
+	settingAPIKeyHeader = "api_key_header"
+	settingCredentials  = "credentials"
+	settingTimeout      = "timeout"
+	settingHeaderPrefix = "default_headers."
+)
+
+// Settings returns the client's effective settings and the layer each came
+// from, in a fixed order with default headers last, sorted by name.
+// NewClient also logs them at debug level.
+func (c *Client) Settings() []Setting {
+	if c == nil {
+		return nil
+	}
+	return append([]Setting(nil), c.settings...)
+}
+
+func optionSetting(name, value, field string) Setting {
+	return Setting{Name: name, Value: value, Source: SourceOptions, Detail: "Options." + field}
+}
+
+// resolveProfile selects the client's profile (Options.Profile, then
+// SECLAI_PROFILE, then "default") and reads it from the config directory. A
+// config file that cannot be read is logged and treated as absent, which
+// leaves the profile without credentials.
+func resolveProfile(opts Options, logger *slog.Logger) (*configProfile, Setting) {
+	name, from := opts.Profile, optionSetting(settingProfile, opts.Profile, "Profile")
+	if name == "" {
+		name = os.Getenv("SECLAI_PROFILE")
+		from = Setting{Name: settingProfile, Value: name, Source: SourceEnv, Detail: "SECLAI_PROFILE"}
+	}
+	if name == "" {
+		name = "default"
+		from = Setting{Name: settingProfile, Value: name, Source: SourceBuiltIn}
+	}
+
+	empty := &configProfile{name: name, values: map[string]string{}, inherited: map[string]bool{}}
+	configDir := resolveConfigDir(opts.ConfigDir)
+	if configDir == "" {
+		return empty, from
+	}
+	prof, err := loadConfigProfile(configDir, name, logger)
+	if err != nil {
+		logger.Warn("seclai: cannot read config file; ignoring it", slog.String("error", err.Error()))
+		return empty, from
+	}
+	return prof, from
+}
+
+// section names the profile's section as written in the config file.
+func (p *configProfile) section() string {
+	if p.name == "default" {
+		return "default"
+	}
+	return "profile " + p.name
+}
+
+// setting reports value as supplied by the profile's key.
+func (p *configProfile) setting(name, key, value string) Setting {
+	src, section := SourceProfile, p.section()
+	if p.inherited[key] {
+		src, section = SourceDefaultProfile, "default"
+	}
+	return Setting{Name: name, Value: value, Source: src, Detail: fmt.Sprintf("%s in [%s] of %s", key, section, p.path)}
+}
+
+// ssoSetting reports the profile's SSO settings as the credential.
+func (p *configProfile) ssoSetting() Setting {
+	s := Setting{Name: settingCredentials, Value: "sso", Source: SourceProfile, Detail: fmt.Sprintf("[%s] of %s", p.section(), p.path)}
+	switch {
+	case p.path == "":
+		s.Source, s.Detail = SourceBuiltIn, "built-in SSO defaults"
+	case p.missing:
+		s.Source, s.Detail = SourceDefaultProfile, "[default] of "+p.path
+	}
+	return s
+}
+
+// credentialKey returns which of api_key, api_key_file and
+// credential_process supplies the profile's credential, or "" for SSO. A
+// key set in the profile itself beats one inherited from [default], so a
+// profile with its own credential_process does not pick up a default
+// api_key, and a profile with its own SSO settings inherits none of them.
+// Two in the same section are an error.
+func (p *configProfile) credentialKey() (string, error) {
+	var own, inherited []string
+	for _, k := range []string{"api_key", "api_key_file", "credential_process"} {
+		switch {
+		case p.values[k] == "":
+		case p.inherited[k]:
+			inherited = append(inherited, k)
+		default:
+			own = append(own, k)
+		}
+	}
+	keys, section := own, p.section()
+	if len(keys) == 0 && !p.ownsSSOSettings() {
+		keys, section = inherited, "default"
+	}
+	switch len(keys) {
+	case 0:
+		return "", nil
+	case 1:
+		return keys[0], nil
+	}
+	return "", &ConfigurationError{Message: fmt.Sprintf("[%s] of %s sets more than one of %s", section, p.path, strings.Join(keys, ", "))}
+}
+
+// ownsSSOSettings reports whether the profile itself, rather than [default],
+// sets where to log in. sso_account_id is left out: it also names the account
+// for an API key.
+func (p *configProfile) ownsSSOSettings() bool {
+	for _, k := range []string{"sso_domain", "sso_client_id", "sso_region"} {
+		if p.values[k] != "" && !p.inherited[k] {
+			return true
+		}
+	}
+	return false
+}
+
+// apiKey returns the profile's api_key, or the contents of its api_key_file.
+// A relative api_key_file is relative to the config directory, and "~/" is
+// the home directory.
+func (p *configProfile) apiKey(key string) (string, error) {
+	if key == "api_key" {
+		return p.values["api_key"], nil
+	}
+	path := p.values["api_key_file"]
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", &ConfigurationError{Message: fmt.Sprintf("api_key_file %s: %v", path, err)}
+		}
+		path = filepath.Join(home, rest)
+	} else if !filepath.IsAbs(path) {
+		path = filepath.Join(p.dir, path)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return "", &ConfigurationError{Message: fmt.Sprintf("api_key_file: %v", err)}
+	}
+	apiKey := strings.TrimSpace(string(raw))
+	if apiKey == "" {
+		return "", &ConfigurationError{Message: fmt.Sprintf("api_key_file %s is empty", path)}
+	}
+	return apiKey, nil
+}
+
+// resolveAPIKeyHeader returns the header the API key is sent in.
+func resolveAPIKeyHeader(opts Options, prof *configProfile) (string, Setting) {
+	if h := strings.TrimSpace(opts.APIKeyHeader); h != "" {
+		return h, optionSetting(settingAPIKeyHeader, h, "APIKeyHeader")
+	}
+	if h := prof.values["api_key_header"]; h != "" {
+		return h, prof.setting(settingAPIKeyHeader, "api_key_header", h)
+	}
+	return "x-api-key", Setting{Name: settingAPIKeyHeader, Value: "x-api-key", Source: SourceBuiltIn}
+}
+
+// resolveBaseURL returns the API base URL.
+func resolveBaseURL(opts Options, prof *configProfile) (string, Setting) {
+	if base := strings.TrimSpace(opts.BaseURL); base != "" {
+		return base, optionSetting(settingBaseURL, base, "BaseURL")
+	}
+	if base := strings.TrimSpace(os.Getenv("SECLAI_API_URL")); base != "" {
+		return base, Setting{Name: settingBaseURL, Value: base, Source: SourceEnv, Detail: "SECLAI_API_URL"}
+	}
+	if base := prof.values["base_url"]; base != "" {
+		return base, prof.setting(settingBaseURL, "base_url", base)
+	}
+	return DefaultBaseURL, Setting{Name: settingBaseURL, Value: DefaultBaseURL, Source: SourceBuiltIn}
+}
+
+// resolveHTTPClient returns Options.HTTPClient, or a client with the
+// profile's timeout.
+func resolveHTTPClient(opts Options, prof *configProfile) (*http.Client, Setting, error) {
+	if opts.HTTPClient != nil {
+		return opts.HTTPClient, optionSetting(settingTimeout, opts.HTTPClient.Timeout.String(), "HTTPClient"), nil
+	}
+	if v := prof.values["timeout"]; v != "" {
+		d, err := parseTimeout(v)
+		if err != nil {
+			return nil, Setting{}, &ConfigurationError{Message: fmt.Sprintf("timeout: %v (%s)", err, prof.setting("", "timeout", "").Detail)}
+		}
+		return &http.Client{Timeout: d}, prof.setting(settingTimeout, "timeout", d.String()), nil
+	}
+	return &http.Client{Timeout: defaultTimeout}, Setting{Name: settingTimeout, Value: defaultTimeout.String(), Source: SourceBuiltIn}, nil
+}
+
+// parseTimeout reads a Go duration ("45s", "2m") or whole seconds ("45").
+// Zero means no timeout, as for [http.Client].
+func parseTimeout(v string) (time.Duration, error) {
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		secs, aerr := strconv.Atoi(v)
+		if aerr != nil {
+			return 0, fmt.Errorf("%q is neither a duration nor a number of seconds", v)
+		}
+		d = time.Duration(secs) * time.Second
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("%q is negative", v)
+	}
+	return d, nil
+}
+
+// parseHeaderList reads a default_headers value: comma-separated
+// Name=value pairs.
+func parseHeaderList(v string) (map[string]string, error) {
+	headers := map[string]string{}
+	for _, pair := range strings.Split(v, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%q is not Name=value", strings.TrimSpace(pair))
+		}
+		headers[name] = strings.TrimSpace(value)
+	}
+	return headers, nil
+}
+
+// headerSet is the client's default headers under construction, with the
+// setting that supplied each.
+type headerSet struct {
+	values map[string]string
+	from   map[string]Setting
+}
+
+// set adds h, overriding earlier layers. h may carry several spellings of
+// one header: keys are sorted so the survivor does not depend on Go's
+// randomised map order, and any differently-cased duplicate is dropped so
+// only one value reaches the wire.
+func (s *headerSet) set(h map[string]string, from func(name string) Setting) {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for existing := range s.values {
+			if strings.EqualFold(existing, k) && existing != k {
+				delete(s.values, existing)
+				delete(s.from, existing)
+			}
+		}
+		s.values[k] = h[k]
+		s.from[k] = from(k)
+	}
+}
+
+// resolveHeaders merges the default headers, lowest layer first: the
+// profile's api_version and default_headers, then Options.APIVersion and
+// Options.DefaultHeaders. In each layer the API version is set first so an
+// explicit header still wins.
+func resolveHeaders(opts Options, prof *configProfile) (*headerSet, error) {
+	hs := &headerSet{values: map[string]string{}, from: map[string]Setting{}}
+	if v := prof.values["api_version"]; v != "" {
+		hs.set(map[string]string{"Seclai-Version": v}, func(string) Setting {
+			return prof.setting(settingAPIVersion, "api_version", v)
+		})
+	}
+	if v := prof.values["default_headers"]; v != "" {
+		h, err := parseHeaderList(v)
+		if err != nil {
+			return nil, &ConfigurationError{Message: fmt.Sprintf("default_headers: %v (%s)", err, prof.setting("", "default_headers", "").Detail)}
+		}
+		hs.set(h, func(string) Setting { return prof.setting("", "default_headers", "") })
+	}
+	if opts.APIVersion != "" {
+		hs.set(map[string]string{"Seclai-Version": opts.APIVersion}, func(string) Setting {
+			return optionSetting(settingAPIVersion, "", "APIVersion")
+		})
+	}
+	hs.set(opts.DefaultHeaders, func(k string) Setting {
+		return optionSetting("", "", fmt.Sprintf("DefaultHeaders[%q]", k))
+	})
+	return hs, nil
+}
+
+// settings reports each default header, with Seclai-Version as api_version.
+func (s *headerSet) settings(r redactor) (version *Setting, headers []Setting) {
+	keys := make([]string, 0, len(s.values))
+	for k := range s.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		st := s.from[k]
+		st.Value = s.values[k]
+		if strings.EqualFold(k, "Seclai-Version") {
+			st.Name = settingAPIVersion
+			version = &st
+			continue
+		}
+		st.Name = settingHeaderPrefix + k
+		if r.headers[http.CanonicalHeaderKey(k)] {
+			st.Value = redacted
+		}
+		headers = append(headers, st)
+	}
+	return version, headers
+}
+
+// logSettings logs the effective settings at debug level.
+func logSettings(logger *slog.Logger, settings []Setting) {
+	for _, s := range settings {
+		logger.Debug("seclai: client setting",
+			slog.String("name", s.Name), slog.String("value", s.Value),
+			slog.String("source", string(s.Source)), slog.String("detail", s.Detail))
+	}
+}
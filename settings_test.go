@@ -0,0 +1,220 @@
+package seclai
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeConfig writes a config file to a new config directory and clears the
+// environment that would override it.
+func writeConfig(t *testing.T, config string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config"), []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SECLAI_API_KEY", "")
+	t.Setenv("SECLAI_API_URL", "")
+	t.Setenv("SECLAI_PROFILE", "")
+	return dir
+}
+
+func settingsByName(c *Client) map[string]Setting {
+	out := map[string]Setting{}
+	for _, s := range c.Settings() {
+		out[s.Name] = s
+	}
+	return out
+}
+
+func TestNewClient_ProfileSettings(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"data":[],"pagination":{"page":1,"limit":20,"total":0,"pages":0,"has_next":false,"has_prev":false}}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	dir := writeConfig(t, `[default]
+base_url = `+srv.URL+`
+api_key_header = X-Team-Key
+default_headers = X-Team=data, X-Env=prod
+
+[profile staging]
+api_key = sk-staging
+api_version = `+APIVersion20260727+`
+timeout = 45
+`)
+	c, err := NewClient(Options{ConfigDir: dir, Profile: "staging"})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := c.ListAgents(context.Background(), ListOptions{}); err != nil {
+		t.Fatalf("ListAgents: %v", err)
+	}
+	if got.Get("X-Team-Key") != "sk-staging" || got.Get("Seclai-Version") != APIVersion20260727 ||
+		got.Get("X-Team") != "data" || got.Get("X-Env") != "prod" {
+		t.Fatalf("profile settings not applied to the request: %v", got)
+	}
+
+	settings := settingsByName(c)
+	config := filepath.Join(dir, "config")
+	want := map[string]Setting{
+		"profile":                {Name: "profile", Value: "staging", Source: SourceOptions, Detail: "Options.Profile"},
+		"base_url":               {Name: "base_url", Value: srv.URL, Source: SourceDefaultProfile, Detail: "base_url in [default] of " + config},
+		"api_version":            {Name: "api_version", Value: APIVersion20260727, Source: SourceProfile, Detail: "api_version in [profile staging] of " + config},
+		"api_key_header":         {Name: "api_key_header", Value: "X-Team-Key", Source: SourceDefaultProfile, Detail: "api_key_header in [default] of " + config},
+		"credentials":            {Name: "credentials", Value: "api_key", Source: SourceProfile, Detail: "api_key in [profile staging] of " + config},
+		"timeout":                {Name: "timeout", Value: "45s", Source: SourceProfile, Detail: "timeout in [profile staging] of " + config},
+		"default_headers.X-Env":  {Name: "default_headers.X-Env", Value: "prod", Source: SourceDefaultProfile, Detail: "default_headers in [default] of " + config},
+		"default_headers.X-Team": {Name: "default_headers.X-Team", Value: "data", Source: SourceDefaultProfile, Detail: "default_headers in [default] of " + config},
+	}
+	if len(settings) != len(want) {
+		t.Errorf("expected %d settings, got %+v", len(want), c.Settings())
+	}
+	for name, w := range want {
+		if settings[name] != w {
+			t.Errorf("%s:\n got  %+v\n want %+v", name, settings[name], w)
+		}
+	}
+}
+
+func TestNewClient_OptionsAndEnvOverrideProfile(t *testing.T) {
+	dir := writeConfig(t, `[default]
+base_url = https://profile.example.com
+api_key = sk-profile
+api_key_header = X-Team-Key
+timeout = 2m
+default_headers = X-Team=data, X-Env=prod
+`)
+	t.Setenv("SECLAI_API_URL", "https://env.example.com")
+	t.Setenv("SECLAI_API_KEY", "sk-env")
+	c, err := NewClient(Options{
+		ConfigDir:      dir,
+		APIKeyHeader:   "X-Other-Key",
+		HTTPClient:     &http.Client{Timeout: time.Second},
+		DefaultHeaders: map[string]string{"x-team": "platform"},
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	settings := settingsByName(c)
+	checks := []struct {
+		name, value string
+		source      SettingSource
+		detail      string
+	}{
+		{"profile", "default", SourceBuiltIn, ""},
+		{"base_url", "https://env.example.com", SourceEnv, "SECLAI_API_URL"},
+		{"api_key_header", "X-Other-Key", SourceOptions, "Options.APIKeyHeader"},
+		{"credentials", "api_key", SourceEnv, "SECLAI_API_KEY"},
+		{"timeout", "1s", SourceOptions, "Options.HTTPClient"},
+		{"default_headers.x-team", "platform", SourceOptions, `Options.DefaultHeaders["x-team"]`},
+		{"default_headers.X-Env", "prod", SourceProfile, "default_headers in [default] of " + filepath.Join(dir, "config")},
+	}
+	for _, want := range checks {
+		s := settings[want.name]
+		if s.Value != want.value || s.Source != want.source || s.Detail != want.detail {
+			t.Errorf("%s: got %+v, want %s from %s (%s)", want.name, s, want.value, want.source, want.detail)
+		}
+	}
+	if _, ok := settings["default_headers.X-Team"]; ok {
+		t.Error("the profile's X-Team should be replaced by the option's x-team, not sent alongside it")
+	}
+}
+
+func TestNewClient_ProfileCredentials(t *testing.T) {
+	t.Run("api_key_file relative to the config directory", func(t *testing.T) {
+		dir := writeConfig(t, "[default]\napi_key_file = key.txt\n")
+		if err := os.WriteFile(filepath.Join(dir, "key.txt"), []byte("sk-file\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		c, err := NewClient(Options{ConfigDir: dir})
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+		if c.auth.mode != authModeAPIKey || c.auth.apiKey != "sk-file" {
+			t.Fatalf("expected the trimmed key from the file, got mode %v key %q", c.auth.mode, c.auth.apiKey)
+		}
+	})
+
+	t.Run("own credential beats an inherited one", func(t *testing.T) {
+		dir := writeConfig(t, "[default]\napi_key = sk-default\n\n[profile ci]\ncredential_process = /bin/creds\n")
+		c, err := NewClient(Options{ConfigDir: dir, Profile: "ci"})
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+		if c.auth.mode != authModeCredentialProcess {
+			t.Fatalf("expected the profile's own credential_process, got mode %v", c.auth.mode)
+		}
+	})
+
+	t.Run("own sso settings beat an inherited credential", func(t *testing.T) {
+		dir := writeConfig(t, "[default]\napi_key = sk-default\n\n[profile staging]\nsso_domain = auth.staging.example\nsso_client_id = staging-client\n")
+		c, err := NewClient(Options{ConfigDir: dir, Profile: "staging"})
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+		if c.auth.mode != authModeSSO || c.auth.ssoProfile.SsoDomain != "auth.staging.example" {
+			t.Fatalf("expected the profile's own SSO settings, got mode %v", c.auth.mode)
+		}
+		if s := settingsByName(c)["credentials"]; s.Value != "sso" || s.Source != SourceProfile {
+			t.Fatalf("expected sso from the profile, got %+v", s)
+		}
+	})
+
+	t.Run("sso when no credential key is set", func(t *testing.T) {
+		dir := writeConfig(t, "[default]\nsso_region = us-west-2\n")
+		c, err := NewClient(Options{ConfigDir: dir})
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+		if s := settingsByName(c)["credentials"]; s.Value != "sso" || s.Source != SourceProfile {
+			t.Fatalf("expected sso from the profile, got %+v", s)
+		}
+	})
+
+	failures := map[string]string{
+		"two credentials in one section": "[default]\napi_key = a\napi_key_file = b\n",
+		"missing api_key_file":           "[default]\napi_key_file = nope.txt\n",
+		"invalid timeout":                "[default]\napi_key = a\ntimeout = soon\n",
+		"invalid default_headers":        "[default]\napi_key = a\ndefault_headers = X-Team\n",
+		"unknown api_version":            "[default]\napi_key = a\napi_version = 2099-01-01\n",
+	}
+	for name, config := range failures {
+		t.Run(name, func(t *testing.T) {
+			dir := writeConfig(t, config)
+			_, err := NewClient(Options{ConfigDir: dir})
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if !strings.Contains(err.Error(), filepath.Join(dir, "config")) && !strings.Contains(err.Error(), "nope.txt") {
+				t.Fatalf("error should point at the config, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestNewClient_LogsSettings(t *testing.T) {
+	logger, buf := newTestLogger(slog.LevelDebug)
+	t.Setenv("SECLAI_API_URL", "")
+	if _, err := NewClient(Options{APIKey: "sk-secret", Logger: logger, DefaultHeaders: map[string]string{"Authorization": "Bearer tok"}}); err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "name=credentials value=api_key source=options detail=Options.APIKey") {
+		t.Fatalf("expected the credential source in the logs:\n%s", out)
+	}
+	if strings.Contains(out, "sk-secret") || strings.Contains(out, "Bearer tok") {
+		t.Fatalf("settings leaked a credential:\n%s", out)
+	}
+}
@@ -0,0 +1,263 @@
+// Package sse decodes Server-Sent Events streams as the WHATWG HTML
+// specification's event-stream format defines them.
+//
+// A [Decoder] reads events one at a time from a response body:
+//
+//	dec := sse.NewDecoder(resp.Body)
+//	for {
+//		ev, err := dec.Next()
+//		if err == io.EOF {
+//			break
+//		}
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(ev.Type, ev.Data)
+//	}
+//
+// It strips a leading byte order mark, accepts CR, LF and CRLF line endings,
+// tracks the id and retry fields, and bounds the size of an event so that a
+// misbehaving server cannot exhaust memory. As the specification requires,
+// an event that the stream ends before terminating with a blank line is
+// discarded, unless [Decoder.SetDispatchAtEOF] asks for it.
+package sse
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// DefaultMaxEventSize is the largest event a [Decoder] accepts unless
+// [Decoder.SetMaxEventSize] changes it: 8 MiB, counting every line of the
+// event, comments included.
+const DefaultMaxEventSize = 8 << 20
+
+// ErrEventTooLarge is returned by [Decoder.Next] when an event exceeds the
+// maximum size. The decoder cannot continue past it.
+var ErrEventTooLarge = errors.New("sse: event exceeds the maximum size")
+
+// Event is one dispatched event.
+type Event struct {
+	// Type is the event field. It is empty when the event had none, which
+	// the specification treats as "message".
+	Type string
+	// Data is the event's data lines joined with "\n".
+	Data string
+	// ID is the last event ID when the event was dispatched: the value of
+	// the most recent id field, which carries over to later events.
+	ID string
+}
+
+// Decoder reads events from an event stream.
+type Decoder struct {
+	r       *bufio.Reader
+	max     int
+	started bool
+	skipLF  bool
+	atEOF   bool
+	err     error
+
+	line  []byte
+	size  int
+	typ   string
+	data  bytes.Buffer
+	id    string
+	retry time.Duration
+}
+
+// NewDecoder returns a decoder reading from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: bufio.NewReader(r), max: DefaultMaxEventSize}
+}
+
+// SetMaxEventSize sets the largest event, in bytes, that Next accepts. n <= 0
+// restores [DefaultMaxEventSize].
+func (d *Decoder) SetMaxEventSize(n int) {
+	if n <= 0 {
+		n = DefaultMaxEventSize
+	}
+	d.max = n
+}
+
+// SetDispatchAtEOF sets whether Next dispatches an event that the stream
+// ends before terminating with a blank line, for servers that omit the last
+// one. Only the event's complete lines count: a final line the stream ends
+// without a line terminator is still discarded.
+func (d *Decoder) SetDispatchAtEOF(dispatch bool) {
+	d.atEOF = dispatch
+}
+
+// LastEventID returns the value of the most recent id field, which a client
+// reconnecting to the stream sends as the Last-Event-ID header.
+func (d *Decoder) LastEventID() string {
+	return d.id
+}
+
+// Retry returns the reconnection time from the most recent valid retry
+// field, or zero when the stream has sent none.
+func (d *Decoder) Retry() time.Duration {
+	return d.retry
+}
+
+// Next returns the next event. It returns [io.EOF] when the stream ends,
+// discarding an unterminated event unless [Decoder.SetDispatchAtEOF] is set,
+// and [ErrEventTooLarge] or the reader's
+// error otherwise. Errors are sticky: later calls return the same error.
+func (d *Decoder) Next() (Event, error) {
+	if d.err != nil {
+		return Event{}, d.err
+	}
+	ev, err := d.next()
+	if err != nil {
+		d.err = err
+	}
+	return ev, err
+}
+
+func (d *Decoder) next() (Event, error) {
+	if !d.started {
+		d.started = true
+		if err := d.skipBOM(); err != nil {
+			return Event{}, err
+		}
+	}
+	for {
+		line, err := d.readLine()
+		if err == io.EOF && d.atEOF && d.data.Len() > 0 {
+			// The next call reports the end of the stream.
+			d.err = io.EOF
+			return d.dispatch(), nil
+		}
+		if err != nil {
+			return Event{}, err
+		}
+		if len(line) == 0 {
+			d.size = 0
+			if d.data.Len() == 0 {
+				d.typ = ""
+				continue
+			}
+			return d.dispatch(), nil
+		}
+		d.processField(line)
+	}
+}
+
+// dispatch returns the pending event and resets it.
+func (d *Decoder) dispatch() Event {
+	data := d.data.Bytes()
+	ev := Event{Type: d.typ, Data: string(data[:len(data)-1]), ID: d.id}
+	d.typ = ""
+	d.data.Reset()
+	return ev
+}
+
+func (d *Decoder) processField(line []byte) {
+	if line[0] == ':' {
+		return
+	}
+	field, value := line, []byte(nil)
+	if i := bytes.IndexByte(line, ':'); i >= 0 {
+		field, value = line[:i], line[i+1:]
+		value = bytes.TrimPrefix(value, []byte(" "))
+	}
+	switch string(field) {
+	case "event":
+		d.typ = string(value)
+	case "data":
+		d.data.Write(value)
+		d.data.WriteByte('\n')
+	case "id":
+		if bytes.IndexByte(value, 0) < 0 {
+			d.id = string(value)
+		}
+	case "retry":
+		if ms, ok := parseDigits(value); ok {
+			d.retry = time.Duration(ms) * time.Millisecond
+		}
+	}
+}
+
+// parseDigits parses a retry value, which must be ASCII digits only.
+func parseDigits(b []byte) (int64, bool) {
+	if len(b) == 0 {
+		return 0, false
+	}
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+	}
+	ms, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil || ms > int64(time.Duration(1<<63-1)/time.Millisecond) {
+		return 0, false
+	}
+	return ms, true
+}
+
+// skipBOM drops a leading U+FEFF. It only waits for more bytes while those
+// read so far are a prefix of one.
+func (d *Decoder) skipBOM() error {
+	bom := []byte{0xEF, 0xBB, 0xBF}
+	for n := 1; n <= len(bom); n++ {
+		b, err := d.r.Peek(n)
+		if !bytes.Equal(b, bom[:len(b)]) {
+			return nil
+		}
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+	_, err := d.r.Discard(len(bom))
+	return err
+}
+
+// readLine returns the next line without its terminator. It never waits for
+// more input once a terminator has been seen, so a stream using bare CR line
+// endings dispatches its events as promptly as one using LF.
+func (d *Decoder) readLine() ([]byte, error) {
+	d.line = d.line[:0]
+	if d.skipLF {
+		d.skipLF = false
+		b, err := d.r.Peek(1)
+		if err != nil {
+			return nil, err
+		}
+		if b[0] == '\n' {
+			_, _ = d.r.Discard(1)
+		}
+	}
+	for {
+		chunk, err := d.r.Peek(max(1, d.r.Buffered()))
+		if len(chunk) == 0 {
+			// A line the stream ends without terminating is discarded
+			// with the event it belongs to.
+			return nil, err
+		}
+		i := bytes.IndexAny(chunk, "\r\n")
+		end := len(chunk)
+		if i >= 0 {
+			end = i
+		}
+		if d.size+len(d.line)+end > d.max {
+			return nil, fmt.Errorf("%w (%d bytes)", ErrEventTooLarge, d.max)
+		}
+		d.line = append(d.line, chunk[:end]...)
+		if i < 0 {
+			_, _ = d.r.Discard(len(chunk))
+			continue
+		}
+		d.skipLF = chunk[i] == '\r'
+		_, _ = d.r.Discard(i + 1)
+		d.size += len(d.line) + 1
+		return d.line, nil
+	}
+}
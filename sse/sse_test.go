@@ -0,0 +1,236 @@
+package sse
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+// decodeAll reads every event from r, returning the events and the error
+// that ended the stream, nil for a clean EOF.
+func decodeAll(r io.Reader, maxSize int) ([]Event, *Decoder, error) {
+	dec := NewDecoder(r)
+	dec.SetMaxEventSize(maxSize)
+	var events []Event
+	for {
+		ev, err := dec.Next()
+		if err == io.EOF {
+			return events, dec, nil
+		}
+		if err != nil {
+			return events, dec, err
+		}
+		events = append(events, ev)
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		stream string
+		want   []Event
+	}{
+		{
+			name:   "typed events",
+			stream: "event: init\ndata: {\"a\":1}\n\nevent: done\ndata: {}\n\n",
+			want:   []Event{{Type: "init", Data: `{"a":1}`}, {Type: "done", Data: "{}"}},
+		},
+		{
+			name:   "multi-line data",
+			stream: "data: one\ndata:two\ndata:  three\n\n",
+			want:   []Event{{Data: "one\ntwo\n three"}},
+		},
+		{
+			name:   "CRLF and bare CR",
+			stream: "event: a\r\ndata: 1\r\n\r\nevent: b\rdata: 2\r\r",
+			want:   []Event{{Type: "a", Data: "1"}, {Type: "b", Data: "2"}},
+		},
+		{
+			name:   "byte order mark",
+			stream: "\uFEFFdata: x\n\n",
+			want:   []Event{{Data: "x"}},
+		},
+		{
+			name:   "only one byte order mark is stripped",
+			stream: "\uFEFF\uFEFFdata: x\n\ndata: y\n\n",
+			want:   []Event{{Data: "y"}},
+		},
+		{
+			name:   "comments and unknown fields",
+			stream: ": keepalive\n\nfoo: bar\ndata: x\n:comment\n\n",
+			want:   []Event{{Data: "x"}},
+		},
+		{
+			name:   "an event without data is not dispatched",
+			stream: "event: ping\n\ndata: x\n\n",
+			want:   []Event{{Data: "x"}},
+		},
+		{
+			name:   "empty data is dispatched",
+			stream: "event: e\ndata\n\n",
+			want:   []Event{{Type: "e", Data: ""}},
+		},
+		{
+			name:   "id carries over and NUL ids are ignored",
+			stream: "id: 1\ndata: a\n\ndata: b\n\nid: 2\x00\ndata: c\n\nid\ndata: d\n\n",
+			want:   []Event{{Data: "a", ID: "1"}, {Data: "b", ID: "1"}, {Data: "c", ID: "1"}, {Data: "d"}},
+		},
+		{
+			name:   "an unterminated event is discarded",
+			stream: "data: a\n\ndata: b\n",
+			want:   []Event{{Data: "a"}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, _, err := decodeAll(strings.NewReader(tc.stream), 0)
+			if err != nil {
+				t.Fatalf("Next: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("events = %+v, want %+v", got, tc.want)
+			}
+			// Reading a byte at a time must give the same events.
+			got, _, err = decodeAll(iotest.OneByteReader(strings.NewReader(tc.stream)), 0)
+			if err != nil {
+				t.Fatalf("Next, one byte at a time: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("events one byte at a time = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDecoder_Retry(t *testing.T) {
+	_, dec, err := decodeAll(strings.NewReader("retry: 1500\n\nretry: soon\n\nretry: -1\n\nid: 7\n\n"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dec.Retry() != 1500*time.Millisecond {
+		t.Fatalf("Retry() = %v, invalid values should be ignored", dec.Retry())
+	}
+	if dec.LastEventID() != "7" {
+		t.Fatalf("LastEventID() = %q", dec.LastEventID())
+	}
+}
+
+func TestDecoder_MaxEventSize(t *testing.T) {
+	stream := "data: small\n\ndata: " + strings.Repeat("x", 100) + "\n\ndata: after\n\n"
+	got, dec, err := decodeAll(strings.NewReader(stream), 64)
+	if !errors.Is(err, ErrEventTooLarge) {
+		t.Fatalf("expected ErrEventTooLarge, got %v", err)
+	}
+	if len(got) != 1 || got[0].Data != "small" {
+		t.Fatalf("events before the limit = %+v", got)
+	}
+	if _, err := dec.Next(); !errors.Is(err, ErrEventTooLarge) {
+		t.Fatalf("the error should be sticky, got %v", err)
+	}
+
+	// Many small lines add up to the same limit.
+	stream = strings.Repeat("data: abcdefgh\n", 10) + "\n"
+	if _, _, err := decodeAll(strings.NewReader(stream), 64); !errors.Is(err, ErrEventTooLarge) {
+		t.Fatalf("expected ErrEventTooLarge for many lines, got %v", err)
+	}
+	// A stream of small events never reaches it.
+	stream = strings.Repeat("data: abcdefgh\n\n", 100)
+	if got, _, err := decodeAll(strings.NewReader(stream), 64); err != nil || len(got) != 100 {
+		t.Fatalf("got %d events, err %v", len(got), err)
+	}
+}
+
+func TestDecoder_DispatchAtEOF(t *testing.T) {
+	for _, tc := range []struct {
+		stream string
+		want   []Event
+	}{
+		{"data: a\n\nevent: done\ndata: b\n", []Event{{Data: "a"}, {Type: "done", Data: "b"}}},
+		{"event: done\r\ndata: b\r", []Event{{Type: "done", Data: "b"}}},
+		// Only the complete lines of the last event count.
+		{"data: a\ndata: b", []Event{{Data: "a"}}},
+		{"event: done\ndata: {\"x\"", nil},
+	} {
+		for _, r := range []io.Reader{strings.NewReader(tc.stream), iotest.OneByteReader(strings.NewReader(tc.stream))} {
+			dec := NewDecoder(r)
+			dec.SetDispatchAtEOF(true)
+			var got []Event
+			for {
+				ev, err := dec.Next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("%q: %v", tc.stream, err)
+				}
+				got = append(got, ev)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("%q: events = %+v, want %+v", tc.stream, got, tc.want)
+			}
+			if _, err := dec.Next(); err != io.EOF {
+				t.Fatalf("%q: expected a sticky io.EOF, got %v", tc.stream, err)
+			}
+		}
+	}
+}
+
+func TestDecoder_DoesNotWaitAfterBareCR(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	go func() { _, _ = io.WriteString(pw, "data: x\r\r") }()
+
+	got := make(chan Event, 1)
+	go func() {
+		ev, err := NewDecoder(pr).Next()
+		if err == nil {
+			got <- ev
+		}
+	}()
+	select {
+	case ev := <-got:
+		if ev.Data != "x" {
+			t.Fatalf("Data = %q", ev.Data)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the event should be dispatched without waiting for a following LF")
+	}
+}
+
+func FuzzDecoder(f *testing.F) {
+	for _, seed := range []string{
+		"event: init\ndata: {}\n\n",
+		"\uFEFFdata: a\r\ndata: b\r\n\r\n",
+		"id: 1\rretry: 10\rdata\r\r: c\n\n",
+		"data: unterminated",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, stream string) {
+		const limit = 1 << 10
+		whole, _, errWhole := decodeAll(strings.NewReader(stream), limit)
+		bytewise, _, errBytewise := decodeAll(iotest.OneByteReader(strings.NewReader(stream)), limit)
+		if !reflect.DeepEqual(whole, bytewise) || (errWhole == nil) != (errBytewise == nil) {
+			t.Fatalf("chunking changed the result: %+v (%v) vs %+v (%v)", whole, errWhole, bytewise, errBytewise)
+		}
+		if errWhole != nil && !errors.Is(errWhole, ErrEventTooLarge) {
+			t.Fatalf("unexpected error: %v", errWhole)
+		}
+		for _, ev := range whole {
+			if len(ev.Data)+len(ev.Type) > limit {
+				t.Fatalf("event over the limit: %d bytes", len(ev.Data)+len(ev.Type))
+			}
+		}
+		// LF and CRLF endings decode alike.
+		if !strings.Contains(stream, "\r") {
+			crlf, _, err := decodeAll(strings.NewReader(strings.ReplaceAll(stream, "\n", "\r\n")), 2*limit)
+			lf, _, errLF := decodeAll(strings.NewReader(stream), 2*limit)
+			if err == nil && errLF == nil && !reflect.DeepEqual(crlf, lf) {
+				t.Fatalf("CRLF changed the events: %+v vs %+v", crlf, lf)
+			}
+		}
+	})
+}
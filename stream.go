@@ -0,0 +1,246 @@
+package seclai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"iter"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/seclai/seclai-go/sse"
+)
+
+// StreamHandler receives the events of a streaming run from
+// [Client.RunStreamingAgentWithHandler], on the caller's goroutine and in
+// order. An error returned from a method stops the stream and is returned
+// as is, without calling OnError.
+type StreamHandler interface {
+	// OnInit receives the init event, the run's initial snapshot.
+	OnInit(ctx context.Context, ev *InitEvent) error
+	// OnStep receives step events, whose Payload is a *[StepEvent].
+	OnStep(ctx context.Context, evt AgentRunEvent) error
+	// OnUpdate receives every other event before done: update and status
+	// snapshots, LLM tokens, error and timeout notices, reconnections and
+	// kinds this release does not know.
+	OnUpdate(ctx context.Context, evt AgentRunEvent) error
+	// OnDone receives the done event, the run's terminal snapshot.
+	OnDone(ctx context.Context, ev *DoneEvent) error
+	// OnError receives the error that ended the stream, which is also
+	// returned.
+	OnError(ctx context.Context, err error)
+}
+
+// StreamHandlerFuncs adapts functions to a [StreamHandler]. A nil field
+// ignores its events.
+type StreamHandlerFuncs struct {
+	Init   func(ctx context.Context, ev *InitEvent) error
+	Step   func(ctx context.Context, evt AgentRunEvent) error
+	Update func(ctx context.Context, evt AgentRunEvent) error
+	Done   func(ctx context.Context, ev *DoneEvent) error
+	Error  func(ctx context.Context, err error)
+}
+
+// OnInit implements [StreamHandler].
+func (f StreamHandlerFuncs) OnInit(ctx context.Context, ev *InitEvent) error {
+	if f.Init == nil {
+		return nil
+	}
+	return f.Init(ctx, ev)
+}
+
+// OnStep implements [StreamHandler].
+func (f StreamHandlerFuncs) OnStep(ctx context.Context, evt AgentRunEvent) error {
+	if f.Step == nil {
+		return nil
+	}
+	return f.Step(ctx, evt)
+}
+
+// OnUpdate implements [StreamHandler].
+func (f StreamHandlerFuncs) OnUpdate(ctx context.Context, evt AgentRunEvent) error {
+	if f.Update == nil {
+		return nil
+	}
+	return f.Update(ctx, evt)
+}
+
+// OnDone implements [StreamHandler].
+func (f StreamHandlerFuncs) OnDone(ctx context.Context, ev *DoneEvent) error {
+	if f.Done == nil {
+		return nil
+	}
+	return f.Done(ctx, ev)
+}
+
+// OnError implements [StreamHandler].
+func (f StreamHandlerFuncs) OnError(ctx context.Context, err error) {
+	if f.Error != nil {
+		f.Error(ctx, err)
+	}
+}
+
+// RunStreamingAgentWithHandler runs an agent in priority mode like
+// [Client.RunStreamingAgent], but reads the stream on the calling goroutine
+// and hands each event to h. It returns when the stream ends, h returns an
+// error, or ctx is cancelled, with the response body closed and no
+// goroutine left behind.
+//
+// A stream that ends without a done event returns nil, as the channel API
+// does; [Options.StreamReconnect] follows the run instead.
+func (c *Client) RunStreamingAgentWithHandler(ctx context.Context, agentID string, body AgentRunStreamRequest, h StreamHandler) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var handlerErr error
+	err := c.streamAgentRun(ctx, agentID, body, func(evt AgentRunEvent) error {
+		handlerErr = dispatchStreamEvent(ctx, h, evt)
+		return handlerErr
+	})
+	if err != nil && err != handlerErr {
+		h.OnError(ctx, err)
+	}
+	return err
+}
+
+func dispatchStreamEvent(ctx context.Context, h StreamHandler, evt AgentRunEvent) error {
+	switch p := evt.Payload.(type) {
+	case *InitEvent:
+		return h.OnInit(ctx, p)
+	case *DoneEvent:
+		return h.OnDone(ctx, p)
+	case *StepEvent:
+		return h.OnStep(ctx, evt)
+	default:
+		return h.OnUpdate(ctx, evt)
+	}
+}
+
+// errStopIteration stops a stream whose iterator's loop has ended.
+var errStopIteration = errors.New("iteration stopped")
+
+// RunStreamingAgentEvents runs an agent in priority mode like
+// [Client.RunStreamingAgent], as an iterator over its events. The stream is
+// read as the loop asks for events, on the loop's goroutine; breaking out
+// of the loop closes the response body and leaves no goroutine behind. A
+// failure is yielded once, as the last element:
+//
+//	for evt, err := range client.RunStreamingAgentEvents(ctx, agentID, body) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(evt.Event, evt.Data)
+//	}
+func (c *Client) RunStreamingAgentEvents(ctx context.Context, agentID string, body AgentRunStreamRequest) iter.Seq2[AgentRunEvent, error] {
+	return func(yield func(AgentRunEvent, error) bool) {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		err := c.streamAgentRun(ctx, agentID, body, func(evt AgentRunEvent) error {
+			if !yield(evt, nil) {
+				return errStopIteration
+			}
+			return nil
+		})
+		if err != nil && err != errStopIteration {
+			yield(AgentRunEvent{}, err)
+		}
+	}
+}
+
+// streamAgentRun starts a streaming run and hands its events to emit until
+// the stream ends, following the run when [Options.StreamReconnect] is set.
+// An error from emit stops the stream and is returned.
+func (c *Client) streamAgentRun(ctx context.Context, agentID string, body AgentRunStreamRequest, emit func(AgentRunEvent) error) error {
+	resp, err := c.openAgentRunStream(ctx, agentID, body)
+	if err != nil {
+		return err
+	}
+
+	s := &runStream{c: c, agentID: agentID, emit: emit}
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "seclai: stream opened", slog.String("agent_id", agentID))
+	defer func() {
+		c.logger.LogAttrs(ctx, slog.LevelDebug, "seclai: stream closed",
+			slog.String("agent_id", agentID), slog.Int("events", s.received))
+	}()
+
+	err = s.consume(ctx, resp)
+	if c.reconnect != nil && !s.done && !s.stopped && ctx.Err() == nil && !errors.Is(err, sse.ErrEventTooLarge) {
+		err = s.recover(ctx, *c.reconnect, err)
+	}
+	return err
+}
+
+// runStream is the state of one streaming run, and of the polling that
+// follows it if the stream drops.
+type runStream struct {
+	c       *Client
+	agentID string
+	emit    func(AgentRunEvent) error
+
+	received    int
+	done        bool
+	stopped     bool
+	runID       string
+	lastEventID string
+}
+
+// consume forwards the events of one response and closes it. It returns
+// nil when the stream ends cleanly, whether or not the done event arrived.
+func (s *runStream) consume(ctx context.Context, resp *http.Response) error {
+	defer resp.Body.Close()
+
+	// If the server returns JSON instead of SSE, emit a single done event.
+	ct := resp.Header.Get("Content-Type")
+	if strings.HasPrefix(ct, "application/json") {
+		raw, _ := io.ReadAll(resp.Body)
+		evt := newAgentRunEvent(string(AgentRunEventDone), string(raw), "")
+		s.received++
+		s.c.logStreamEvent(ctx, s.agentID, evt.Event, evt.Data)
+		s.done = true
+		return s.send(evt)
+	}
+
+	dec := sse.NewDecoder(resp.Body)
+	// Keep a last event whose closing blank line the server left off.
+	dec.SetDispatchAtEOF(true)
+	for {
+		ev, err := dec.Next()
+		if id := dec.LastEventID(); id != "" {
+			s.lastEventID = id
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		s.received++
+		s.c.logStreamEvent(ctx, s.agentID, ev.Type, ev.Data)
+		if ev.Data == "" {
+			continue
+		}
+
+		evt := newAgentRunEvent(ev.Type, ev.Data, ev.ID)
+		if evt.Kind() == AgentRunEventInit && evt.Run != nil {
+			s.runID = evt.Run.RunId
+		}
+		if evt.Kind() == AgentRunEventDone {
+			s.done = true
+		}
+		if err := s.send(evt); err != nil {
+			return err
+		}
+	}
+}
+
+// send hands evt to the consumer. An error from the consumer stops the
+// stream, without reconnecting.
+func (s *runStream) send(evt AgentRunEvent) error {
+	if err := s.emit(evt); err != nil {
+		s.stopped = true
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package seclai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const handlerStream = "event: init\ndata: " + runProcessing + "\n\n" +
+	"event: status\ndata: {\"run_id\":\"run_1\",\"status\":\"processing\"}\n\n" +
+	"event: step\ndata: {\"run_id\":\"run_1\",\"agent_step_id\":\"s1\",\"status\":\"processing\"}\n\n" +
+	"event: update\ndata: " + runProcessing + "\n\n" +
+	"event: done\ndata: " + runCompleted + "\n\n"
+
+func newStreamServer(t *testing.T, stream string) *Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, stream)
+	}))
+	t.Cleanup(srv.Close)
+	c, err := NewClient(Options{APIKey: "k", BaseURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// recordingHandler records the calls it receives.
+func recordingHandler(calls *[]string) StreamHandlerFuncs {
+	return StreamHandlerFuncs{
+		Init: func(_ context.Context, ev *InitEvent) error {
+			*calls = append(*calls, "init:"+ev.RunId)
+			return nil
+		},
+		Step: func(_ context.Context, evt AgentRunEvent) error {
+			*calls = append(*calls, "step:"+evt.Event)
+			return nil
+		},
+		Update: func(_ context.Context, evt AgentRunEvent) error {
+			*calls = append(*calls, "update:"+evt.Event)
+			return nil
+		},
+		Done: func(_ context.Context, ev *DoneEvent) error {
+			*calls = append(*calls, "done:"+string(ev.Status))
+			return nil
+		},
+		Error: func(_ context.Context, err error) {
+			*calls = append(*calls, "error")
+		},
+	}
+}
+
+func TestRunStreamingAgentWithHandler_Dispatches(t *testing.T) {
+	c := newStreamServer(t, handlerStream)
+	var calls []string
+	if err := c.RunStreamingAgentWithHandler(context.Background(), "agent_1", AgentRunStreamRequest{}, recordingHandler(&calls)); err != nil {
+		t.Fatalf("RunStreamingAgentWithHandler: %v", err)
+	}
+	want := "init:run_1,update:status,step:step,update:update,done:completed"
+	if got := strings.Join(calls, ","); got != want {
+		t.Fatalf("calls = %s", got)
+	}
+}
+
+func TestRunStreamingAgentWithHandler_Errors(t *testing.T) {
+	c := newStreamServer(t, handlerStream)
+	stop := errors.New("stop")
+	var calls []string
+	h := recordingHandler(&calls)
+	h.Step = func(context.Context, AgentRunEvent) error { return stop }
+	if err := c.RunStreamingAgentWithHandler(context.Background(), "agent_1", AgentRunStreamRequest{}, h); err != stop {
+		t.Fatalf("expected the handler's error, got %v", err)
+	}
+	if got := strings.Join(calls, ","); got != "init:run_1,update:status" {
+		t.Fatalf("a handler error should stop the stream without OnError, calls = %s", got)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(srv.Close)
+	c, _ = NewClient(Options{APIKey: "k", BaseURL: srv.URL})
+	calls = nil
+	err := c.RunStreamingAgentWithHandler(context.Background(), "agent_1", AgentRunStreamRequest{}, recordingHandler(&calls))
+	var apiErr *APIStatusError
+	if !errors.As(err, &apiErr) || strings.Join(calls, ",") != "error" {
+		t.Fatalf("expected OnError and an APIStatusError, got %v and calls %v", err, calls)
+	}
+}
+
+func TestRunStreamingAgentEvents(t *testing.T) {
+	c := newStreamServer(t, handlerStream)
+	var kinds []string
+	for evt, err := range c.RunStreamingAgentEvents(context.Background(), "agent_1", AgentRunStreamRequest{}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		kinds = append(kinds, evt.Event)
+	}
+	if got := strings.Join(kinds, ","); got != "init,status,step,update,done" {
+		t.Fatalf("events = %s", got)
+	}
+}
+
+func TestRunStreamingAgentEvents_BreakClosesTheBody(t *testing.T) {
+	disconnected := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "event: init\ndata: "+runProcessing+"\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+		close(disconnected)
+	}))
+	t.Cleanup(srv.Close)
+	c, _ := NewClient(Options{APIKey: "k", BaseURL: srv.URL})
+
+	// No deadline: only closing the body can end the server's request.
+	for evt, err := range c.RunStreamingAgentEvents(context.Background(), "agent_1", AgentRunStreamRequest{}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if evt.Kind() == AgentRunEventInit {
+			break
+		}
+	}
+	select {
+	case <-disconnected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("breaking out of the loop should close the response body")
+	}
+}
+
+func TestRunStreamingAgentEvents_YieldsTheErrorLast(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(srv.Close)
+	c, _ := NewClient(Options{APIKey: "k", BaseURL: srv.URL})
+
+	var n int
+	var last error
+	for _, err := range c.RunStreamingAgentEvents(context.Background(), "agent_1", AgentRunStreamRequest{}) {
+		n++
+		last = err
+	}
+	var apiErr *APIStatusError
+	if n != 1 || !errors.As(last, &apiErr) {
+		t.Fatalf("expected one APIStatusError, got %d elements and %v", n, last)
+	}
+}
@@ -0,0 +1,246 @@
+package seclai
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// TokenStore persists SSO tokens for a profile, keyed by the profile's SSO
+// domain and client ID. Set one on [Options.TokenStore] or
+// [LoginOptions.TokenStore]; the default is a [FileTokenStore] in the config
+// directory, the plaintext cache the Seclai CLI shares.
+type TokenStore interface {
+	// Get returns the stored entry, or nil and no error when there is none.
+	Get(ctx context.Context, profile *SsoProfile) (*SsoCacheEntry, error)
+	// Put stores entry, replacing any previous one.
+	Put(ctx context.Context, profile *SsoProfile, entry *SsoCacheEntry) error
+	// Delete removes the stored entry. Deleting a missing entry is not an
+	// error.
+	Delete(ctx context.Context, profile *SsoProfile) error
+}
+
+// TokenLocker is implemented by a [TokenStore] that several processes share.
+// The client holds the lock from reading an expired token to storing its
+// replacement, so that one process refreshes and the others read the result
+// rather than each spending the same rotating refresh token.
+type TokenLocker interface {
+	// Lock blocks until the profile's lock is held and returns its release.
+	Lock(ctx context.Context, profile *SsoProfile) (unlock func(), err error)
+}
+
+// ── File store ──────────────────────────────────────────────────────────────
+
+// FileTokenStore keeps tokens as plaintext JSON under
+// <ConfigDir>/sso/cache, the format [ReadSsoCache] and [WriteSsoCache] use.
+// The zero value uses the default config dir, like NewFileTokenStore("").
+type FileTokenStore struct {
+	configDir string
+}
+
+// NewFileTokenStore returns a store in configDir. Empty uses
+// SECLAI_CONFIG_DIR, then ~/.seclai.
+func NewFileTokenStore(configDir string) *FileTokenStore {
+	return &FileTokenStore{configDir: resolveConfigDir(configDir)}
+}
+
+// Get implements [TokenStore].
+func (s *FileTokenStore) Get(_ context.Context, profile *SsoProfile) (*SsoCacheEntry, error) {
+	return ReadSsoCache(s.dir(), profile)
+}
+
+// Put implements [TokenStore]. It writes atomically but does not take the
+// lock; hold [FileTokenStore.Lock] around a read-modify-write.
+func (s *FileTokenStore) Put(_ context.Context, profile *SsoProfile, entry *SsoCacheEntry) error {
+	return writeSsoCache(s.dir(), profile, entry)
+}
+
+// Delete implements [TokenStore].
+func (s *FileTokenStore) Delete(_ context.Context, profile *SsoProfile) error {
+	return DeleteSsoCache(s.dir(), profile)
+}
+
+// Lock implements [TokenLocker] with the cache file's cross-process lock.
+func (s *FileTokenStore) Lock(ctx context.Context, profile *SsoProfile) (func(), error) {
+	return lockSsoCache(ctx, s.dir(), profile)
+}
+
+// dir returns the store's config dir, resolving the default for the zero
+// value.
+func (s *FileTokenStore) dir() string {
+	return resolveConfigDir(s.configDir)
+}
+
+// ── Memory store ────────────────────────────────────────────────────────────
+
+// MemoryTokenStore keeps tokens in process memory only, for services that
+// must not write credentials to disk. Seed it with Put, or a login helper.
+type MemoryTokenStore struct {
+	mu      sync.Mutex
+	entries map[string]SsoCacheEntry
+}
+
+// NewMemoryTokenStore returns an empty in-memory store.
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{entries: map[string]SsoCacheEntry{}}
+}
+
+// Get implements [TokenStore].
+func (s *MemoryTokenStore) Get(_ context.Context, profile *SsoProfile) (*SsoCacheEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[CacheFileName(profile.SsoDomain, profile.SsoClientID)]
+	if !ok {
+		return nil, nil
+	}
+	return &entry, nil
+}
+
+// Put implements [TokenStore].
+func (s *MemoryTokenStore) Put(_ context.Context, profile *SsoProfile, entry *SsoCacheEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[CacheFileName(profile.SsoDomain, profile.SsoClientID)] = *entry
+	return nil
+}
+
+// Delete implements [TokenStore].
+func (s *MemoryTokenStore) Delete(_ context.Context, profile *SsoProfile) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, CacheFileName(profile.SsoDomain, profile.SsoClientID))
+	return nil
+}
+
+// ── Encrypted file store ────────────────────────────────────────────────────
+
+// EncryptedFileTokenStore keeps tokens under <ConfigDir>/sso/cache like
+// [FileTokenStore], but sealed with AES-256-GCM in <hash>.enc files, so a
+// leaked file or volume does not leak refresh tokens. Processes sharing the
+// files share the same cross-process lock.
+type EncryptedFileTokenStore struct {
+	configDir string
+	aead      cipher.AEAD
+}
+
+// NewEncryptedFileTokenStore returns a store in configDir (empty uses
+// SECLAI_CONFIG_DIR, then ~/.seclai) sealed with a 32-byte key. See
+// [TokenStoreKeyFromEnv] and [TokenStoreKeyFromFile].
+func NewEncryptedFileTokenStore(configDir string, key []byte) (*EncryptedFileTokenStore, error) {
+	if len(key) != 32 {
+		return nil, &ConfigurationError{Message: fmt.Sprintf("token store key must be 32 bytes, got %d", len(key))}
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptedFileTokenStore{configDir: resolveConfigDir(configDir), aead: aead}, nil
+}
+
+// TokenStoreKeyFromEnv reads a base64-encoded 32-byte key from the named
+// environment variable.
+func TokenStoreKeyFromEnv(name string) ([]byte, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return nil, &ConfigurationError{Message: fmt.Sprintf("token store key: %s is not set", name)}
+	}
+	return decodeTokenStoreKey(v, name)
+}
+
+// TokenStoreKeyFromFile reads a base64-encoded 32-byte key from a file, such
+// as a mounted secret.
+func TokenStoreKeyFromFile(path string) ([]byte, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return decodeTokenStoreKey(string(raw), path)
+}
+
+func decodeTokenStoreKey(s, source string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, &ConfigurationError{Message: fmt.Sprintf("token store key in %s is not base64: %v", source, err)}
+	}
+	if len(key) != 32 {
+		return nil, &ConfigurationError{Message: fmt.Sprintf("token store key in %s must decode to 32 bytes, got %d", source, len(key))}
+	}
+	return key, nil
+}
+
+func (s *EncryptedFileTokenStore) path(profile *SsoProfile) string {
+	return filepath.Join(s.configDir, ssoCacheDir, CacheFileName(profile.SsoDomain, profile.SsoClientID)+".enc")
+}
+
+// Get implements [TokenStore]. A file sealed with another key is an error.
+func (s *EncryptedFileTokenStore) Get(_ context.Context, profile *SsoProfile) (*SsoCacheEntry, error) {
+	path := s.path(profile)
+	sealed, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	n := s.aead.NonceSize()
+	if len(sealed) < n {
+		return nil, fmt.Errorf("corrupt encrypted SSO cache file %s", path)
+	}
+	data, err := s.aead.Open(nil, sealed[:n], sealed[n:], []byte(filepath.Base(path)))
+	if err != nil {
+		return nil, fmt.Errorf("decrypt SSO cache file %s: wrong key or corrupt file", path)
+	}
+	var entry SsoCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("corrupt SSO cache file %s: %w", path, err)
+	}
+	return &entry, nil
+}
+
+// Put implements [TokenStore]. Like [FileTokenStore.Put] it does not take
+// the lock.
+func (s *EncryptedFileTokenStore) Put(_ context.Context, profile *SsoProfile, entry *SsoCacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	path := s.path(profile)
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	// The file name is authenticated too, so a sealed entry cannot be
+	// copied over another profile's.
+	sealed := s.aead.Seal(nonce, nonce, data, []byte(filepath.Base(path)))
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, sealed)
+}
+
+// Delete implements [TokenStore].
+func (s *EncryptedFileTokenStore) Delete(_ context.Context, profile *SsoProfile) error {
+	err := os.Remove(s.path(profile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Lock implements [TokenLocker].
+func (s *EncryptedFileTokenStore) Lock(ctx context.Context, profile *SsoProfile) (func(), error) {
+	return lockSsoCache(ctx, s.configDir, profile)
+}
@@ -0,0 +1,167 @@
+package seclai
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testTokenStore(t *testing.T, store TokenStore) {
+	t.Helper()
+	ctx := context.Background()
+	a := &SsoProfile{SsoDomain: "auth.a", SsoClientID: "c"}
+	b := &SsoProfile{SsoDomain: "auth.b", SsoClientID: "c"}
+
+	if got, err := store.Get(ctx, a); got != nil || err != nil {
+		t.Fatalf("Get on an empty store = %+v, %v", got, err)
+	}
+	want := &SsoCacheEntry{AccessToken: "at", RefreshToken: "rt", ExpiresAt: "2030-01-01T00:00:00Z", ClientID: "c"}
+	if err := store.Put(ctx, a, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.Get(ctx, a)
+	if err != nil || got == nil || *got != *want {
+		t.Fatalf("Get = %+v, %v; want %+v", got, err, want)
+	}
+	if other, _ := store.Get(ctx, b); other != nil {
+		t.Fatalf("entries should be keyed by profile, got %+v for another", other)
+	}
+	if err := store.Delete(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := store.Get(ctx, a); got != nil {
+		t.Fatalf("Get after Delete = %+v", got)
+	}
+	if err := store.Delete(ctx, a); err != nil {
+		t.Fatalf("deleting a missing entry: %v", err)
+	}
+}
+
+func TestTokenStores(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	enc, err := NewEncryptedFileTokenStore(t.TempDir(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Run("file", func(t *testing.T) { testTokenStore(t, NewFileTokenStore(t.TempDir())) })
+	t.Run("memory", func(t *testing.T) { testTokenStore(t, NewMemoryTokenStore()) })
+	t.Run("encrypted", func(t *testing.T) { testTokenStore(t, enc) })
+}
+
+func TestFileTokenStore_ZeroValueUsesTheDefaultDir(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("SECLAI_CONFIG_DIR", dir)
+	wd, _ := os.Getwd()
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+	ctx := context.Background()
+	profile := &SsoProfile{SsoDomain: "auth.example", SsoClientID: "c"}
+
+	var store FileTokenStore
+	if err := store.Put(ctx, profile, &SsoCacheEntry{AccessToken: "at"}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadSsoCache(dir, profile)
+	if err != nil || got == nil || got.AccessToken != "at" {
+		t.Fatalf("the zero value should write under SECLAI_CONFIG_DIR, got %+v, %v", got, err)
+	}
+	if _, err := os.Stat(ssoCacheDir); !os.IsNotExist(err) {
+		t.Fatalf("the zero value wrote under the working directory: %v", err)
+	}
+}
+
+func TestEncryptedFileTokenStore_NoPlaintextAndKeyChecked(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	profile := &SsoProfile{SsoDomain: "auth.example", SsoClientID: "c"}
+	store, _ := NewEncryptedFileTokenStore(dir, bytes.Repeat([]byte{1}, 32))
+	if err := store.Put(ctx, profile, &SsoCacheEntry{RefreshToken: "rt-secret"}); err != nil {
+		t.Fatal(err)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, ssoCacheDir, "*"))
+	for _, f := range files {
+		raw, _ := os.ReadFile(f)
+		if bytes.Contains(raw, []byte("rt-secret")) {
+			t.Fatalf("%s holds the refresh token in plaintext", f)
+		}
+	}
+
+	wrong, _ := NewEncryptedFileTokenStore(dir, bytes.Repeat([]byte{2}, 32))
+	if _, err := wrong.Get(ctx, profile); err == nil {
+		t.Fatal("a different key should fail to decrypt")
+	}
+	if _, err := NewEncryptedFileTokenStore(dir, []byte("short")); err == nil {
+		t.Fatal("a short key should be rejected")
+	}
+}
+
+func TestTokenStoreKeys(t *testing.T) {
+	key := bytes.Repeat([]byte{9}, 32)
+	encoded := base64.StdEncoding.EncodeToString(key)
+
+	t.Setenv("SECLAI_TOKEN_KEY", encoded)
+	if got, err := TokenStoreKeyFromEnv("SECLAI_TOKEN_KEY"); err != nil || !bytes.Equal(got, key) {
+		t.Fatalf("TokenStoreKeyFromEnv = %x, %v", got, err)
+	}
+	path := filepath.Join(t.TempDir(), "key")
+	_ = os.WriteFile(path, []byte(encoded+"\n"), 0600)
+	if got, err := TokenStoreKeyFromFile(path); err != nil || !bytes.Equal(got, key) {
+		t.Fatalf("TokenStoreKeyFromFile = %x, %v", got, err)
+	}
+	t.Setenv("SECLAI_TOKEN_KEY", base64.StdEncoding.EncodeToString([]byte("too short")))
+	if _, err := TokenStoreKeyFromEnv("SECLAI_TOKEN_KEY"); err == nil {
+		t.Fatal("a key of the wrong length should be rejected")
+	}
+	if _, err := TokenStoreKeyFromEnv("SECLAI_TOKEN_KEY_UNSET"); err == nil {
+		t.Fatal("an unset variable should be an error")
+	}
+}
+
+func TestOptionsTokenStore_RefreshesIntoTheStore(t *testing.T) {
+	profile, hc := newSsoServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"at-2","expires_in":3600}`))
+	})
+	t.Setenv("SECLAI_API_KEY", "")
+	t.Setenv("SECLAI_SSO_DOMAIN", profile.SsoDomain)
+	t.Setenv("SECLAI_SSO_CLIENT_ID", profile.SsoClientID)
+
+	var gotAuth string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(api.Close)
+
+	store := NewMemoryTokenStore()
+	_ = store.Put(context.Background(), profile, &SsoCacheEntry{
+		AccessToken:  "at-1",
+		RefreshToken: "rt-1",
+		ExpiresAt:    time.Now().Add(-time.Minute).UTC().Format(time.RFC3339),
+	})
+	dir := t.TempDir()
+	c, err := NewClient(Options{ConfigDir: dir, TokenStore: store, BaseURL: api.URL, HTTPClient: hc})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Do(context.Background(), http.MethodGet, "/me", nil, nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if gotAuth != "Bearer at-2" {
+		t.Fatalf("Authorization = %q", gotAuth)
+	}
+	if entry, _ := store.Get(context.Background(), profile); entry == nil || entry.AccessToken != "at-2" || entry.RefreshToken != "rt-1" {
+		t.Fatalf("the refreshed token should be stored, got %+v", entry)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, ssoCacheDir, "*")); len(files) != 0 {
+		t.Fatalf("a memory store should write nothing to disk, found %v", files)
+	}
+}
@@ -242,6 +242,9 @@ type AlertCommentResponse = generated.RoutersApiAlertsAlertCommentResponse
 // AlertSubscriberResponse is a subscriber to an alert.
 type AlertSubscriberResponse = generated.RoutersApiAlertsAlertSubscriberResponse
 
+// AlertHistoryEntryResponse is one status change in an alert's history.
+type AlertHistoryEntryResponse = generated.AlertHistoryEntryResponse
+
 // UnreadCountResponse is a count of unread items.
 type UnreadCountResponse = generated.UnreadCountResponse
 